@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"os/signal"
 	"strings"
@@ -11,20 +13,72 @@ import (
 	"time"
 
 	"github.com/capsaicin/scanner/internal/config"
+	"github.com/capsaicin/scanner/internal/detection"
 	"github.com/capsaicin/scanner/internal/reporting"
 	"github.com/capsaicin/scanner/internal/scanner"
+	"github.com/capsaicin/scanner/internal/transport"
 	"github.com/capsaicin/scanner/internal/ui"
+	"github.com/capsaicin/scanner/internal/version"
 )
 
 func main() {
-	ui.PrintBanner()
-
 	cfg := config.Parse()
 
+	if cfg.ShowVersion {
+		fmt.Print(version.String())
+		return
+	}
+
+	if cfg.PrintSchema {
+		fmt.Println(reporting.JSONSchema())
+		return
+	}
+
+	// With -o - or --ndjson-out - stdout carries machine-readable output
+	// only; everything for humans goes to stderr.
+	uiFile := os.Stdout
+	if cfg.OutputFile == reporting.Stdout || cfg.NDJSONOut == reporting.Stdout {
+		uiFile = os.Stderr
+		ui.SetOutput(uiFile)
+	}
+	// Escape codes would garble piped output and log files.
+	ui.SetColor(!cfg.NoColor && ui.IsTerminal(uiFile))
+	// --quiet leaves stdout to finding URLs; errors still go to stderr.
+	if cfg.Quiet {
+		ui.SetOutput(io.Discard)
+	}
+
+	ui.PrintBanner()
+
 	targets := []string{}
 	stat, _ := os.Stdin.Stat()
-	if (stat.Mode() & os.ModeCharDevice) == 0 {
-		fmt.Printf("  %sReading targets from STDIN...%s\n", "\033[2m", "\033[0m")
+	stdinPiped := (stat.Mode() & os.ModeCharDevice) == 0
+	// With -w - STDIN carries the wordlist, so targets must come from flags.
+	wordlistFromStdin := cfg.WordlistFromStdin()
+	if wordlistFromStdin {
+		if !stdinPiped {
+			fmt.Fprintln(os.Stderr, "Error: -w - reads the wordlist from STDIN, but nothing is piped in")
+			os.Exit(1)
+		}
+		if cfg.TargetsJSON == "" && cfg.TargetURL == "" {
+			fmt.Fprintln(os.Stderr, "Error: -w - reads the wordlist from STDIN, so targets cannot come from there too. Use -u or --targets-json")
+			os.Exit(1)
+		}
+	}
+	if cfg.TargetsJSON != "" {
+		specs, err := config.LoadTargetsJSON(cfg.TargetsJSON)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		cfg.TargetSpecs = make(map[string]config.TargetSpec, len(specs))
+		for _, spec := range specs {
+			targets = append(targets, spec.URL)
+			cfg.TargetSpecs[spec.URL] = spec
+		}
+		ui.PrintInfo(fmt.Sprintf("Loaded %d targets from %s", len(targets), cfg.TargetsJSON))
+	} else if stdinPiped && !wordlistFromStdin {
+		ui.PrintInfo("Reading targets from STDIN...")
 		sc := bufio.NewScanner(os.Stdin)
 		for sc.Scan() {
 			target := strings.TrimSpace(sc.Text())
@@ -32,7 +86,7 @@ func main() {
 				targets = append(targets, target)
 			}
 		}
-		fmt.Printf("  %sLoaded %d targets%s\n", "\033[2m", len(targets), "\033[0m")
+		ui.PrintInfo(fmt.Sprintf("Loaded %d targets", len(targets)))
 	} else if cfg.TargetURL != "" {
 		targets = append(targets, cfg.TargetURL)
 	} else {
@@ -45,12 +99,84 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err := scanner.ValidateBypassStrategies(cfg.BypassStrategies, cfg.NoBypassStrategies); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if _, err := detection.ParseCalibrationStrategies(cfg.CalStrategies); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if cfg.TokenCmd != "" {
+		if _, err := transport.NewTokenSource(cfg.TokenCmd, 0); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if _, err := transport.NewScope(cfg.AllowPatterns, cfg.DenyPatterns); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if _, err := transport.TLSAllowList(cfg.TLSProfile, cfg.TLSCipherSuites, cfg.TLSCurves); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	mutationModes, err := scanner.ParseMutationModes(cfg.Mutations)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+
 	// Count wordlist lines for display.
-	wordCount, _ := scanner.CountWordlist(cfg.Wordlist)
+	wordCount, err := scanner.CountWordlist(cfg.WordlistPaths(), cfg.WordlistDiff)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
 	ui.PrintConfig(cfg, len(targets), wordCount)
 
+	if cfg.Resume != "" {
+		if err := scanner.CheckCheckpoint(cfg.Resume); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if cfg.ConditionalCache != "" {
+		if err := scanner.CheckValidatorCache(cfg.ConditionalCache); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if cfg.ShowSecrets {
+		ui.PrintWarning("--show-secrets is on: raw secret values will be written to the JSON and HTML reports. Handle them as sensitive.")
+	}
+
+	if wordCount < scanner.SmallWordlistThreshold {
+		ui.PrintWarning(fmt.Sprintf("Wordlist has only %d usable entries; check the path and encoding if that's unexpected", wordCount))
+	}
+
+	if len(mutationModes) > 0 && wordCount > 0 {
+		mutatedCount, _ := scanner.CountMutatedWordlist(cfg.WordlistPaths(), cfg.WordlistDiff, mutationModes)
+		ui.PrintWarning(fmt.Sprintf("Mutations expand %d words to %d (×%.1f requests)", wordCount, mutatedCount, float64(mutatedCount)/float64(wordCount)))
+	}
+
 	engine := scanner.NewEngine(cfg)
 
+	if cfg.ReadOnly {
+		plan, err := engine.Plan(targets)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		ui.PrintPlan(plan, cfg.MaxDepth)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -69,6 +195,69 @@ func main() {
 	scanStart := time.Now()
 	runID := reporting.GenerateRunID()
 
+	// --output-dir fills in the JSON, HTML and CSV paths so the three
+	// reports share this run ID.
+	if cfg.OutputDir != "" {
+		reports, err := reporting.PrepareOutputDir(cfg.OutputDir, runID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		cfg.OutputFile, cfg.HTMLReport, cfg.CSVReport = reports.JSON, reports.HTML, reports.CSV
+		fmt.Fprintf(ui.Output(), "  Writing reports to %s\n\n", reports.Dir)
+	}
+
+	// Report writers receive results as the scan produces them.
+	type namedSink struct {
+		label    string
+		filename string
+		sink     reporting.Sink
+	}
+	var sinks []namedSink
+	var jsonSink *reporting.JSONSink
+	if cfg.OutputFile != "" {
+		jsonSink = reporting.NewJSONSink(cfg.OutputFile, targets, runID, scanStart)
+		jsonSink.SetConfig(cfg)
+		sinks = append(sinks, namedSink{"JSON report", cfg.OutputFile, jsonSink})
+	}
+	if cfg.HTMLReport != "" {
+		sinks = append(sinks, namedSink{"HTML report", cfg.HTMLReport, reporting.NewHTMLSink(cfg.HTMLReport)})
+	}
+	if cfg.YAMLReport != "" {
+		sinks = append(sinks, namedSink{"YAML report", cfg.YAMLReport, reporting.NewYAMLSink(cfg.YAMLReport)})
+	}
+	if cfg.CSVReport != "" {
+		sinks = append(sinks, namedSink{"CSV report", cfg.CSVReport, reporting.NewCSVSink(cfg.CSVReport)})
+	}
+	if cfg.SARIFReport != "" {
+		sinks = append(sinks, namedSink{"SARIF report", cfg.SARIFReport, reporting.NewSARIFSink(cfg.SARIFReport)})
+	}
+	if cfg.NDJSONOut != "" {
+		sinks = append(sinks, namedSink{"NDJSON stream", cfg.NDJSONOut, reporting.NewNDJSONSink(cfg.NDJSONOut)})
+	}
+
+	// Sinks that save the full result set on Close get the report filters;
+	// the NDJSON stream writes each result unfiltered.
+	var filters []reporting.ResultFilter
+	if cfg.NormalizeSlash {
+		filters = append(filters, reporting.NormalizeTrailingSlash)
+	}
+	if cfg.ExcludeLengthAuto > 0 {
+		filters = append(filters, engine.DropAutoFiltered)
+	}
+	for _, ns := range sinks {
+		if fs, ok := ns.sink.(reporting.FilteredSink); ok {
+			for _, f := range filters {
+				fs.AddFilter(f)
+			}
+		}
+		if err := ns.sink.Open(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		engine.AddSink(ns.sink)
+	}
+
 	// Event channel for live UI updates.
 	eventCh := make(chan scanner.ScanEvent, cfg.Threads*4)
 
@@ -80,6 +269,36 @@ func main() {
 
 	resultCh := make(chan scanResult, 1)
 
+	// --status-addr serves progress and results until the scan ends.
+	statusCtx, statusCancel := context.WithCancel(ctx)
+	defer statusCancel()
+	var statusDone <-chan struct{}
+	if cfg.StatusAddr != "" {
+		if reporting.ListensOnAllInterfaces(cfg.StatusAddr) {
+			ui.PrintWarning(fmt.Sprintf("--status-addr %s listens on all interfaces and /results can include secrets; bind to 127.0.0.1 unless the network is trusted", cfg.StatusAddr))
+		}
+		addr, done, err := reporting.StartStatusServer(statusCtx, cfg.StatusAddr, engine)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: cannot start status server on %s: %s\n", cfg.StatusAddr, err)
+			os.Exit(1)
+		}
+		statusDone = done
+		fmt.Fprintf(ui.Output(), "  Status server listening on http://%s (/status, /results)\n\n", addr)
+	}
+	var metricsDone <-chan struct{}
+	if cfg.MetricsAddr != "" {
+		if reporting.ListensOnAllInterfaces(cfg.MetricsAddr) {
+			ui.PrintWarning(fmt.Sprintf("--metrics-addr %s listens on all interfaces; bind to 127.0.0.1 unless the network is trusted", cfg.MetricsAddr))
+		}
+		addr, done, err := reporting.StartMetricsServer(statusCtx, cfg.MetricsAddr, engine)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: cannot start metrics server on %s: %s\n", cfg.MetricsAddr, err)
+			os.Exit(1)
+		}
+		metricsDone = done
+		fmt.Fprintf(ui.Output(), "  Metrics server listening on http://%s/metrics\n\n", addr)
+	}
+
 	go func() {
 		res, st, err := engine.RunWithEvents(ctx, targets, eventCh)
 		resultCh <- scanResult{results: res, stats: st, err: err}
@@ -91,19 +310,54 @@ func main() {
 		fmt.Fprintln(os.Stderr, "  [!] Scan cancelled before initialization")
 		os.Exit(0)
 	}
+	if skipped, changed := stats.Resumed(); changed {
+		ui.PrintWarning(fmt.Sprintf("Wordlist changed since %s was written; resuming anyway and skipping the %d completed tasks it still matches", cfg.Resume, skipped))
+	} else if skipped > 0 {
+		ui.PrintWarning(fmt.Sprintf("Resuming from %s: skipping %d completed tasks", cfg.Resume, skipped))
+	}
 	uiCtx, uiCancel := context.WithCancel(ctx)
 	uiDone := make(chan struct{})
 	go func() {
-		ui.StartLiveUI(stats, eventCh, uiCtx)
+		if cfg.Quiet {
+			ui.PrintQuietResults(os.Stdout, eventCh)
+		} else {
+			ui.StartLiveUI(stats, eventCh, uiCtx, cfg.LiveRecent, time.Duration(cfg.LiveInterval)*time.Millisecond)
+		}
 		close(uiDone)
 	}()
 
+	// Keep the HTML report current while the scan runs.
+	liveCtx, liveCancel := context.WithCancel(ctx)
+	liveDone := make(chan struct{})
+	go func() {
+		defer close(liveDone)
+		if cfg.HTMLLive {
+			snapshot := engine.Results
+			if cfg.NormalizeSlash {
+				snapshot = func() []scanner.Result { return reporting.NormalizeTrailingSlash(engine.Results()) }
+			}
+			reporting.RunLiveHTML(liveCtx, cfg.HTMLReport, reporting.LiveHTMLInterval, snapshot)
+		}
+	}()
+
 	// Wait for scan to complete.
 	sr := <-resultCh
 	uiCancel()
 	<-uiDone // wait for UI to finish
+	liveCancel()
+	<-liveDone // the final report below must not be overwritten
+	statusCancel()
+	if statusDone != nil {
+		<-statusDone
+	}
+	if metricsDone != nil {
+		<-metricsDone
+	}
 
 	results := sr.results
+	if cfg.NormalizeSlash {
+		results = reporting.NormalizeTrailingSlash(results)
+	}
 
 	if sr.err != nil {
 		if ctx.Err() != nil {
@@ -118,22 +372,79 @@ func main() {
 		os.Exit(1)
 	}
 
-	ui.PrintSummary(stats)
+	ui.PrintSummary(stats, results)
 
-	if cfg.OutputFile != "" {
-		scanDuration := time.Since(scanStart)
-		if err := reporting.SaveJSONReport(results, cfg.OutputFile, targets, runID, scanStart, scanDuration); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to save JSON: %s\n", err)
+	if cfg.Tree {
+		ui.PrintTree(reporting.BuildTree(results))
+	}
+
+	if cfg.AnalyzeDupes {
+		ui.PrintBodyClusters(reporting.GroupByBodyHash(results), 5)
+	}
+
+	if targetErrors := stats.GetTargetErrors(); len(targetErrors) > 0 && jsonSink != nil {
+		jsonSink.SetTargetErrors(targetErrors)
+	}
+	if secretTypes := stats.GetSecretTypeCounts(); len(secretTypes) > 0 && jsonSink != nil {
+		jsonSink.SetSecretTypes(secretTypes)
+	}
+	if abandoned := stats.GetAbandonedHosts(); len(abandoned) > 0 && jsonSink != nil {
+		jsonSink.SetAbandonedHosts(abandoned)
+	}
+	if wafHosts := stats.GetWAFHosts(); len(wafHosts) > 0 && jsonSink != nil {
+		jsonSink.SetWAFHosts(wafHosts)
+	}
+	if targetHosts := stats.GetTargetHosts(); len(targetHosts) > 0 && jsonSink != nil {
+		jsonSink.SetTargetHosts(targetHosts)
+	}
+	if autoSizes := stats.GetAutoFilteredSizes(); len(autoSizes) > 0 && jsonSink != nil {
+		jsonSink.SetAutoFilteredSizes(autoSizes)
+	}
+
+	if reason := stats.GetStopReason(); reason != "" {
+		ui.PrintWarning("Scan stopped early: " + reason + "; reports are partial")
+		if jsonSink != nil {
+			jsonSink.SetStopReason(reason)
+		}
+	}
+
+	for _, ns := range sinks {
+		if err := ns.sink.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to save %s: %s\n", ns.label, err)
+		} else if ns.filename == reporting.Stdout {
+			fmt.Fprintf(ui.Output(), "  %s written to stdout\n", ns.label)
 		} else {
-			fmt.Printf("  JSON report saved: %s\n", cfg.OutputFile)
+			fmt.Fprintf(ui.Output(), "  %s saved: %s\n", ns.label, ns.filename)
 		}
 	}
 
-	if cfg.HTMLReport != "" {
-		if err := reporting.GenerateHTML(results, cfg.HTMLReport); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to generate HTML: %s\n", err)
+	if err := engine.SaveConditionalCache(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save conditional cache %s: %s\n", cfg.ConditionalCache, err)
+	}
+
+	monitorChanged := false
+	if cfg.MonitorDir != "" {
+		if stats.GetStopReason() != "" || ctx.Err() != nil {
+			ui.PrintWarning("Monitor state not updated: the scan did not complete")
 		} else {
-			fmt.Printf("  HTML report saved: %s\n", cfg.HTMLReport)
+			// An abandoned host's findings are incomplete; keep its old state.
+			monitorTargets := targets
+			if abandoned := stats.GetAbandonedHosts(); len(abandoned) > 0 {
+				monitorTargets = nil
+				for _, target := range targets {
+					if u, err := url.Parse(target); err == nil && abandoned[u.Host] > 0 {
+						continue
+					}
+					monitorTargets = append(monitorTargets, target)
+				}
+			}
+			m, err := reporting.RunMonitor(cfg.MonitorDir, monitorTargets, results, runID, scanStart, time.Since(scanStart), cfg.WordlistDiff != "")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to update monitor state: %s\n", err)
+				os.Exit(scanner.ExitScanError)
+			}
+			ui.PrintMonitorDiff(m)
+			monitorChanged = !m.Diff.Empty()
 		}
 	}
 
@@ -144,4 +455,8 @@ func main() {
 			os.Exit(exitCode)
 		}
 	}
+
+	if monitorChanged {
+		os.Exit(scanner.ExitChangesFound)
+	}
 }
@@ -2,4 +2,9 @@ module github.com/capsaicin/scanner
 
 go 1.21
 
-require golang.org/x/time v0.5.0
+require (
+	github.com/andybalholm/brotli v1.1.1
+	golang.org/x/net v0.21.0
+	golang.org/x/time v0.5.0
+	gopkg.in/yaml.v3 v3.0.1
+)
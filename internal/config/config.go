@@ -1,35 +1,139 @@
 package config
 
 import (
+	"crypto/tls"
 	"flag"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
+	"path"
+	"runtime"
 	"strconv"
 	"strings"
 )
 
 type Config struct {
-	TargetURL     string
-	Wordlist      string
-	Threads       int
-	Extensions    []string
-	Timeout       int
-	OutputFile    string
-	HTMLReport    string
-	Verbose       bool
-	MaxDepth      int
-	CustomHeaders map[string]string
-	RateLimit     int
-	MaxResponseMB int
-	RetryAttempts int
-	LogLevel      string
-	DryRun        bool
-	AllowPatterns []string
-	DenyPatterns  []string
-	SafeMode      bool
-	FailOn        string
+	TargetURL          string
+	Wordlist           string
+	Wordlists          []string
+	Threads            int
+	Extensions         []string
+	Timeout            int
+	OutputFile         string
+	HTMLReport         string
+	Verbose            bool
+	MaxDepth           int
+	CustomHeaders      map[string]string
+	RateLimit          int
+	MaxResponseMB      int
+	RetryAttempts      int
+	LogLevel           string
+	DryRun             bool
+	AllowPatterns      []string
+	DenyPatterns       []string
+	SafeMode           bool
+	FailOn             string
+	DefaultCreds       bool
+	BypassConcurrency  int
+	BypassStrategies   []string
+	NoBypassStrategies []string
+	UnixSocket         string
+	ConnectTo          []string
+	PrintSchema        bool
+	ShowVersion        bool
+	Evasion            string
+	BodyTimeout        int
+	Mutations          []string
+	TLSCipherSuites    []string
+	TLSCurves          []string
+	TLSProfile         string
+	ConfirmFindings    bool
+	OnlySecrets        bool
+	SeverityMap        []string
+	HTMLLive           bool
+	InsecureDowngrade  bool
+	MaxFindings        int
+	SNI                string
+	AnalyzeDupes       bool
+	AdaptiveTimeout    bool
+	ShowSecrets        bool
+	MonitorDir         string
+	MaxHeaderKB        int
+	MaxHeaders         int
+	TargetConcurrency  int
+	Tree               bool
+	CalStrategies      []string
+	ParamsWordlist     string
+	LiveRecent         int
+	LiveInterval       int
+	WordlistDiff       string
+	HostErrorBudget    int
+	TokenCmd           string
+	TokenRefresh       int
+	NormalizeSlash     bool
+	ThreadsAuto        bool
+	WAFAdaptive        bool
+	WAFRate            int
+	YAMLReport         string
+	ActionableOnly     bool
+	CacheProbe         bool
+	Resolve            bool
+	ReadOnly           bool
+	Proxy              string
+	Insecure           bool
+	TLSMinVersion      string
+	CSVReport          string
+	SARIFReport        string
+	Resume             string
+	NDJSONOut          string
+	DelayMin           int
+	DelayMax           int
+	StatusAddr         string
+	Method             string
+	Methods            []string
+	Data               string
+	DataFile           string
+	ContentType        string
+	Soft404Similarity  float64
+	UserAgent          string
+	UserAgentsFile     string
+	UserAgents         []string
+	MatchCodes         []string
+	FilterCodes        []string
+	FilterSizes        []string
+	FilterWords        []string
+	Robots             bool
+	FollowRedirects    bool
+	MaxRedirects       int
+	TargetsJSON        string
+	TargetSpecs        map[string]TargetSpec
+	Seed               int64
+	ExcludeLengthAuto  int
+	RateLimitGlobal    int
+	MetricsAddr        string
+	BackupProbe        bool
+	BackupPatterns     []string
+	NoColor            bool
+	Quiet              bool
+	ThreadsPerHost     int
+	HTTP2              bool
+	HTTP1              bool
+	OutputDir          string
+	RecurseOn          []string
+	RecurseExclude     []string
+	ExtMode            string
+	InterestingTypes   []string
+	GitCheck           bool
+	ConditionalCache   string
+	Cookies            string
+	UseCookieJar       bool
 }
 
+// validSeverities lists the severity names accepted by --fail-on and
+// --severity-map.
+var validSeverities = map[string]bool{"critical": true, "high": true, "medium": true, "low": true, "info": true}
+
 type headerFlags []string
 
 func (h *headerFlags) String() string {
@@ -52,6 +156,168 @@ func (s *stringSliceFlag) Set(value string) error {
 	return nil
 }
 
+// threadsFlag accepts a thread count or "auto"; auto is resolved by Validate
+// once the number of targets is known.
+type threadsFlag struct {
+	n    *int
+	auto *bool
+}
+
+func (f threadsFlag) String() string {
+	if f.auto != nil && *f.auto {
+		return "auto"
+	}
+	if f.n == nil {
+		return ""
+	}
+	return strconv.Itoa(*f.n)
+}
+
+func (f threadsFlag) Set(value string) error {
+	if value == "auto" {
+		*f.n, *f.auto = 0, true
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("must be a number or \"auto\"")
+	}
+	*f.n, *f.auto = n, false
+	return nil
+}
+
+// StdinWordlist is the -w value that reads the wordlist from STDIN.
+const StdinWordlist = "-"
+
+// WordlistFromStdin reports whether one of the -w wordlists is STDIN, which
+// then cannot also supply the targets.
+func (c Config) WordlistFromStdin() bool {
+	for _, path := range c.WordlistPaths() {
+		if path == StdinWordlist {
+			return true
+		}
+	}
+	return false
+}
+
+// WordlistPaths returns every -w wordlist in the order given. Wordlist is
+// the first of them; a Config built without Wordlists scans Wordlist alone.
+func (c Config) WordlistPaths() []string {
+	if len(c.Wordlists) > 0 {
+		return c.Wordlists
+	}
+	if c.Wordlist == "" {
+		return nil
+	}
+	return []string{c.Wordlist}
+}
+
+// RequestMethods returns the HTTP methods each wordlist entry is requested
+// with, in order. Method is the first of them; a Config built without
+// Methods uses Method alone, and GET when that is empty too.
+func (c Config) RequestMethods() []string {
+	if len(c.Methods) > 0 {
+		return c.Methods
+	}
+	if c.Method == "" {
+		return []string{"GET"}
+	}
+	return []string{c.Method}
+}
+
+// readUserAgents loads a --user-agents-file: one User-Agent per line, with
+// blank lines and lines starting with # skipped.
+func readUserAgents(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var agents []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		agents = append(agents, line)
+	}
+	return agents, nil
+}
+
+// validMethod reports whether method is an HTTP token (RFC 9110) that
+// net/http will send.
+func validMethod(method string) bool {
+	if method == "" {
+		return false
+	}
+	for _, r := range method {
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("!#$%&'*+-.^_`|~", r) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// checkReadOnly rejects flags that would send the target anything other
+// than a plain GET for a wordlist path. --read-only is a compliance
+// guardrail, so a conflicting flag is an error rather than being quietly
+// switched off.
+func checkReadOnly(config *Config) error {
+	if config.CacheProbe {
+		return fmt.Errorf("--read-only forbids --cache-probe: it injects X-Forwarded-Host/X-Host and can poison a shared cache")
+	}
+	if len(config.BypassStrategies) > 0 {
+		return fmt.Errorf("--read-only forbids --bypass-strategies: read-only mode makes no bypass attempts")
+	}
+	for _, mode := range config.Mutations {
+		if mode == "backup" {
+			return fmt.Errorf("--read-only forbids --mutations backup: read-only mode does no backup-file probing")
+		}
+	}
+	if config.Data != "" {
+		return fmt.Errorf("--read-only forbids --data and --data-file: read-only mode sends plain GET requests")
+	}
+	for _, method := range config.RequestMethods() {
+		if method != "GET" {
+			return fmt.Errorf("--read-only forbids --method %s: read-only mode sends GET requests only", method)
+		}
+	}
+	if config.BackupProbe {
+		return fmt.Errorf("--read-only forbids --backup-probe: read-only mode does no backup-file probing")
+	}
+	if config.Robots {
+		return fmt.Errorf("--read-only forbids --robots: its paths come from the target and cannot be listed in the request plan")
+	}
+	for _, strategy := range config.CalStrategies {
+		if strategy == "random-method" {
+			return fmt.Errorf("--read-only forbids --cal-strategy random-method: it calibrates with POST requests")
+		}
+	}
+	return nil
+}
+
+// Bounds for -t auto. The cap keeps a large target list from exhausting
+// file descriptors under the common 1024 ulimit.
+const (
+	AutoThreadsMin = 10
+	AutoThreadsMax = 200
+)
+
+// AutoThreads picks a thread count for -t auto: 10 per CPU plus 5 per
+// target, clamped to [AutoThreadsMin, AutoThreadsMax]. Requests are
+// network-bound, so threads scale past the CPU count, and more targets
+// spread the load over more servers.
+func AutoThreads(cpus, targets int) int {
+	n := 10*cpus + 5*targets
+	if n < AutoThreadsMin {
+		return AutoThreadsMin
+	}
+	if n > AutoThreadsMax {
+		return AutoThreadsMax
+	}
+	return n
+}
+
 func envOrDefault(envKey string, defaultVal int) int {
 	if val := os.Getenv(envKey); val != "" {
 		if n, err := strconv.Atoi(val); err == nil {
@@ -76,49 +342,238 @@ func Parse() Config {
 	var headers headerFlags
 	var allowPatterns stringSliceFlag
 	var denyPatterns stringSliceFlag
+	var connectTo stringSliceFlag
+	var wordlists stringSliceFlag
 
 	flag.StringVar(&config.TargetURL, "u", "", "Target URL (or use STDIN for multiple targets)")
-	flag.StringVar(&config.Wordlist, "w", "", "Wordlist path (required)")
-	flag.IntVar(&config.Threads, "t", envOrDefault("CAPSAICIN_THREADS", 50), "Number of concurrent threads")
+	flag.StringVar(&config.TargetsJSON, "targets-json", "", "JSON-lines file of targets, each with optional headers and wordlist")
+	flag.Var(&wordlists, "w", "Wordlist path, or - for STDIN (required; repeat to merge several)")
+	flag.StringVar(&config.WordlistDiff, "wordlist-diff", "", "Previous wordlist; only scan -w entries that are not in it")
+	flag.StringVar(&config.ParamsWordlist, "params", "", "Parameter-name wordlist; tries each word as a query parameter instead of a path")
+	config.Threads = envOrDefault("CAPSAICIN_THREADS", 50)
+	config.ThreadsAuto = os.Getenv("CAPSAICIN_THREADS") == "auto"
+	flag.Var(threadsFlag{&config.Threads, &config.ThreadsAuto}, "t", "Number of concurrent threads, or \"auto\" to size from CPUs and targets")
 	extensions := flag.String("x", "", "Extensions (comma-separated, e.g., php,html,txt)")
+	flag.StringVar(&config.ExtMode, "ext-mode", "suffix", "How words expand: suffix (word, word.ext), prefix (word, .word) or both")
 	flag.IntVar(&config.Timeout, "timeout", envOrDefault("CAPSAICIN_TIMEOUT", 10), "Request timeout in seconds")
 	flag.StringVar(&config.OutputFile, "o", "", "Output file (JSON format)")
 	flag.StringVar(&config.HTMLReport, "html", "", "Generate HTML report")
+	flag.StringVar(&config.YAMLReport, "yaml", "", "Write findings as a YAML document (stable output for diffing in git)")
+	flag.StringVar(&config.CSVReport, "csv", "", "Write findings as a CSV table (one row per result)")
+	flag.StringVar(&config.OutputDir, "output-dir", "", "Write report.json, report.html and report.csv into a per-run subdirectory of this directory")
+	flag.StringVar(&config.SARIFReport, "sarif", "", "Write findings as a SARIF 2.1.0 log (GitHub code scanning)")
+	flag.StringVar(&config.NDJSONOut, "ndjson-out", "", "Stream each finding as a JSON line while scanning (- for stdout)")
+	flag.StringVar(&config.StatusAddr, "status-addr", "", "Serve live scan status and results as JSON on this address (e.g. 127.0.0.1:8080)")
+	flag.StringVar(&config.MetricsAddr, "metrics-addr", "", "Serve scan metrics in Prometheus format at /metrics on this address (e.g. 127.0.0.1:9090)")
+	flag.BoolVar(&config.HTMLLive, "html-live", false, "Keep the --html report updated during the scan (auto-refreshing page)")
 	flag.BoolVar(&config.Verbose, "v", false, "Verbose mode")
 	flag.IntVar(&config.MaxDepth, "depth", 0, "Recursive scanning depth (0=disabled)")
+	recurseOn := flag.String("recurse-on", "", "Only recurse into directories that answered with these status codes (comma-separated, e.g. 200,403)")
+	recurseExclude := flag.String("recurse-exclude", "", "Never recurse into directories with these names (comma-separated, * wildcards allowed, e.g. static,assets,img*)")
+	flag.Int64Var(&config.Seed, "seed", 0, "Seed for User-Agent rotation, delays, jitter and calibration names, for reproducible scans (0=random)")
+	flag.BoolVar(&config.FollowRedirects, "follow-redirects", false, "Follow redirects and record the chain instead of reporting the 3xx")
+	flag.IntVar(&config.MaxRedirects, "max-redirects", 5, "Max redirects followed per request under --follow-redirects")
 	flag.Var(&headers, "H", "Custom header (can be used multiple times)")
+	flag.StringVar(&config.Cookies, "cookies", "", "Cookies to send on every request (e.g. \"session=abc; theme=dark\")")
+	flag.BoolVar(&config.UseCookieJar, "use-cookie-jar", false, "Store cookies the target sets and send them back on later requests to the same host")
 	flag.IntVar(&config.RateLimit, "rate-limit", envOrDefault("CAPSAICIN_RATE_LIMIT", 0), "Max requests per second per host (0=unlimited)")
+	flag.IntVar(&config.RateLimitGlobal, "rate-limit-global", 0, "Max requests per second across all hosts (0=unlimited)")
+	flag.BoolVar(&config.AdaptiveTimeout, "adaptive-timeout", false, "Derive per-host timeouts from calibration and observed p95 latency (capped by --timeout)")
+	flag.IntVar(&config.DelayMin, "delay-min", 0, "Min random delay in ms each worker waits before a request")
+	flag.IntVar(&config.DelayMax, "delay-max", 0, "Max random delay in ms each worker waits before a request (0=same as --delay-min)")
+	flag.IntVar(&config.BodyTimeout, "body-timeout", 0, "Max seconds to read a response body after headers (0=use --timeout only)")
+	flag.IntVar(&config.HostErrorBudget, "host-error-budget", 0, "Abandon a host after N failed requests and skip its remaining paths (0=never)")
+	flag.BoolVar(&config.WAFAdaptive, "waf-adaptive", false, "Slow down and skip bypass attempts on hosts where a WAF is detected")
+	flag.IntVar(&config.WAFRate, "waf-rate", 2, "Requests per second per host once --waf-adaptive detects a WAF")
+	flag.IntVar(&config.ThreadsPerHost, "threads-per-host", 0, "Max requests in flight to any one host (0=unlimited)")
+	flag.IntVar(&config.TargetConcurrency, "target-concurrency", 0, "Max targets scanned at once; their paths share the worker pool (0=all)")
 	flag.IntVar(&config.MaxResponseMB, "max-response-mb", 10, "Max response body size in MB")
+	flag.IntVar(&config.MaxHeaderKB, "max-header-kb", 256, "Max response header block size in KB; larger responses fail")
+	flag.IntVar(&config.MaxHeaders, "max-headers", 200, "Max response header lines kept per response; extras are dropped")
 	flag.IntVar(&config.RetryAttempts, "retries", 2, "Number of retry attempts for failed requests")
 	flag.StringVar(&config.LogLevel, "log-level", envOrDefaultStr("CAPSAICIN_LOG_LEVEL", "info"), "Log level (debug|info|warn|error)")
 	flag.BoolVar(&config.DryRun, "dry-run", false, "Show what would be scanned without scanning")
 	flag.Var(&allowPatterns, "allow", "Allow domain pattern (repeatable)")
 	flag.Var(&denyPatterns, "deny", "Deny domain pattern (repeatable)")
+	flag.StringVar(&config.Resume, "resume", "", "Checkpoint file: skip tasks it records as done and record new ones (removed when the scan completes)")
+	flag.StringVar(&config.ConditionalCache, "conditional-cache", "", "Keep findings' ETag and Last-Modified in this file and send them as If-None-Match/If-Modified-Since on the next scan")
+	flag.IntVar(&config.MaxFindings, "max-findings", 0, "Stop the scan after N findings (0=unlimited)")
+	flag.BoolVar(&config.ConfirmFindings, "confirm-findings", false, "Re-request each finding once and drop it if the status changes")
+	flag.BoolVar(&config.ShowSecrets, "show-secrets", false, "Include raw, unredacted secret values in results (sensitive!)")
+	flag.BoolVar(&config.OnlySecrets, "only-secrets", false, "Only report results whose body contains a secret")
+	flag.BoolVar(&config.ActionableOnly, "actionable-only", false, "Drop informational findings; report only secrets, bypasses, default creds and medium+ severity")
+	flag.StringVar(&config.Method, "method", "GET", "HTTP method for the primary request to each path")
+	methods := flag.String("methods", "", "HTTP methods to request each path with (comma-separated, e.g. GET,POST)")
+	flag.StringVar(&config.Data, "data", "", "Request body sent with each path (implies POST unless --methods is set)")
+	flag.StringVar(&config.DataFile, "data-file", "", "Read the request body from a file (implies POST unless --methods is set)")
+	flag.StringVar(&config.ContentType, "content-type", "", "Content-Type of the request body (default: application/x-www-form-urlencoded)")
+	flag.StringVar(&config.UserAgent, "user-agent", "", "Send this User-Agent with every request instead of rotating browser strings")
+	flag.StringVar(&config.UserAgentsFile, "user-agents-file", "", "Rotate through the User-Agents in this file, one per line")
 	flag.BoolVar(&config.SafeMode, "safe-mode", false, "Disable bypass attempts and aggressive techniques")
+	flag.BoolVar(&config.ReadOnly, "read-only", false, "Send plain GET requests only and print every URL before scanning (implies --safe-mode)")
 	flag.StringVar(&config.FailOn, "fail-on", "", "Exit with code 2 if findings meet severity threshold (critical|high|medium|low|info)")
+	matchCodes := flag.String("mc", "", "Only report these status codes (comma-separated, e.g. 200,301,403)")
+	filterCodes := flag.String("fc", "", "Report every status code except these (comma-separated, e.g. 404,500)")
+	filterSizes := flag.String("fs", "", "Drop responses of these body sizes in bytes (comma-separated sizes or ranges, e.g. 1024,100-200)")
+	filterWords := flag.String("fw", "", "Drop responses with these word counts (comma-separated counts or ranges, e.g. 50,10-)")
+	interestingTypes := flag.String("interesting-types", "", "Always report responses of these content types, even when calibration calls them noise (comma-separated, e.g. application/json,text/x-*)")
+	severityMap := flag.String("severity-map", "", "Override severity per status code (e.g. 403=high,500=medium,200=low)")
+	flag.IntVar(&config.BypassConcurrency, "bypass-concurrency", 1, "Max bypass strategies in flight per 403/401 (1=sequential)")
+	mutations := flag.String("mutations", "", "Wordlist mutations (comma-separated: case,leet,slash,affix,backup)")
+	flag.BoolVar(&config.Robots, "robots", false, "Also request the paths listed in each target's robots.txt and sitemap.xml")
+	flag.BoolVar(&config.GitCheck, "git-check", false, "Request .git/HEAD, .git/config and .svn/entries on each target and report exposed repositories")
+	flag.BoolVar(&config.BackupProbe, "backup-probe", false, "Probe backup copies (config.php.bak, .config.php.swp, ...) of every file found with 200 or 403")
+	backupPatterns := flag.String("backup-patterns", "", "Backup permutations for --backup-probe, * is the file name (comma-separated, e.g. *.bak,*~,.*.swp)")
+	flag.IntVar(&config.ExcludeLengthAuto, "exclude-length-auto", 0, "Drop a status and body size once it is seen on more than N paths during the scan (0=off)")
+	flag.Float64Var(&config.Soft404Similarity, "soft404-similarity", 0, "Also drop responses whose body is at least this similar (0-1) to the soft-404 baseline (0=off)")
+	calStrategies := flag.String("cal-strategy", "", "Calibration probe strategies (comma-separated: random-path,random-ext,random-query,random-method)")
+	bypassStrategies := flag.String("bypass-strategies", "", "Only run these bypass strategies (comma-separated names)")
+	noBypassStrategies := flag.String("no-bypass-strategies", "", "Skip these bypass strategies (comma-separated names)")
+	tlsCipherSuites := flag.String("tls-cipher-suites", "", "Only offer these TLS 1.2 cipher suites (comma-separated IANA names; order is not kept)")
+	tlsCurves := flag.String("tls-curves", "", "Only offer these TLS curves (comma-separated: X25519,P256,P384,P521; order is not kept)")
+	flag.BoolVar(&config.Insecure, "insecure", false, "Skip TLS certificate verification (self-signed or internal CAs)")
+	flag.BoolVar(&config.Insecure, "k", false, "Skip TLS certificate verification (shorthand)")
+	flag.BoolVar(&config.HTTP2, "http2", false, "Offer HTTP/2 to HTTPS targets (default: HTTP/1.1 only)")
+	flag.BoolVar(&config.HTTP1, "http1", false, "Force HTTP/1.1 on every connection")
+	flag.StringVar(&config.TLSMinVersion, "tls-min-version", "", "Lowest TLS version to offer (1.0, 1.1, 1.2, 1.3) for probing legacy servers")
+	flag.StringVar(&config.SNI, "sni", "", "TLS server name (SNI) to send, independent of the Host header")
+	flag.StringVar(&config.TLSProfile, "tls-profile", "", "Offer only the TLS 1.2 cipher suites and curves a browser enables (chrome|firefox)")
+	flag.StringVar(&config.TokenCmd, "token-cmd", "", "Command whose stdout is sent as \"Authorization: Bearer <token>\" (run without a shell)")
+	flag.IntVar(&config.TokenRefresh, "token-refresh", 300, "Seconds between --token-cmd runs; a 401 also refreshes (0=only on 401)")
+	flag.StringVar(&config.UnixSocket, "unix", "", "Connect through a unix domain socket instead of TCP")
+	flag.StringVar(&config.Proxy, "proxy", "", "Route requests through an HTTP or SOCKS5 proxy (http://host:port, socks5://host:port)")
+	flag.Var(&connectTo, "connect-to", "Connect to addr instead of host:port, keeping the Host header (host:port:addr, repeatable)")
+	flag.StringVar(&config.Evasion, "evasion", "none", "Primary request path evasion (none|case|encode)")
+	flag.IntVar(&config.LiveRecent, "live-recent", 0, "List the N most recently tried URLs under the live progress line (max 16)")
+	flag.BoolVar(&config.Quiet, "quiet", false, "Print only the URL of each finding to stdout, one per line")
+	flag.BoolVar(&config.Quiet, "q", false, "Print only the URL of each finding to stdout, one per line (shorthand)")
+	flag.BoolVar(&config.NoColor, "no-color", os.Getenv("NO_COLOR") != "", "Disable colors in terminal output (also set by NO_COLOR)")
+	flag.IntVar(&config.LiveInterval, "live-interval", 500, "Minimum milliseconds between URL updates in the live display")
+	flag.BoolVar(&config.Tree, "tree", false, "Print discovered paths as a directory tree per target")
+	flag.StringVar(&config.MonitorDir, "monitor", "", "Keep per-target state in this directory and report only changes since the last run")
+	flag.BoolVar(&config.AnalyzeDupes, "analyze-dupes", false, "Summarize findings that share an identical response body")
+	flag.BoolVar(&config.NormalizeSlash, "normalize-trailing-slash", false, "Report /path and /path/ once when they serve the same response or one redirects to the other")
+	flag.BoolVar(&config.PrintSchema, "print-schema", false, "Print the JSON report schema and exit")
+	flag.BoolVar(&config.ShowVersion, "version", false, "Print version and build information and exit")
+	flag.BoolVar(&config.ShowVersion, "V", false, "Print version and build information and exit (shorthand)")
+	flag.BoolVar(&config.Resolve, "resolve", false, "Resolve each target's IPs and reverse DNS into the report and tag results with resolved_ip")
+	flag.BoolVar(&config.CacheProbe, "cache-probe", false, "Re-request cacheable 200s with X-Forwarded-Host/X-Host and flag reflected hosts (cache poisoning hint)")
+	flag.BoolVar(&config.InsecureDowngrade, "insecure-downgrade", false, "Flag http:// form actions and resources on https pages")
+	flag.BoolVar(&config.DefaultCreds, "default-creds", false, "Note well-known default credentials for detected login panels")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: capsaicin [options]\n\n")
 		fmt.Fprintf(os.Stderr, "Required:\n")
 		fmt.Fprintf(os.Stderr, "  -u string       Target URL (or pipe via STDIN)\n")
-		fmt.Fprintf(os.Stderr, "  -w string       Path to wordlist file\n\n")
+		fmt.Fprintf(os.Stderr, "  -w string       Path to wordlist file, or - for STDIN (repeatable; merged without duplicates)\n\n")
 		fmt.Fprintf(os.Stderr, "Optional:\n")
-		fmt.Fprintf(os.Stderr, "  -t int          Concurrent threads (default: 50, env: CAPSAICIN_THREADS)\n")
+		fmt.Fprintf(os.Stderr, "  -t int|auto     Concurrent threads (default: 50, env: CAPSAICIN_THREADS)\n")
+		fmt.Fprintf(os.Stderr, "  --targets-json file  Targets as JSON lines with per-target headers and wordlist (replaces -u/STDIN)\n")
+		fmt.Fprintf(os.Stderr, "  --target-concurrency int  Max targets scanned at once (default: 0=all)\n")
+		fmt.Fprintf(os.Stderr, "  --threads-per-host int    Max requests in flight per host (default: 0=unlimited)\n")
+		fmt.Fprintf(os.Stderr, "  --host-error-budget int   Abandon a host after N errors (default: 0=never)\n")
+		fmt.Fprintf(os.Stderr, "  --waf-adaptive  Throttle hosts behind a WAF and skip bypass on them\n")
+		fmt.Fprintf(os.Stderr, "  --waf-rate int  Req/s per WAF-protected host under --waf-adaptive (default: 2)\n")
 		fmt.Fprintf(os.Stderr, "  -x string       Extensions (comma-separated)\n")
+		fmt.Fprintf(os.Stderr, "  --ext-mode mode Word expansion: suffix (word, word.ext), prefix (word, .word), both (default: suffix)\n")
 		fmt.Fprintf(os.Stderr, "  -H string       Custom headers (repeatable)\n")
+		fmt.Fprintf(os.Stderr, "  --cookies string  Cookies to send on every request (e.g. \"session=abc; theme=dark\")\n")
+		fmt.Fprintf(os.Stderr, "  --use-cookie-jar  Replay cookies the target sets on later requests to the same host\n")
+		fmt.Fprintf(os.Stderr, "  --wordlist-diff file  Only scan -w entries missing from this previous wordlist\n")
+		fmt.Fprintf(os.Stderr, "  --params file   Discover query parameters: try each word as ?word=test (or in place of FUZZ)\n")
+		fmt.Fprintf(os.Stderr, "  --mutations list  Wordlist mutations: case,leet,slash,affix,backup\n")
+		fmt.Fprintf(os.Stderr, "  --backup-probe  Probe backup copies of files found with 200 or 403\n")
+		fmt.Fprintf(os.Stderr, "  --backup-patterns list  Backup permutations, * is the file name (default: *.bak,*.old,*.orig,*.save,*~,*.swp,.*.swp,*.tmp,*.copy)\n")
 		fmt.Fprintf(os.Stderr, "  --timeout int   Request timeout in seconds (default: 10, env: CAPSAICIN_TIMEOUT)\n")
+		fmt.Fprintf(os.Stderr, "  --adaptive-timeout  Per-host timeout of 4x calibration latency, then 3x p95 (1s floor, --timeout ceiling)\n")
+		fmt.Fprintf(os.Stderr, "  --delay-min int  Min random ms before each request, per worker (default: 0)\n")
+		fmt.Fprintf(os.Stderr, "  --delay-max int  Max random ms before each request, per worker (default: 0=--delay-min)\n")
+		fmt.Fprintf(os.Stderr, "  --body-timeout int  Max seconds to read a body after headers (default: 0=off)\n")
+		fmt.Fprintf(os.Stderr, "  --max-header-kb int  Max response header block size in KB (default: 256)\n")
+		fmt.Fprintf(os.Stderr, "  --max-headers int    Max response header lines kept (default: 200)\n")
 		fmt.Fprintf(os.Stderr, "  --depth int     Recursive scanning depth (0=disabled)\n")
+		fmt.Fprintf(os.Stderr, "  --recurse-on list       Only recurse into directories with these status codes (e.g. 200,403)\n")
+		fmt.Fprintf(os.Stderr, "  --recurse-exclude list  Skip recursion into directories with these names (e.g. static,assets)\n")
+		fmt.Fprintf(os.Stderr, "  --seed int      Seed the random choices for a reproducible scan (default: 0=random)\n")
+		fmt.Fprintf(os.Stderr, "  --follow-redirects  Follow redirects and record the chain (default: report the 3xx)\n")
+		fmt.Fprintf(os.Stderr, "  --max-redirects int  Max redirects followed per request (default: 5)\n")
 		fmt.Fprintf(os.Stderr, "  --rate-limit int Max req/s per host (default: 0, env: CAPSAICIN_RATE_LIMIT)\n")
+		fmt.Fprintf(os.Stderr, "  --rate-limit-global int  Max req/s across all hosts (default: 0)\n")
 		fmt.Fprintf(os.Stderr, "  --retries int   Retry attempts (default: 2)\n")
 		fmt.Fprintf(os.Stderr, "  --log-level str Log level: debug|info|warn|error (default: info)\n")
 		fmt.Fprintf(os.Stderr, "  --dry-run       Show scan plan without executing\n")
 		fmt.Fprintf(os.Stderr, "  --allow pattern Allow domain pattern (repeatable)\n")
 		fmt.Fprintf(os.Stderr, "  --deny pattern  Deny domain pattern (repeatable)\n")
+		fmt.Fprintf(os.Stderr, "  --method str    HTTP method for each path (default: GET)\n")
+		fmt.Fprintf(os.Stderr, "  --methods list  Request each path with every listed method (e.g. GET,POST)\n")
+		fmt.Fprintf(os.Stderr, "  --data str      Request body for each path; switches the default method to POST\n")
+		fmt.Fprintf(os.Stderr, "  --data-file path  Read the request body from a file\n")
+		fmt.Fprintf(os.Stderr, "  --content-type str  Body Content-Type (default: application/x-www-form-urlencoded)\n")
+		fmt.Fprintf(os.Stderr, "  --user-agent str  Fixed User-Agent for every request (default: rotate browser strings)\n")
+		fmt.Fprintf(os.Stderr, "  --user-agents-file path  User-Agents to rotate through, one per line\n")
+		fmt.Fprintf(os.Stderr, "  --robots        Also scan paths from robots.txt and sitemap.xml\n")
+		fmt.Fprintf(os.Stderr, "  --git-check     Probe each target for exposed .git and .svn metadata\n")
 		fmt.Fprintf(os.Stderr, "  --safe-mode     Disable bypass attempts\n")
+		fmt.Fprintf(os.Stderr, "  --read-only     GET requests only; print the request plan first (implies --safe-mode)\n")
+		fmt.Fprintf(os.Stderr, "  --only-secrets  Only report responses containing secrets\n")
+		fmt.Fprintf(os.Stderr, "  --actionable-only  Drop informational findings from output and reports\n")
+		fmt.Fprintf(os.Stderr, "  --show-secrets  Write raw secret values to results and reports (sensitive)\n")
+		fmt.Fprintf(os.Stderr, "  --resume file   Checkpoint completed paths; rerun with the same file to continue\n")
+		fmt.Fprintf(os.Stderr, "  --conditional-cache file  Skip downloading findings unchanged since the last scan (304 Not Modified)\n")
+		fmt.Fprintf(os.Stderr, "  --max-findings int  Stop after N findings and write a partial report (default: 0=off)\n")
+		fmt.Fprintf(os.Stderr, "  --confirm-findings  Re-request findings once; drop flaky ones (kept with -v)\n")
 		fmt.Fprintf(os.Stderr, "  --fail-on str   Exit code 2 if severity >= threshold (critical|high|medium|low|info)\n")
+		fmt.Fprintf(os.Stderr, "  --severity-map list  Severity per status code (e.g. 403=high,500=medium)\n")
+		fmt.Fprintf(os.Stderr, "  -mc list        Only report these status codes (e.g. 200,301,403)\n")
+		fmt.Fprintf(os.Stderr, "  -fc list        Report every status code except these (e.g. 404,500)\n")
+		fmt.Fprintf(os.Stderr, "  -fs list        Drop responses of these sizes in bytes (e.g. 1024,100-200)\n")
+		fmt.Fprintf(os.Stderr, "  -fw list        Drop responses with these word counts (e.g. 50,10-)\n")
+		fmt.Fprintf(os.Stderr, "  --interesting-types list  Always report these content types, even as calibrated noise (e.g. application/json,text/x-*)\n")
+		fmt.Fprintf(os.Stderr, "  --exclude-length-auto int  Drop a status and size seen on more than N paths (default: 0=off)\n")
+		fmt.Fprintf(os.Stderr, "  --soft404-similarity f  Drop bodies this similar (0-1) to the soft-404 baseline, e.g. 0.9 (default: 0=off)\n")
+		fmt.Fprintf(os.Stderr, "  --cal-strategy list  Calibration probes: random-path,random-ext,random-query,random-method (default: random-path)\n")
+		fmt.Fprintf(os.Stderr, "  --evasion mode  Path evasion on primary requests: none|case|encode (default: none)\n")
+		fmt.Fprintf(os.Stderr, "  --bypass-concurrency int  Parallel bypass strategies per 403/401 (default: 1)\n")
+		fmt.Fprintf(os.Stderr, "  --bypass-strategies list     Only run the named bypass strategies (e.g. headers,case-upper)\n")
+		fmt.Fprintf(os.Stderr, "  --no-bypass-strategies list  Skip the named bypass strategies (e.g. path-null-byte)\n")
+		fmt.Fprintf(os.Stderr, "  --token-cmd cmd  Run cmd for a bearer token; refreshed on interval and on 401\n")
+		fmt.Fprintf(os.Stderr, "  --token-refresh int  Seconds between token refreshes (default: 300)\n")
+		fmt.Fprintf(os.Stderr, "  -k, --insecure  Skip TLS certificate verification\n")
+		fmt.Fprintf(os.Stderr, "  --tls-min-version v  Lowest TLS version to offer: 1.0, 1.1, 1.2, 1.3\n")
+		fmt.Fprintf(os.Stderr, "  --http2         Offer HTTP/2 to HTTPS targets\n")
+		fmt.Fprintf(os.Stderr, "  --http1         Force HTTP/1.1\n")
+		fmt.Fprintf(os.Stderr, "  --sni name      TLS server name to send, independent of Host\n")
+		fmt.Fprintf(os.Stderr, "  --tls-profile name  Offer only a browser's TLS 1.2 suites and curves: chrome|firefox\n")
+		fmt.Fprintf(os.Stderr, "  --tls-cipher-suites list  Allowed TLS 1.2 cipher suites (IANA names)\n")
+		fmt.Fprintf(os.Stderr, "  --tls-curves list  Allowed TLS curves (X25519,P256,P384,P521)\n")
+		fmt.Fprintf(os.Stderr, "  --unix path     Connect through a unix domain socket\n")
+		fmt.Fprintf(os.Stderr, "  --connect-to host:port:addr  Send host:port traffic to addr, keeping Host (repeatable)\n")
+		fmt.Fprintf(os.Stderr, "  --proxy url     HTTP or SOCKS5 proxy, e.g. http://127.0.0.1:8080 or socks5://127.0.0.1:9050\n")
+		fmt.Fprintf(os.Stderr, "  --default-creds Note default credentials for detected login panels\n")
+		fmt.Fprintf(os.Stderr, "  --insecure-downgrade  Flag http:// forms and resources on https pages\n")
+		fmt.Fprintf(os.Stderr, "  --cache-probe   Check cacheable 200s for reflected X-Forwarded-Host/X-Host\n")
+		fmt.Fprintf(os.Stderr, "  --resolve       Record target IPs and PTR names in the report\n")
 		fmt.Fprintf(os.Stderr, "  -v              Verbose mode\n")
-		fmt.Fprintf(os.Stderr, "  -o string       JSON output file\n")
-		fmt.Fprintf(os.Stderr, "  --html string   HTML report file\n\n")
+		fmt.Fprintf(os.Stderr, "  -o string       JSON output file (- for stdout; UI moves to stderr)\n")
+		fmt.Fprintf(os.Stderr, "  --html string   HTML report file\n")
+		fmt.Fprintf(os.Stderr, "  --html-live     Rewrite the HTML report every few seconds during the scan\n")
+		fmt.Fprintf(os.Stderr, "  --yaml string   YAML findings file (stable output for git diffs)\n")
+		fmt.Fprintf(os.Stderr, "  --csv string    CSV findings file for spreadsheets\n")
+		fmt.Fprintf(os.Stderr, "  --output-dir dir  Write JSON, HTML and CSV reports into dir/<run-id>/\n")
+		fmt.Fprintf(os.Stderr, "  --sarif string  SARIF 2.1.0 findings file for code scanning\n")
+		fmt.Fprintf(os.Stderr, "  --ndjson-out string  Stream findings as JSON lines during the scan (- for stdout)\n")
+		fmt.Fprintf(os.Stderr, "  --status-addr addr  Serve /status and /results JSON while scanning (e.g. 127.0.0.1:8080)\n")
+		fmt.Fprintf(os.Stderr, "  --metrics-addr addr Serve Prometheus /metrics while scanning (e.g. 127.0.0.1:9090)\n")
+		fmt.Fprintf(os.Stderr, "  --live-recent int    Show the N most recent URLs under the progress line (default: 0)\n")
+		fmt.Fprintf(os.Stderr, "  --live-interval int  Min ms between live URL updates (default: 500)\n")
+		fmt.Fprintf(os.Stderr, "  --no-color      Disable colors (default: off when NO_COLOR is set or output is not a terminal)\n")
+		fmt.Fprintf(os.Stderr, "  -q, --quiet     Print only the URL of each finding, one per line\n")
+		fmt.Fprintf(os.Stderr, "  --tree          Print discovered paths as a directory tree\n")
+		fmt.Fprintf(os.Stderr, "  --monitor dir   Report only changes since the last run (exit 3 if any)\n")
+		fmt.Fprintf(os.Stderr, "  --analyze-dupes Summarize findings serving an identical body\n")
+		fmt.Fprintf(os.Stderr, "  --normalize-trailing-slash  Collapse /path and /path/ duplicates in reports\n")
+		fmt.Fprintf(os.Stderr, "  --print-schema  Print the JSON report schema and exit\n")
+		fmt.Fprintf(os.Stderr, "  -V, --version   Print version and build information and exit\n\n")
 		fmt.Fprintf(os.Stderr, "Examples:\n")
 		fmt.Fprintf(os.Stderr, "  capsaicin -u https://target.com -w wordlist.txt\n")
 		fmt.Fprintf(os.Stderr, "  cat targets.txt | capsaicin -w words.txt -t 100\n")
@@ -137,6 +592,23 @@ func Parse() Config {
 		}
 	}
 
+	config.Mutations = splitList(*mutations)
+	config.BackupPatterns = splitList(*backupPatterns)
+	config.SeverityMap = splitList(*severityMap)
+	config.MatchCodes = splitList(*matchCodes)
+	config.FilterCodes = splitList(*filterCodes)
+	config.FilterSizes = splitList(*filterSizes)
+	config.FilterWords = splitList(*filterWords)
+	config.InterestingTypes = splitList(strings.ToLower(*interestingTypes))
+	config.RecurseOn = splitList(*recurseOn)
+	config.RecurseExclude = splitList(*recurseExclude)
+	config.TLSCipherSuites = splitList(*tlsCipherSuites)
+	config.TLSCurves = splitList(*tlsCurves)
+	config.CalStrategies = splitList(*calStrategies)
+	config.BypassStrategies = splitList(*bypassStrategies)
+	config.NoBypassStrategies = splitList(*noBypassStrategies)
+	config.Methods = splitList(*methods)
+
 	for _, h := range headers {
 		parts := strings.SplitN(h, ":", 2)
 		if len(parts) == 2 {
@@ -146,20 +618,235 @@ func Parse() Config {
 		}
 	}
 
+	if len(wordlists) > 0 {
+		config.Wordlist = wordlists[0]
+		config.Wordlists = wordlists
+	}
+
 	config.AllowPatterns = allowPatterns
 	config.DenyPatterns = denyPatterns
+	config.ConnectTo = connectTo
 
 	return config
 }
 
+// cookieHeader checks a --cookies value of semicolon-separated name=value
+// pairs and returns it as a Cookie header value.
+func cookieHeader(value string) (string, error) {
+	var pairs []string
+	for _, pair := range strings.Split(value, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, _, ok := strings.Cut(pair, "=")
+		if !ok || name == "" || strings.ContainsAny(name, " \t,\"") {
+			return "", fmt.Errorf("invalid --cookies entry %q. Use name=value pairs separated by semicolons, e.g. --cookies \"session=abc; theme=dark\"", pair)
+		}
+		pairs = append(pairs, pair)
+	}
+	if len(pairs) == 0 {
+		return "", fmt.Errorf("--cookies is empty. Use name=value pairs separated by semicolons, e.g. --cookies \"session=abc; theme=dark\"")
+	}
+	return strings.Join(pairs, "; "), nil
+}
+
+// splitList splits a comma-separated flag value, trimming whitespace and
+// dropping empty entries.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ConnectToMap parses --connect-to entries of the form host:port:addr into a
+// dial address map. addr may carry its own port ("10.0.0.5:8080"); if it
+// does not, the original port is kept.
+func ConnectToMap(entries []string) (map[string]string, error) {
+	mappings := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid --connect-to %q. Expected host:port:addr", entry)
+		}
+		port, err := strconv.Atoi(parts[1])
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("invalid --connect-to %q. Port %q must be 1-65535", entry, parts[1])
+		}
+
+		addr := parts[2]
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, parts[1])
+		}
+		mappings[net.JoinHostPort(parts[0], parts[1])] = addr
+	}
+	return mappings, nil
+}
+
+// ProxyURL parses --proxy. Supported schemes are http, https, socks5 and
+// socks5h; a SOCKS proxy without a port gets the standard 1080. An empty
+// value returns a nil URL.
+func ProxyURL(raw string) (*url.URL, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid --proxy %q. Expected scheme://host:port, e.g. http://127.0.0.1:8080 or socks5://127.0.0.1:9050", raw)
+	}
+	switch u.Scheme {
+	case "http", "https":
+	case "socks5", "socks5h":
+		if u.Port() == "" {
+			u.Host = net.JoinHostPort(u.Hostname(), "1080")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported --proxy scheme %q. Valid values: http, https, socks5, socks5h", u.Scheme)
+	}
+	return u, nil
+}
+
+// tlsVersions maps --tls-min-version values to crypto/tls constants.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSVersion parses --tls-min-version ("1.0" through "1.3") into a
+// tls.VersionTLS1x constant. An empty value returns 0, which keeps Go's
+// default minimum.
+func TLSVersion(value string) (uint16, error) {
+	if value == "" {
+		return 0, nil
+	}
+	version, ok := tlsVersions[value]
+	if !ok {
+		return 0, fmt.Errorf("invalid --tls-min-version %q. Valid values: 1.0, 1.1, 1.2, 1.3", value)
+	}
+	return version, nil
+}
+
+// SeverityMapping parses --severity-map entries of the form status=severity
+// into a status code to severity map.
+func SeverityMapping(entries []string) (map[int]string, error) {
+	mapping := make(map[int]string, len(entries))
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid --severity-map entry %q. Expected status=severity", entry)
+		}
+		status, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+		if err != nil || status < 100 || status > 599 {
+			return nil, fmt.Errorf("invalid --severity-map entry %q. Status must be 100-599", entry)
+		}
+		severity := strings.ToLower(strings.TrimSpace(parts[1]))
+		if !validSeverities[severity] {
+			return nil, fmt.Errorf("invalid --severity-map entry %q. Valid severities: critical, high, medium, low, info", entry)
+		}
+		mapping[status] = severity
+	}
+	return mapping, nil
+}
+
+// StatusCodeSet parses -mc or -fc entries into a set of status codes. flag
+// names the option in error messages. No entries give a nil set.
+func StatusCodeSet(flag string, entries []string) (map[int]bool, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	codes := make(map[int]bool, len(entries))
+	for _, entry := range entries {
+		status, err := strconv.Atoi(strings.TrimSpace(entry))
+		if err != nil || status < 100 || status > 599 {
+			return nil, fmt.Errorf("invalid %s entry %q. Status must be 100-599", flag, entry)
+		}
+		codes[status] = true
+	}
+	return codes, nil
+}
+
+// Range is an inclusive span of non-negative integers. Max is -1 for an
+// open-ended range such as "100-".
+type Range struct {
+	Min, Max int
+}
+
+// Ranges is a -fs or -fw list.
+type Ranges []Range
+
+// Contains reports whether n falls in any of the ranges.
+func (r Ranges) Contains(n int) bool {
+	for _, span := range r {
+		if n >= span.Min && (span.Max < 0 || n <= span.Max) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRanges parses -fs or -fw entries: a number ("1024"), a range
+// ("100-200"), or a range open at either end ("100-", "-200"). flag names
+// the option in error messages.
+func ParseRanges(flag string, entries []string) (Ranges, error) {
+	var ranges Ranges
+	for _, entry := range entries {
+		invalid := fmt.Errorf("invalid %s entry %q. Use a number or a range like 100-200, 100- or -200", flag, entry)
+		lo, hi, isRange := strings.Cut(strings.TrimSpace(entry), "-")
+		lo, hi = strings.TrimSpace(lo), strings.TrimSpace(hi)
+		if !isRange {
+			hi = lo
+		}
+		if lo == "" && hi == "" {
+			return nil, invalid
+		}
+
+		span := Range{Min: 0, Max: -1}
+		if lo != "" {
+			n, err := strconv.Atoi(lo)
+			if err != nil || n < 0 {
+				return nil, invalid
+			}
+			span.Min = n
+		}
+		if hi != "" {
+			n, err := strconv.Atoi(hi)
+			if err != nil || n < 0 {
+				return nil, invalid
+			}
+			span.Max = n
+		}
+		if span.Max >= 0 && span.Min > span.Max {
+			return nil, fmt.Errorf("invalid %s range %q. The lower bound is above the upper one", flag, entry)
+		}
+		ranges = append(ranges, span)
+	}
+	return ranges, nil
+}
+
 func Validate(config *Config, targets []string) error {
 	if len(targets) == 0 {
 		return fmt.Errorf("no targets specified. Use -u flag or pipe targets via STDIN")
 	}
 
 	for i := range targets {
-		if !strings.HasPrefix(targets[i], "http://") && !strings.HasPrefix(targets[i], "https://") {
-			targets[i] = "http://" + targets[i]
+		targets[i] = normalizeTarget(targets[i])
+	}
+
+	if config.ParamsWordlist != "" {
+		paths := config.WordlistPaths()
+		if len(paths) > 1 || (len(paths) == 1 && paths[0] != config.ParamsWordlist) {
+			return fmt.Errorf("--params replaces -w; pass only one wordlist")
+		}
+		config.Wordlist = config.ParamsWordlist
+		config.Wordlists = nil
+		if config.Robots {
+			return fmt.Errorf("--robots seeds paths and cannot be combined with --params")
 		}
 	}
 
@@ -167,25 +854,363 @@ func Validate(config *Config, targets []string) error {
 		return fmt.Errorf("wordlist is required (-w). Provide a wordlist file path")
 	}
 
-	if _, err := os.Stat(config.Wordlist); os.IsNotExist(err) {
-		return fmt.Errorf("wordlist file not found: %s. Check the path and try again", config.Wordlist)
+	stdinWordlists := 0
+	for _, path := range config.WordlistPaths() {
+		if path == StdinWordlist {
+			stdinWordlists++
+			continue
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return fmt.Errorf("wordlist file not found: %s. Check the path and try again", path)
+		}
+	}
+
+	if stdinWordlists > 1 {
+		return fmt.Errorf("-w - can only be given once: STDIN is read a single time")
+	}
+
+	if err := validateTargetSpecs(config); err != nil {
+		return err
+	}
+
+	if config.WordlistDiff != "" {
+		if _, err := os.Stat(config.WordlistDiff); os.IsNotExist(err) {
+			return fmt.Errorf("wordlist-diff file not found: %s. Check the path and try again", config.WordlistDiff)
+		}
+	}
+
+	if config.Data != "" && config.DataFile != "" {
+		return fmt.Errorf("--data and --data-file cannot be combined. Use one of them for the request body")
+	}
+	if config.DataFile != "" {
+		data, err := os.ReadFile(config.DataFile)
+		if err != nil {
+			return fmt.Errorf("cannot read --data-file %s: %v", config.DataFile, err)
+		}
+		if len(data) == 0 {
+			return fmt.Errorf("--data-file %s is empty. Check the path or drop the flag", config.DataFile)
+		}
+		config.Data = string(data)
+	}
+	if config.Data != "" {
+		// As with curl -d, a body turns the default GET into POST;
+		// --methods GET keeps GET.
+		if len(config.Methods) == 0 && (config.Method == "" || strings.EqualFold(config.Method, "GET")) {
+			config.Method = "POST"
+		}
+		if config.ContentType == "" {
+			config.ContentType = "application/x-www-form-urlencoded"
+		}
+	} else if config.ContentType != "" {
+		return fmt.Errorf("--content-type needs a request body. Use --data or --data-file to set one")
+	}
+
+	if config.UserAgent != "" && config.UserAgentsFile != "" {
+		return fmt.Errorf("--user-agent and --user-agents-file cannot be combined. Use --user-agent for a fixed User-Agent")
+	}
+	if strings.ContainsAny(config.UserAgent, "\r\n") {
+		return fmt.Errorf("--user-agent must be a single line")
+	}
+	if config.UserAgentsFile != "" {
+		agents, err := readUserAgents(config.UserAgentsFile)
+		if err != nil {
+			return fmt.Errorf("cannot read --user-agents-file %s: %v", config.UserAgentsFile, err)
+		}
+		if len(agents) == 0 {
+			return fmt.Errorf("--user-agents-file %s has no User-Agents. Put one per line", config.UserAgentsFile)
+		}
+		config.UserAgents = agents
+	}
+
+	if len(config.Methods) > 0 && config.Method != "" && !strings.EqualFold(config.Method, "GET") {
+		return fmt.Errorf("--method and --methods cannot be combined. List every method in --methods")
+	}
+	methods := make([]string, 0, len(config.RequestMethods()))
+	seenMethods := make(map[string]bool)
+	for _, method := range config.RequestMethods() {
+		method = strings.ToUpper(method)
+		if !validMethod(method) {
+			return fmt.Errorf("invalid HTTP method %q. Use --method or --methods with names like GET,POST", method)
+		}
+		if !seenMethods[method] {
+			seenMethods[method] = true
+			methods = append(methods, method)
+		}
+	}
+	config.Method = methods[0]
+	config.Methods = methods
+
+	if config.ReadOnly {
+		if err := checkReadOnly(config); err != nil {
+			return err
+		}
+		// Read-only is a superset of safe mode: no method fuzzing, no
+		// bypass, no cache probe.
+		config.SafeMode = true
+	}
+
+	if config.ThreadsAuto {
+		config.Threads = AutoThreads(runtime.NumCPU(), len(targets))
 	}
 
 	if config.Threads <= 0 {
 		return fmt.Errorf("threads must be positive, got %d. Use -t to set (default: 50)", config.Threads)
 	}
 
+	if config.FollowRedirects && config.MaxRedirects <= 0 {
+		return fmt.Errorf("max redirects must be positive, got %d. Use --max-redirects to set (default: 5)", config.MaxRedirects)
+	}
+
 	if config.Timeout <= 0 {
 		return fmt.Errorf("timeout must be positive, got %d. Use --timeout to set (default: 10)", config.Timeout)
 	}
 
+	if config.TokenRefresh < 0 {
+		return fmt.Errorf("token refresh must not be negative, got %d. Use --token-refresh to set (default: 300)", config.TokenRefresh)
+	}
+
+	if config.Cookies != "" {
+		cookies, err := cookieHeader(config.Cookies)
+		if err != nil {
+			return err
+		}
+		// --cookies adds to a Cookie header given with -H.
+		if config.CustomHeaders == nil {
+			config.CustomHeaders = make(map[string]string)
+		}
+		name := "Cookie"
+		for key := range config.CustomHeaders {
+			if strings.EqualFold(key, "Cookie") {
+				name = key
+				cookies = config.CustomHeaders[key] + "; " + cookies
+			}
+		}
+		config.CustomHeaders[name] = cookies
+	}
+
+	if config.TokenCmd != "" {
+		for name := range config.CustomHeaders {
+			if strings.EqualFold(name, "Authorization") {
+				return fmt.Errorf("--token-cmd sets the Authorization header; remove -H %q", name+": ...")
+			}
+		}
+	}
+
+	if config.HostErrorBudget < 0 {
+		return fmt.Errorf("host error budget must not be negative, got %d. Use --host-error-budget to set (default: 0)", config.HostErrorBudget)
+	}
+
+	if config.WAFAdaptive && config.WAFRate <= 0 {
+		return fmt.Errorf("waf rate must be positive, got %d. Use --waf-rate to set (default: 2)", config.WAFRate)
+	}
+
+	if config.ThreadsPerHost < 0 {
+		return fmt.Errorf("threads per host must not be negative, got %d. Use --threads-per-host to set (default: 0)", config.ThreadsPerHost)
+	}
+	if config.TargetConcurrency < 0 {
+		return fmt.Errorf("target concurrency must not be negative, got %d. Use --target-concurrency to set (default: 0)", config.TargetConcurrency)
+	}
+
+	if config.LiveRecent < 0 || config.LiveRecent > 16 {
+		return fmt.Errorf("live recent must be between 0 and 16, got %d. Use --live-recent to set (default: 0)", config.LiveRecent)
+	}
+
+	if config.LiveInterval < 0 {
+		return fmt.Errorf("live interval must not be negative, got %d. Use --live-interval to set (default: 500)", config.LiveInterval)
+	}
+
+	if config.DelayMin < 0 {
+		return fmt.Errorf("delay min must not be negative, got %d. Use --delay-min to set (default: 0)", config.DelayMin)
+	}
+
+	// --delay-min alone is a fixed delay.
+	if config.DelayMax == 0 {
+		config.DelayMax = config.DelayMin
+	}
+	if config.DelayMax < config.DelayMin {
+		return fmt.Errorf("delay max must be at least --delay-min (%d ms), got %d. Use --delay-max to set (default: 0)", config.DelayMin, config.DelayMax)
+	}
+
+	if config.Soft404Similarity < 0 || config.Soft404Similarity > 1 {
+		return fmt.Errorf("soft-404 similarity must be between 0 and 1, got %g. Use --soft404-similarity to set (default: 0=off)", config.Soft404Similarity)
+	}
+
+	if config.BodyTimeout < 0 {
+		return fmt.Errorf("body timeout must not be negative, got %d. Use --body-timeout to set (default: 0)", config.BodyTimeout)
+	}
+
+	if config.MaxHeaderKB < 0 {
+		return fmt.Errorf("max header size must not be negative, got %d. Use --max-header-kb to set (default: 256)", config.MaxHeaderKB)
+	}
+
+	if config.MaxHeaders < 0 {
+		return fmt.Errorf("max headers must not be negative, got %d. Use --max-headers to set (default: 200)", config.MaxHeaders)
+	}
+
+	if config.HTMLReport == "-" {
+		return fmt.Errorf("--html cannot write to stdout. Use -o - for a machine-readable report on stdout")
+	}
+
+	if config.YAMLReport == "-" {
+		return fmt.Errorf("--yaml cannot write to stdout. Use -o - for a machine-readable report on stdout")
+	}
+
+	if config.CSVReport == "-" {
+		return fmt.Errorf("--csv cannot write to stdout. Use -o - for a machine-readable report on stdout")
+	}
+
+	if config.SARIFReport == "-" {
+		return fmt.Errorf("--sarif cannot write to stdout. Use -o - for a machine-readable report on stdout")
+	}
+
+	if config.OutputDir != "" {
+		if config.OutputDir == "-" {
+			return fmt.Errorf("--output-dir needs a directory path, not stdout. Use -o - for a machine-readable report on stdout")
+		}
+		if config.OutputFile != "" || config.HTMLReport != "" || config.CSVReport != "" {
+			return fmt.Errorf("--output-dir already writes the JSON, HTML and CSV reports, so it cannot be combined with -o, --html or --csv")
+		}
+	}
+
+	if config.Quiet && (config.OutputFile == "-" || config.NDJSONOut == "-") {
+		return fmt.Errorf("--quiet prints finding URLs to stdout, so -o and --ndjson-out cannot also write there. Send them to a file")
+	}
+	if config.NDJSONOut == "-" && config.OutputFile == "-" {
+		return fmt.Errorf("-o and --ndjson-out cannot both write to stdout. Send one of them to a file")
+	}
+
+	if config.StatusAddr != "" {
+		if _, _, err := net.SplitHostPort(config.StatusAddr); err != nil {
+			return fmt.Errorf("invalid --status-addr %q: %v. Use host:port, e.g. 127.0.0.1:8080", config.StatusAddr, err)
+		}
+	}
+	if config.MetricsAddr != "" {
+		if _, _, err := net.SplitHostPort(config.MetricsAddr); err != nil {
+			return fmt.Errorf("invalid --metrics-addr %q: %v. Use host:port, e.g. 127.0.0.1:9090", config.MetricsAddr, err)
+		}
+		if config.MetricsAddr == config.StatusAddr {
+			return fmt.Errorf("--metrics-addr and --status-addr cannot share %s. Give each its own port", config.MetricsAddr)
+		}
+	}
+
+	if config.Resume == "-" {
+		return fmt.Errorf("--resume needs a checkpoint file path, not stdout")
+	}
+	if config.ConditionalCache == "-" {
+		return fmt.Errorf("--conditional-cache needs a file path, not stdout")
+	}
+
+	if config.HTMLLive && config.HTMLReport == "" && config.OutputDir == "" {
+		return fmt.Errorf("--html-live requires an HTML report path. Use --html or --output-dir to set it")
+	}
+
+	if config.MaxFindings < 0 {
+		return fmt.Errorf("max findings must not be negative, got %d. Use --max-findings to set (default: 0)", config.MaxFindings)
+	}
+
+	if config.BypassConcurrency < 0 {
+		return fmt.Errorf("bypass concurrency must not be negative, got %d. Use --bypass-concurrency to set (default: 1)", config.BypassConcurrency)
+	}
+
+	if config.UnixSocket != "" {
+		info, err := os.Stat(config.UnixSocket)
+		if err != nil {
+			return fmt.Errorf("unix socket not found: %s. Check the path and try again", config.UnixSocket)
+		}
+		if info.Mode()&os.ModeSocket == 0 {
+			return fmt.Errorf("%s is not a unix socket", config.UnixSocket)
+		}
+	}
+
+	if _, err := ConnectToMap(config.ConnectTo); err != nil {
+		return err
+	}
+
+	if config.HTTP2 && config.HTTP1 {
+		return fmt.Errorf("--http2 and --http1 cannot be combined. Pick one protocol")
+	}
+	if _, err := TLSVersion(config.TLSMinVersion); err != nil {
+		return err
+	}
+
+	proxyURL, err := ProxyURL(config.Proxy)
+	if err != nil {
+		return err
+	}
+	if proxyURL != nil {
+		if config.UnixSocket != "" {
+			return fmt.Errorf("--proxy cannot be combined with --unix; both decide where connections go")
+		}
+		if len(config.ConnectTo) > 0 && !strings.HasPrefix(proxyURL.Scheme, "socks5") {
+			return fmt.Errorf("--connect-to needs a socks5 --proxy; an HTTP proxy connects to the target host itself")
+		}
+	}
+
+	if config.ExtMode != "" {
+		validExtModes := map[string]bool{"suffix": true, "prefix": true, "both": true}
+		if !validExtModes[config.ExtMode] {
+			return fmt.Errorf("invalid --ext-mode value %q. Valid values: suffix, prefix, both", config.ExtMode)
+		}
+		if config.ExtMode == "prefix" && len(config.Extensions) > 0 {
+			return fmt.Errorf("--ext-mode prefix does not use -x extensions. Use --ext-mode both to request word.ext and .word")
+		}
+	}
+
+	if config.Evasion != "" {
+		validEvasion := map[string]bool{"none": true, "case": true, "encode": true}
+		if !validEvasion[config.Evasion] {
+			return fmt.Errorf("invalid --evasion value %q. Valid values: none, case, encode", config.Evasion)
+		}
+	}
+
 	validLogLevels := map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
 	if !validLogLevels[config.LogLevel] {
 		return fmt.Errorf("invalid log level %q. Valid values: debug, info, warn, error", config.LogLevel)
 	}
 
+	if _, err := SeverityMapping(config.SeverityMap); err != nil {
+		return err
+	}
+	if _, err := StatusCodeSet("-mc", config.MatchCodes); err != nil {
+		return err
+	}
+	if _, err := StatusCodeSet("-fc", config.FilterCodes); err != nil {
+		return err
+	}
+	if _, err := ParseRanges("-fs", config.FilterSizes); err != nil {
+		return err
+	}
+	if _, err := ParseRanges("-fw", config.FilterWords); err != nil {
+		return err
+	}
+	if config.ExcludeLengthAuto < 0 {
+		return fmt.Errorf("exclude-length-auto must not be negative, got %d. Use --exclude-length-auto to set (default: 0)", config.ExcludeLengthAuto)
+	}
+	for _, pattern := range config.InterestingTypes {
+		kind, sub, ok := strings.Cut(pattern, "/")
+		if _, err := path.Match(pattern, ""); err != nil || !ok || kind == "" || sub == "" || strings.ContainsAny(sub, "/;") {
+			return fmt.Errorf("invalid --interesting-types entry %q. Use a media type such as application/json, optionally with * wildcards (text/x-*)", pattern)
+		}
+	}
+
+	if _, err := StatusCodeSet("--recurse-on", config.RecurseOn); err != nil {
+		return err
+	}
+	for _, name := range config.RecurseExclude {
+		if _, err := path.Match(name, ""); err != nil || strings.Contains(name, "/") {
+			return fmt.Errorf("invalid --recurse-exclude entry %q. Use a directory name such as static, optionally with * wildcards", name)
+		}
+	}
+	if (len(config.RecurseOn) > 0 || len(config.RecurseExclude) > 0) && config.MaxDepth <= 0 {
+		return fmt.Errorf("--recurse-on and --recurse-exclude only apply to recursive scans. Use --depth to enable recursion")
+	}
+	for _, pattern := range config.BackupPatterns {
+		if strings.Count(pattern, "*") != 1 || pattern == "*" || strings.ContainsAny(pattern, "/?#") {
+			return fmt.Errorf("invalid --backup-patterns entry %q. Use one * for the file name plus a prefix or suffix, e.g. *.bak or .*.swp", pattern)
+		}
+	}
+
 	if config.FailOn != "" {
-		validSeverities := map[string]bool{"critical": true, "high": true, "medium": true, "low": true, "info": true}
 		if !validSeverities[config.FailOn] {
 			return fmt.Errorf("invalid --fail-on value %q. Valid values: critical, high, medium, low, info", config.FailOn)
 		}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TargetSpec is one line of a --targets-json file: a target URL with the
+// headers and wordlist to scan it with. Headers are merged over -H, a
+// header given in both taking the target's value; a wordlist replaces -w
+// for that target.
+type TargetSpec struct {
+	URL      string            `json:"url"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Wordlist string            `json:"wordlist,omitempty"`
+}
+
+// normalizeTarget defaults a target without a scheme to http://.
+func normalizeTarget(target string) string {
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		return "http://" + target
+	}
+	return target
+}
+
+// LoadTargetsJSON reads a --targets-json file: one JSON object per line,
+// with blank lines and lines starting with # skipped. URLs are normalized
+// as Validate does, and a URL listed twice is an error because its
+// settings would be ambiguous.
+func LoadTargetsJSON(path string) ([]TargetSpec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read --targets-json %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var specs []TargetSpec
+	seen := make(map[string]int)
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNum := 1; sc.Scan(); lineNum++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var spec TargetSpec
+		dec := json.NewDecoder(strings.NewReader(line))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", path, lineNum, err)
+		}
+		spec.URL = strings.TrimSpace(spec.URL)
+		if spec.URL == "" {
+			return nil, fmt.Errorf("%s line %d: missing \"url\"", path, lineNum)
+		}
+		spec.URL = normalizeTarget(spec.URL)
+		if first, ok := seen[spec.URL]; ok {
+			return nil, fmt.Errorf("%s line %d: %s is already listed on line %d", path, lineNum, spec.URL, first)
+		}
+		seen[spec.URL] = lineNum
+		specs = append(specs, spec)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read --targets-json %s: %v", path, err)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("--targets-json %s lists no targets", path)
+	}
+	return specs, nil
+}
+
+// ForTarget returns the configuration target is scanned with: c itself
+// unless --targets-json gave the target its own headers or wordlist.
+func (c Config) ForTarget(target string) Config {
+	spec, ok := c.TargetSpecs[target]
+	if !ok {
+		return c
+	}
+	if len(spec.Headers) > 0 {
+		headers := make(map[string]string, len(c.CustomHeaders)+len(spec.Headers))
+		for key, value := range c.CustomHeaders {
+			headers[key] = value
+		}
+		for key, value := range spec.Headers {
+			// Header names are case-insensitive; drop the -H spelling
+			// so it cannot win over the target's value.
+			for existing := range headers {
+				if strings.EqualFold(existing, key) {
+					delete(headers, existing)
+				}
+			}
+			headers[key] = value
+		}
+		c.CustomHeaders = headers
+	}
+	if spec.Wordlist != "" {
+		c.Wordlist = spec.Wordlist
+		c.Wordlists = nil
+	}
+	return c
+}
+
+// validateTargetSpecs checks the per-target settings from --targets-json.
+func validateTargetSpecs(config *Config) error {
+	if config.TargetsJSON != "" && config.TargetURL != "" {
+		return fmt.Errorf("--targets-json and -u cannot be combined. List every target in the file")
+	}
+	for _, spec := range config.TargetSpecs {
+		for name, value := range spec.Headers {
+			if name == "" || strings.ContainsAny(name, ": \t\r\n") {
+				return fmt.Errorf("invalid header name %q for %s in --targets-json", name, spec.URL)
+			}
+			if strings.ContainsAny(value, "\r\n") {
+				return fmt.Errorf("header %s for %s in --targets-json must be a single line", name, spec.URL)
+			}
+			if config.TokenCmd != "" && strings.EqualFold(name, "Authorization") {
+				return fmt.Errorf("--token-cmd sets the Authorization header; remove it for %s in --targets-json", spec.URL)
+			}
+		}
+		if spec.Wordlist == "" {
+			continue
+		}
+		if config.ParamsWordlist != "" {
+			return fmt.Errorf("--params replaces every wordlist; remove \"wordlist\" for %s in --targets-json", spec.URL)
+		}
+		if _, err := os.Stat(spec.Wordlist); os.IsNotExist(err) {
+			return fmt.Errorf("wordlist file not found: %s (for %s in --targets-json). Check the path and try again", spec.Wordlist, spec.URL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package detection
+
+import (
+	"net/http"
+	"strings"
+)
+
+// CacheProbeHeaders are the host-override headers --cache-probe injects.
+// Frameworks that trust them build absolute URLs from the injected value,
+// which a shared cache then serves to everyone.
+var CacheProbeHeaders = []string{"X-Forwarded-Host", "X-Host"}
+
+// ReflectedHost reports whether the injected host appears in body, ignoring
+// case. An empty injected value never matches.
+func ReflectedHost(body, injected string) bool {
+	if injected == "" {
+		return false
+	}
+	return strings.Contains(strings.ToLower(body), strings.ToLower(injected))
+}
+
+// Cacheable reports whether a response looks like it can be stored by a
+// shared cache: Cache-Control must not forbid it (no-store, private,
+// no-cache) and there must be some sign of caching, either an explicit
+// lifetime (public, max-age, s-maxage, Expires) or a cache status header
+// such as Age, X-Cache or CF-Cache-Status.
+func Cacheable(header http.Header) bool {
+	cc := strings.ToLower(header.Get("Cache-Control"))
+	for _, directive := range []string{"no-store", "private", "no-cache"} {
+		if strings.Contains(cc, directive) {
+			return false
+		}
+	}
+	for _, directive := range []string{"public", "max-age", "s-maxage"} {
+		if strings.Contains(cc, directive) {
+			return true
+		}
+	}
+	for _, name := range []string{"Expires", "Age", "X-Cache", "CF-Cache-Status", "X-Cache-Status", "X-Varnish"} {
+		if header.Get(name) != "" {
+			return true
+		}
+	}
+	return false
+}
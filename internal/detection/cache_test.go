@@ -0,0 +1,55 @@
+package detection
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestReflectedHost(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		injected string
+		want     bool
+	}{
+		{"absolute URL", `<script src="https://canary123.example/static/app.js"></script>`, "canary123.example", true},
+		{"case insensitive", `<link rel="canonical" href="https://CANARY123.EXAMPLE/">`, "canary123.example", true},
+		{"not reflected", `<script src="https://target.com/static/app.js"></script>`, "canary123.example", false},
+		{"empty injected", `anything`, "", false},
+		{"empty body", ``, "canary123.example", false},
+	}
+
+	for _, tt := range tests {
+		if got := ReflectedHost(tt.body, tt.injected); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestCacheable(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{"public max-age", map[string]string{"Cache-Control": "public, max-age=600"}, true},
+		{"s-maxage", map[string]string{"Cache-Control": "s-maxage=60"}, true},
+		{"cache hit header", map[string]string{"X-Cache": "HIT"}, true},
+		{"cloudflare status", map[string]string{"CF-Cache-Status": "DYNAMIC"}, true},
+		{"age", map[string]string{"Age": "12"}, true},
+		{"no-store", map[string]string{"Cache-Control": "no-store", "X-Cache": "MISS"}, false},
+		{"private", map[string]string{"Cache-Control": "private, max-age=600"}, false},
+		{"no-cache", map[string]string{"Cache-Control": "no-cache"}, false},
+		{"no cache headers", map[string]string{"Content-Type": "text/html"}, false},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		for k, v := range tt.headers {
+			header.Set(k, v)
+		}
+		if got := Cacheable(header); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
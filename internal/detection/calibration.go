@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"math/rand"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -16,19 +18,42 @@ type ResponseSignature struct {
 	Size       int
 	WordCount  int
 	LineCount  int
+	BodyHash   uint64 // SimHash of the body, 0 when empty
 }
 
 type CalibrationCache struct {
 	mu         sync.RWMutex
 	signatures map[string][]ResponseSignature
+	tlsErrors  map[string]string
+	latencies  map[string]time.Duration
 }
 
 func NewCalibrationCache() *CalibrationCache {
 	return &CalibrationCache{
 		signatures: make(map[string][]ResponseSignature),
+		tlsErrors:  make(map[string]string),
+		latencies:  make(map[string]time.Duration),
 	}
 }
 
+// Latency returns the response time of the slowest successful calibration
+// probe for targetURL, the baseline --adaptive-timeout starts from. ok is
+// false when no probe got a response.
+func (c *CalibrationCache) Latency(targetURL string) (time.Duration, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	latency, ok := c.latencies[targetURL]
+	return latency, ok
+}
+
+// TLSError returns the TLS diagnostic recorded when every calibration probe
+// for targetURL failed the handshake, or "" if the target was reachable.
+func (c *CalibrationCache) TLSError(targetURL string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.tlsErrors[targetURL]
+}
+
 func (c *CalibrationCache) Get(targetURL string) ([]ResponseSignature, bool) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -55,41 +80,171 @@ func calRandIntn(n int) int {
 	return calRng.rng.Intn(n)
 }
 
+// SeedCalibration makes the random names in calibration probes
+// reproducible (--seed). The generator is shared by every scan in the
+// process.
+func SeedCalibration(seed int64) {
+	calRng.mu.Lock()
+	defer calRng.mu.Unlock()
+	calRng.rng = rand.New(rand.NewSource(seed))
+}
+
+// ProbeID returns a random number for a calibration probe name, drawn from
+// the generator SeedCalibration seeds.
+func ProbeID() int {
+	return calRandIntn(999999)
+}
+
+// CalibrationProbe is one baseline request: a method and a path (with an
+// optional query string) relative to the target. A Path starting with
+// http:// or https:// is used as the full URL instead.
+type CalibrationProbe struct {
+	Method string
+	Path   string
+}
+
+// CalibrationStrategy generates a fresh set of probes for one target. Each
+// call must return new random names so baselines are not cached by the
+// target.
+type CalibrationStrategy func() []CalibrationProbe
+
+// CalibrationStrategies are the built-in strategies selectable with
+// --cal-strategy. Extenders can register more before the scan starts.
+var CalibrationStrategies = map[string]CalibrationStrategy{
+	"random-path": func() []CalibrationProbe {
+		return []CalibrationProbe{
+			{"GET", fmt.Sprintf("/capsaicin_cal_%d", calRandIntn(999999))},
+			{"GET", fmt.Sprintf("/nonexistent_%d", calRandIntn(999999))},
+			{"GET", fmt.Sprintf("/test404_%d", calRandIntn(999999))},
+		}
+	},
+	"random-ext": func() []CalibrationProbe {
+		return []CalibrationProbe{
+			{"GET", fmt.Sprintf("/capsaicin_cal_%d.php", calRandIntn(999999))},
+			{"GET", fmt.Sprintf("/capsaicin_cal_%d.html", calRandIntn(999999))},
+		}
+	},
+	"random-query": func() []CalibrationProbe {
+		return []CalibrationProbe{
+			{"GET", fmt.Sprintf("/capsaicin_cal_%d?cal%d=%d", calRandIntn(999999), calRandIntn(999999), calRandIntn(999999))},
+		}
+	},
+	"random-method": func() []CalibrationProbe {
+		return []CalibrationProbe{
+			{"POST", fmt.Sprintf("/capsaicin_cal_%d", calRandIntn(999999))},
+		}
+	},
+}
+
+// DefaultCalibrationStrategies is used when no strategy is selected.
+var DefaultCalibrationStrategies = []string{"random-path"}
+
+// ParseCalibrationStrategies resolves strategy names, falling back to
+// DefaultCalibrationStrategies when names is empty.
+func ParseCalibrationStrategies(names []string) ([]CalibrationStrategy, error) {
+	if len(names) == 0 {
+		names = DefaultCalibrationStrategies
+	}
+	strategies := make([]CalibrationStrategy, 0, len(names))
+	for _, name := range names {
+		strategy, ok := CalibrationStrategies[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown calibration strategy %q. Valid values: %s", name, strings.Join(CalibrationStrategyNames(), ", "))
+		}
+		strategies = append(strategies, strategy)
+	}
+	return strategies, nil
+}
+
+// CalibrationStrategyNames lists the registered strategies, sorted.
+func CalibrationStrategyNames() []string {
+	names := make([]string, 0, len(CalibrationStrategies))
+	for name := range CalibrationStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func PerformCalibration(ctx context.Context, targetURL string, client *http.Client, headers map[string]string, cache *CalibrationCache) []ResponseSignature {
+	return PerformCalibrationWith(ctx, targetURL, client, headers, cache, nil)
+}
+
+// PerformCalibrationWith is PerformCalibration with a path transform applied
+// to each random probe path, so the baseline is measured under the same
+// path rewriting (e.g. WAF evasion) as the real scan. A nil transform
+// leaves paths untouched.
+func PerformCalibrationWith(ctx context.Context, targetURL string, client *http.Client, headers map[string]string, cache *CalibrationCache, transform func(string) string) []ResponseSignature {
+	return PerformCalibrationStrategies(ctx, targetURL, client, headers, cache, transform, nil)
+}
+
+// PerformCalibrationStrategies is PerformCalibrationWith using the probes
+// generated by strategies. Nil strategies mean the default random-path set.
+func PerformCalibrationStrategies(ctx context.Context, targetURL string, client *http.Client, headers map[string]string, cache *CalibrationCache, transform func(string) string, strategies []CalibrationStrategy) []ResponseSignature {
 	if sigs, ok := cache.Get(targetURL); ok {
 		return sigs
 	}
 
-	signatures := make([]ResponseSignature, 0, 3)
-	randomPaths := []string{
-		fmt.Sprintf("/capsaicin_cal_%d", calRandIntn(999999)),
-		fmt.Sprintf("/nonexistent_%d", calRandIntn(999999)),
-		fmt.Sprintf("/test404_%d", calRandIntn(999999)),
+	if len(strategies) == 0 {
+		strategies, _ = ParseCalibrationStrategies(nil)
+	}
+	var probes []CalibrationProbe
+	for _, strategy := range strategies {
+		probes = append(probes, strategy()...)
 	}
 
-	for _, path := range randomPaths {
+	signatures := make([]ResponseSignature, 0, len(probes))
+	tlsError := ""
+	var slowest time.Duration
+
+	for _, probe := range probes {
 		select {
 		case <-ctx.Done():
 			return signatures
 		default:
 		}
-		url := strings.TrimSuffix(targetURL, "/") + path
-		sig := fetchSignature(ctx, url, client, headers)
+		url := probe.Path
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			// The transform rewrites the path only, never the query string.
+			path, query, hasQuery := strings.Cut(probe.Path, "?")
+			if transform != nil {
+				path = transform(path)
+			}
+			if hasQuery {
+				path += "?" + query
+			}
+			url = strings.TrimSuffix(targetURL, "/") + path
+		}
+		start := time.Now()
+		sig, err := fetchSignature(ctx, probe.Method, url, client, headers)
 		if sig != nil {
 			signatures = append(signatures, *sig)
+			if elapsed := time.Since(start); elapsed > slowest {
+				slowest = elapsed
+			}
+		} else if diag := DescribeTLSError(err); diag != "" {
+			tlsError = diag
 		}
 	}
 
 	cache.Set(targetURL, signatures)
+	cache.mu.Lock()
+	if len(signatures) > 0 {
+		cache.latencies[targetURL] = slowest
+	} else if tlsError != "" {
+		cache.tlsErrors[targetURL] = tlsError
+	}
+	cache.mu.Unlock()
 	return signatures
 }
 
-func fetchSignature(ctx context.Context, url string, client *http.Client, headers map[string]string) *ResponseSignature {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+func fetchSignature(ctx context.Context, method, url string, client *http.Client, headers map[string]string) (*ResponseSignature, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
+	// The scan passes its own User-Agent in headers; this is the fallback.
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36")
 
 	for key, value := range headers {
@@ -98,13 +253,13 @@ func fetchSignature(ctx context.Context, url string, client *http.Client, header
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	return &ResponseSignature{
@@ -112,9 +267,18 @@ func fetchSignature(ctx context.Context, url string, client *http.Client, header
 		Size:       len(body),
 		WordCount:  len(strings.Fields(string(body))),
 		LineCount:  strings.Count(string(body), "\n") + 1,
-	}
+		BodyHash:   SimHash(body),
+	}, nil
 }
 
+// Soft-404 thresholds: a response matches a baseline when its size is
+// within sizeThreshold of it, or its word and line counts are both within
+// shapeThreshold (relative differences).
+const (
+	sizeThreshold  = 0.05
+	shapeThreshold = 0.10
+)
+
 func MatchesSignature(statusCode, size, wordCount, lineCount int, signatures []ResponseSignature) bool {
 	for _, sig := range signatures {
 		if statusCode != sig.StatusCode {
@@ -124,13 +288,13 @@ func MatchesSignature(statusCode, size, wordCount, lineCount int, signatures []R
 			continue
 		}
 		sizeDiff := float64(abs(size-sig.Size)) / float64(sig.Size)
-		if sizeDiff < 0.05 {
+		if sizeDiff < sizeThreshold {
 			return true
 		}
 		if sig.WordCount > 0 && sig.LineCount > 0 {
 			wcDiff := float64(abs(wordCount-sig.WordCount)) / float64(sig.WordCount)
 			lcDiff := float64(abs(lineCount-sig.LineCount)) / float64(sig.LineCount)
-			if wcDiff < 0.10 && lcDiff < 0.10 {
+			if wcDiff < shapeThreshold && lcDiff < shapeThreshold {
 				return true
 			}
 		}
@@ -138,6 +302,32 @@ func MatchesSignature(statusCode, size, wordCount, lineCount int, signatures []R
 	return false
 }
 
+// CalibrationDistance reports how close a response came to matching a
+// baseline for its status code, scaled so 1.0 is the filtering boundary:
+// below 1 MatchesSignature would drop it. It is the smaller of the size
+// difference over sizeThreshold and the larger word/line difference over
+// shapeThreshold, minimized across baselines. ok is false when no baseline
+// shares the status code.
+func CalibrationDistance(statusCode, size, wordCount, lineCount int, signatures []ResponseSignature) (distance float64, ok bool) {
+	for _, sig := range signatures {
+		if statusCode != sig.StatusCode || sig.Size == 0 {
+			continue
+		}
+		d := float64(abs(size-sig.Size)) / float64(sig.Size) / sizeThreshold
+		if sig.WordCount > 0 && sig.LineCount > 0 {
+			wcDiff := float64(abs(wordCount-sig.WordCount)) / float64(sig.WordCount)
+			lcDiff := float64(abs(lineCount-sig.LineCount)) / float64(sig.LineCount)
+			if shape := math.Max(wcDiff, lcDiff) / shapeThreshold; shape < d {
+				d = shape
+			}
+		}
+		if !ok || d < distance {
+			distance, ok = d, true
+		}
+	}
+	return distance, ok
+}
+
 func abs(n int) int {
 	if n < 0 {
 		return -n
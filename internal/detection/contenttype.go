@@ -0,0 +1,70 @@
+package detection
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// sniffLen is how much of the body content-type sniffing looks at, matching
+// http.DetectContentType.
+const sniffLen = 512
+
+// SniffContentType detects the body's media type when the declared
+// Content-Type is missing or generic (application/octet-stream). Returns ""
+// when the declared type is specific enough to be trusted. JSON, which
+// http.DetectContentType reports as text/plain, is recognized separately.
+func SniffContentType(declared string, body []byte) string {
+	if len(body) == 0 || !genericContentType(declared) {
+		return ""
+	}
+
+	sniffed := http.DetectContentType(body)
+	if strings.HasPrefix(sniffed, "text/plain") && looksLikeJSON(body) {
+		return "application/json"
+	}
+	return sniffed
+}
+
+// EffectiveContentType returns the sniffed type when there is one, else the
+// declared type.
+func EffectiveContentType(declared, sniffed string) string {
+	if sniffed != "" {
+		return sniffed
+	}
+	return declared
+}
+
+func genericContentType(declared string) bool {
+	if strings.TrimSpace(declared) == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(declared)
+	return err != nil || mediaType == "application/octet-stream"
+}
+
+// looksLikeJSON checks the first sniffLen bytes: a complete document must
+// be valid JSON, a longer one only has to open like an object or array.
+func looksLikeJSON(body []byte) bool {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return false
+	}
+	if len(body) <= sniffLen {
+		return json.Valid(trimmed)
+	}
+	dec := json.NewDecoder(bytes.NewReader(trimmed[:min(len(trimmed), sniffLen)]))
+	tok, err := dec.Token()
+	if err != nil {
+		return false
+	}
+	_, isDelim := tok.(json.Delim)
+	if !isDelim {
+		return false
+	}
+	// The next token must also parse, ruling out prose that starts with a bracket.
+	_, err = dec.Token()
+	return err == nil
+}
@@ -0,0 +1,44 @@
+package detection
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSniffContentType(t *testing.T) {
+	longJSON := `{"items": [` + strings.Repeat(`"x",`, 200) + `"x"]}`
+
+	tests := []struct {
+		name     string
+		declared string
+		body     string
+		expected string
+	}{
+		{"declared type trusted", "text/html; charset=utf-8", `{"a":1}`, ""},
+		{"missing type, HTML", "", "<!DOCTYPE html><html><body>hi</body></html>", "text/html; charset=utf-8"},
+		{"missing type, JSON", "", `{"user": "admin", "debug": true}`, "application/json"},
+		{"octet-stream JSON array", "application/octet-stream", `[1, 2, 3]`, "application/json"},
+		{"octet-stream HTML", "application/octet-stream", "<html><head></head></html>", "text/html; charset=utf-8"},
+		{"long JSON past sniff window", "", longJSON, "application/json"},
+		{"bracketed prose is not JSON", "", "[Note] the service is down", "text/plain; charset=utf-8"},
+		{"malformed header", "text/", "<html></html>", "text/html; charset=utf-8"},
+		{"empty body", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SniffContentType(tt.declared, []byte(tt.body)); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestEffectiveContentType(t *testing.T) {
+	if got := EffectiveContentType("application/octet-stream", "application/json"); got != "application/json" {
+		t.Errorf("expected sniffed type, got %q", got)
+	}
+	if got := EffectiveContentType("text/html", ""); got != "text/html" {
+		t.Errorf("expected declared type, got %q", got)
+	}
+}
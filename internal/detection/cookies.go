@@ -0,0 +1,38 @@
+package detection
+
+import "net/http"
+
+// Cookie hygiene flags reported by CookieIssues.
+const (
+	CookieMissingSecure        = "missing-secure"
+	CookieMissingHTTPOnly      = "missing-httponly"
+	CookieMissingSameSite      = "missing-samesite"
+	CookieSameSiteNoneNoSecure = "samesite-none-without-secure"
+)
+
+// CookieIssues inspects the Set-Cookie headers of a response and returns one
+// "name: flag" entry per missing security attribute. Cookie values are never
+// included, so the output is safe to store in reports.
+func CookieIssues(resp *http.Response) []string {
+	if resp == nil {
+		return nil
+	}
+
+	var issues []string
+	for _, cookie := range inspectedCookies(resp) {
+		if !cookie.Secure {
+			if cookie.SameSite == http.SameSiteNoneMode {
+				issues = append(issues, cookie.Name+": "+CookieSameSiteNoneNoSecure)
+			} else {
+				issues = append(issues, cookie.Name+": "+CookieMissingSecure)
+			}
+		}
+		if !cookie.HttpOnly {
+			issues = append(issues, cookie.Name+": "+CookieMissingHTTPOnly)
+		}
+		if cookie.SameSite == 0 {
+			issues = append(issues, cookie.Name+": "+CookieMissingSameSite)
+		}
+	}
+	return issues
+}
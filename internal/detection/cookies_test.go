@@ -0,0 +1,59 @@
+package detection
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCookieIssues(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Add("Set-Cookie", "session=s3cr3t-value; Path=/")
+	resp.Header.Add("Set-Cookie", "prefs=dark; Secure; HttpOnly; SameSite=Lax")
+	resp.Header.Add("Set-Cookie", "tracker=abc; HttpOnly; SameSite=None")
+
+	issues := CookieIssues(resp)
+
+	expected := []string{
+		"session: missing-secure",
+		"session: missing-httponly",
+		"session: missing-samesite",
+		"tracker: samesite-none-without-secure",
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, issues)
+	}
+	for i := range expected {
+		if issues[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], issues[i])
+		}
+	}
+
+	for _, issue := range issues {
+		if strings.Contains(issue, "s3cr3t") || strings.Contains(issue, "abc") {
+			t.Errorf("cookie value leaked into %q", issue)
+		}
+	}
+}
+
+func TestCookieIssues_NoCookies(t *testing.T) {
+	if issues := CookieIssues(&http.Response{Header: http.Header{}}); len(issues) != 0 {
+		t.Errorf("expected no issues, got %v", issues)
+	}
+	if issues := CookieIssues(nil); issues != nil {
+		t.Errorf("expected nil for nil response, got %v", issues)
+	}
+}
+
+func TestCookieIssues_ManyCookies(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	for i := 0; i < 5000; i++ {
+		resp.Header.Add("Set-Cookie", fmt.Sprintf("c%d=v; Secure; HttpOnly", i))
+	}
+
+	issues := CookieIssues(resp)
+	if len(issues) != MaxInspectedHeaders {
+		t.Errorf("expected %d issues from the inspected cookies, got %d", MaxInspectedHeaders, len(issues))
+	}
+}
@@ -2,11 +2,13 @@ package detection
 
 import (
 	"context"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 // testSecretFixtures builds fake credential strings at runtime
@@ -25,6 +27,12 @@ func testSecretFixtures() map[string]string {
 		"google_api":  "AI" + "za" + "SyTESTONLY234567890abcdefghijklm_ox",
 		"api_key_val": "test_" + "only_" + "key_1234567890abcdefgh",
 		"private_key": "-----BEGIN " + "TEST" + " KEY-----",
+		"gcp_sa_json": `{"type": "service_account", "project_id": "test-only", "private_key_` + `id": "` + "8f86bebb2737f6a6f0fb" + "23c6f5da2cec255404e4" + `"}`,
+		"gcp_oauth":   "GOC" + "SPX-" + "TestOnly1234567890abcd" + "_-XyZw",
+		"azure_conn": "DefaultEndpointsProtocol=https;AccountName=testonly;Account" + "Key=" +
+			"PtYgjmUhBel31iEl2hpChYgCfrL1sp" + "NxnyVmihA/2O76UMFxFkM/R5Kjp1vR" + "t+1fjORS/6ilI8ihN5KXSc7Tvo" + "==;EndpointSuffix=core.windows.net",
+		"azure_short": "Account" + "Key=" + "/hBKqFYY/kv5ZJr3J1TW" + "DtkwtDDb+xHKas1VOqg6YYZ" + "=",
+		"do_token":    "dop" + "_v1_" + "c3fc1626e53a13043b026c48bbf33fef" + "f9243a8f506b40928b5b7a767c76fb00",
 	}
 }
 
@@ -103,6 +111,44 @@ func TestDetectSecrets(t *testing.T) {
 	}
 }
 
+func TestDetectSecrets_CloudProviders(t *testing.T) {
+	fix := testSecretFixtures()
+
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		severity Severity
+	}{
+		{"GCP service account JSON", fix["gcp_sa_json"], "GCP Service Account Key", SeverityCritical},
+		{"GCP OAuth client secret", `{"client_secret":"` + fix["gcp_oauth"] + `"}`, "GCP OAuth Client Secret", SeverityHigh},
+		{"Azure connection string", fix["azure_conn"], "Azure Storage Account Key", SeverityCritical},
+		{"Azure 44-character key", fix["azure_short"], "Azure Storage Account Key", SeverityCritical},
+		{"DigitalOcean token", "DIGITALOCEAN_TOKEN=" + fix["do_token"], "DigitalOcean Personal Access Token", SeverityCritical},
+		{"placeholder private_key_id", `"private_key_` + `id": "` + strings.Repeat("0", 40) + `"`, "", ""},
+		{"placeholder Azure key", "Account" + "Key=" + strings.Repeat("A", 86) + "==", "", ""},
+		{"DigitalOcean token too short", "dop" + "_v1_" + "c3fc1626e53a13043b026c48bbf33fef", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := DetectSecretsDetailed(tt.content)
+			if tt.expected == "" {
+				if len(matches) != 0 {
+					t.Errorf("expected no secrets, got %v", matches)
+				}
+				return
+			}
+			if len(matches) != 1 || matches[0].Name != tt.expected {
+				t.Fatalf("expected only %q, got %v", tt.expected, matches)
+			}
+			if matches[0].Severity != tt.severity {
+				t.Errorf("expected severity %q, got %q", tt.severity, matches[0].Severity)
+			}
+		})
+	}
+}
+
 func TestDetectSecrets_ReturnedNames(t *testing.T) {
 	fix := testSecretFixtures()
 	secrets := DetectSecrets(fix["aws_key"])
@@ -128,6 +174,72 @@ func TestDetectSecretsDetailed_Severity(t *testing.T) {
 	}
 }
 
+func TestDetectSecretsDetailed_Raw(t *testing.T) {
+	fix := testSecretFixtures()
+	matches := DetectSecretsDetailed(fix["aws_key"])
+	if len(matches) == 0 {
+		t.Fatal("expected at least one match")
+	}
+	if !strings.Contains(fix["aws_key"], matches[0].Raw) || matches[0].Raw == "" {
+		t.Errorf("expected raw match from input, got %q", matches[0].Raw)
+	}
+	if matches[0].Raw == matches[0].Redacted {
+		t.Errorf("expected raw and redacted values to differ, got %q", matches[0].Raw)
+	}
+}
+
+func TestDetectSecretsDetailed_Context(t *testing.T) {
+	fix := testSecretFixtures()
+	key := fix["aws_key"]
+	content := "<html>\n<head>\n<script>\n\tvar cfg = {region: \"eu-west-1\", accessKeyId: \"" + key + "\"};\n</script>\n" +
+		strings.Repeat("<p>filler</p>\n", 20)
+
+	matches := DetectSecretsDetailed(content)
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %v", matches)
+	}
+	m := matches[0]
+	if m.LineNumber != 4 {
+		t.Errorf("expected line 4, got %d", m.LineNumber)
+	}
+	if strings.Contains(m.Context, m.Raw) {
+		t.Errorf("expected the context to be redacted, got %q", m.Context)
+	}
+	if !strings.Contains(m.Context, m.Redacted) || !strings.Contains(m.Context, `region: "eu-west-1"`) {
+		t.Errorf("expected the redacted key with its surroundings, got %q", m.Context)
+	}
+	if strings.ContainsAny(m.Context, "\n\t") {
+		t.Errorf("expected the context on one line, got %q", m.Context)
+	}
+	if !strings.HasPrefix(m.Context, "...") || !strings.HasSuffix(m.Context, "...") {
+		t.Errorf("expected both ends to be marked as cut, got %q", m.Context)
+	}
+	if max := len(m.Raw) + 2*secretContextRadius + 6; len(m.Context) > max {
+		t.Errorf("expected at most %d bytes of context, got %d: %q", max, len(m.Context), m.Context)
+	}
+}
+
+func TestDetectSecretsDetailed_ContextRedactsNeighbours(t *testing.T) {
+	fix := testSecretFixtures()
+	// The JWT starts inside the AWS key's window and runs past its edge, so
+	// the window alone would not match the JWT pattern.
+	content := "id=" + fix["aws_key"] + " token=" + fix["jwt"]
+
+	for _, m := range DetectSecretsDetailed(content) {
+		if strings.Contains(m.Context, fix["aws_key"][:20]) || strings.Contains(m.Context, fix["jwt"][:30]) {
+			t.Errorf("%s: expected neighbouring secrets redacted, got %q", m.Name, m.Context)
+		}
+		if m.LineNumber != 1 {
+			t.Errorf("%s: expected line 1, got %d", m.Name, m.LineNumber)
+		}
+	}
+
+	short := DetectSecretsDetailed("x " + fix["aws_key"])
+	if len(short) != 1 || short[0].Context != "x "+short[0].Redacted+fix["aws_key"][20:] {
+		t.Errorf("expected an uncut context without ellipses, got %+v", short)
+	}
+}
+
 func TestShannonEntropy(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -240,7 +352,7 @@ func TestDetectWAF(t *testing.T) {
 				resp.Header.Add("Set-Cookie", c.String())
 			}
 
-			result := DetectWAF(resp)
+			result := DetectWAF(resp, 200, "")
 			if result != tt.expected {
 				t.Errorf("expected %q, got %q", tt.expected, result)
 			}
@@ -248,6 +360,58 @@ func TestDetectWAF(t *testing.T) {
 	}
 }
 
+func TestDetectWAF_CombinedConditions(t *testing.T) {
+	saved := WAFSignatures
+	defer func() { WAFSignatures = saved }()
+	WAFSignatures = []WAFSignature{
+		{Name: "Edge Block", ServerHeader: "edgeguard", StatusPattern: 403},
+		{Name: "Edge Challenge", ServerHeader: "edgeguard", CookiePattern: "eg_chal", BodyPattern: "verifying you are human"},
+		{Name: "Empty"},
+	}
+
+	tests := []struct {
+		name     string
+		server   string
+		cookie   string
+		status   int
+		body     string
+		expected string
+	}{
+		{"server and status", "EdgeGuard/2.1", "", 403, "", "Edge Block"},
+		{"server without status", "EdgeGuard/2.1", "", 200, "", ""},
+		{"status without server", "nginx", "", 403, "", ""},
+		{"all three conditions", "edgeguard", "eg_chal_42", 200, "<p>Verifying you are human...</p>", "Edge Challenge"},
+		{"missing cookie", "edgeguard", "", 200, "<p>Verifying you are human...</p>", ""},
+		{"missing body text", "edgeguard", "eg_chal_42", 200, "<p>Welcome</p>", ""},
+		{"no conditions never matches", "", "", 200, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: make(http.Header)}
+			if tt.server != "" {
+				resp.Header.Set("Server", tt.server)
+			}
+			if tt.cookie != "" {
+				resp.Header.Add("Set-Cookie", (&http.Cookie{Name: tt.cookie, Value: "1"}).String())
+			}
+			if got := DetectWAF(resp, tt.status, tt.body); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDetectWAF_ImpervaBlockPage(t *testing.T) {
+	resp := &http.Response{Header: make(http.Header)}
+	body := "<html><body>Request unsuccessful. Incapsula incident ID: 123000450012345678-90123456789</body></html>"
+	if got := DetectWAF(resp, 403, body); got != "Imperva" {
+		t.Errorf("expected Imperva on a 403 block page, got %q", got)
+	}
+	if got := DetectWAF(resp, 200, "Our support page explains what an Incapsula incident ID is."); got != "" {
+		t.Errorf("expected no WAF for the phrase on a 200, got %q", got)
+	}
+}
+
 func TestDetectWAFFromBody(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -295,6 +459,25 @@ func TestCalibration(t *testing.T) {
 	}
 }
 
+func TestCalibration_Latency(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+
+	cache := NewCalibrationCache()
+	PerformCalibration(context.Background(), server.URL, &http.Client{}, nil, cache)
+
+	latency, ok := cache.Latency(server.URL)
+	if !ok || latency < 20*time.Millisecond {
+		t.Errorf("expected a baseline of at least 20ms, got %s (ok=%v)", latency, ok)
+	}
+	if _, ok := cache.Latency("http://never-calibrated.example.com"); ok {
+		t.Error("expected no baseline for a target that was not calibrated")
+	}
+}
+
 func TestCalibration_CacheHit(t *testing.T) {
 	cache := NewCalibrationCache()
 
@@ -343,6 +526,65 @@ func TestCalibration_ServerError(t *testing.T) {
 	}
 }
 
+func TestCalibration_Strategies(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Method+" "+r.URL.RequestURI())
+		mu.Unlock()
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+
+	strategies, err := ParseCalibrationStrategies([]string{"random-ext", "random-query", "random-method"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	upper := func(path string) string { return strings.ToUpper(path) }
+	sigs := PerformCalibrationStrategies(context.Background(), server.URL, &http.Client{}, nil, NewCalibrationCache(), upper, strategies)
+
+	if len(sigs) != 4 || len(seen) != 4 {
+		t.Fatalf("expected 4 probes, got %d signatures for %v", len(sigs), seen)
+	}
+	checks := []struct {
+		name  string
+		match func(string) bool
+	}{
+		{"php extension", func(s string) bool {
+			return strings.HasPrefix(s, "GET /CAPSAICIN_CAL_") && strings.HasSuffix(s, ".PHP")
+		}},
+		{"html extension", func(s string) bool { return strings.HasSuffix(s, ".HTML") }},
+		{"query left untransformed", func(s string) bool { return strings.Contains(s, "?cal") }},
+		{"post method", func(s string) bool { return strings.HasPrefix(s, "POST ") }},
+	}
+	for _, c := range checks {
+		found := false
+		for _, s := range seen {
+			if c.match(s) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s: no matching probe in %v", c.name, seen)
+		}
+	}
+}
+
+func TestParseCalibrationStrategies(t *testing.T) {
+	strategies, err := ParseCalibrationStrategies(nil)
+	if err != nil || len(strategies) != 1 {
+		t.Fatalf("expected the default strategy, got %d (%v)", len(strategies), err)
+	}
+	if probes := strategies[0](); len(probes) != 3 {
+		t.Errorf("expected 3 default random-path probes, got %d", len(probes))
+	}
+
+	if _, err := ParseCalibrationStrategies([]string{"random-path", "bogus"}); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
+
 func TestCalibrationCache_Concurrent(t *testing.T) {
 	cache := NewCalibrationCache()
 
@@ -393,6 +635,41 @@ func TestMatchesSignature(t *testing.T) {
 	}
 }
 
+func TestCalibrationDistance(t *testing.T) {
+	signatures := []ResponseSignature{
+		{StatusCode: 404, Size: 100, WordCount: 10, LineCount: 5},
+		{StatusCode: 404, Size: 1000, WordCount: 100, LineCount: 50},
+	}
+
+	tests := []struct {
+		name       string
+		statusCode int
+		size       int
+		wordCount  int
+		lineCount  int
+		want       float64
+		wantOK     bool
+	}{
+		{"exact match", 404, 100, 10, 5, 0, true},
+		{"size just outside threshold", 404, 110, 20, 10, 2, true},
+		{"word and line closer than size", 404, 150, 11, 5, 1, true},
+		{"nearest of several baselines", 404, 1030, 200, 90, 0.6, true},
+		{"no baseline for status", 200, 100, 10, 5, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := CalibrationDistance(tt.statusCode, tt.size, tt.wordCount, tt.lineCount, signatures)
+			if ok != tt.wantOK || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("expected %v (ok=%v), got %v (ok=%v)", tt.want, tt.wantOK, got, ok)
+			}
+			if ok && (got < 1) != MatchesSignature(tt.statusCode, tt.size, tt.wordCount, tt.lineCount, signatures) {
+				t.Errorf("distance %v disagrees with MatchesSignature", got)
+			}
+		})
+	}
+}
+
 func TestMatchesSignature_EmptySignatures(t *testing.T) {
 	result := MatchesSignature(404, 100, 0, 0, nil)
 	if result {
@@ -15,6 +15,7 @@ const (
 	CategoryCMS       TechCategory = "cms"
 	CategoryJSLib     TechCategory = "js-library"
 	CategoryCDN       TechCategory = "cdn"
+	CategoryAPI       TechCategory = "api"
 	CategoryOther     TechCategory = "other"
 )
 
@@ -94,6 +95,11 @@ var techSignatures = []TechSignature{
 	{Name: "Netlify", Category: CategoryCDN, HeaderName: "X-Nf-Request-Id", HeaderValue: ""},
 	{Name: "Firebase", Category: CategoryCDN, HeaderName: "X-Served-By", HeaderValue: "firebase"},
 
+	// ── APIs ──────────────────────────────────────────────────
+	// Introspection results carry a "__schema" key; a page that only
+	// mentions the word does not. See also isGraphQLError.
+	{Name: "GraphQL", Category: CategoryAPI, BodyPattern: `"__schema":`},
+
 	// ── Other ─────────────────────────────────────────────────
 	{Name: "OpenSSL", Category: CategoryOther, HeaderName: "Server", HeaderValue: "openssl"},
 	{Name: "Laravel", Category: CategoryFramework, CookieName: "laravel_session"},
@@ -129,9 +135,36 @@ func DetectTechnologies(resp *http.Response, body string) []TechMatch {
 		}
 	}
 
+	if !seen["GraphQL"] && isGraphQLError(resp, lowerBody) {
+		matches = append(matches, TechMatch{Name: "GraphQL", Category: CategoryAPI})
+	}
+
 	return matches
 }
 
+// isGraphQLError reports whether resp is a GraphQL endpoint rejecting a
+// request that carried no query, e.g. Apollo's
+// {"errors":[{"message":"GraphQL operations must contain a non-empty `query`..."}]}.
+// Body text alone is too common in docs pages, so the request path must
+// end in "graphql" and the body must be a JSON object with an "errors" key
+// that mentions the query.
+func isGraphQLError(resp *http.Response, lowerBody string) bool {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return false
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	path := strings.TrimSuffix(strings.ToLower(resp.Request.URL.Path), "/")
+	if !strings.HasSuffix(path, "graphql") {
+		return false
+	}
+	if !strings.HasPrefix(strings.TrimSpace(lowerBody), "{") {
+		return false
+	}
+	return strings.Contains(lowerBody, `"errors"`) && strings.Contains(lowerBody, "query")
+}
+
 // DetectTechNames is a convenience wrapper that returns just the tech names.
 func DetectTechNames(resp *http.Response, body string) []string {
 	matches := DetectTechnologies(resp, body)
@@ -160,7 +193,7 @@ func matchesTechSignature(resp *http.Response, lowerBody string, sig *TechSignat
 
 	// Cookie match
 	if sig.CookieName != "" {
-		for _, cookie := range resp.Cookies() {
+		for _, cookie := range inspectedCookies(resp) {
 			if strings.Contains(cookie.Name, sig.CookieName) {
 				return true
 			}
@@ -2,6 +2,9 @@ package detection
 
 import (
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -303,3 +306,52 @@ func TestDetectTechnologies_CaseInsensitive(t *testing.T) {
 		t.Error("expected Nginx detection to be case-insensitive")
 	}
 }
+
+func TestDetectTechnologies_GraphQL(t *testing.T) {
+	fixture := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join("testdata", "graphql", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+	response := func(status int, path string) *http.Response {
+		resp := buildResponse(map[string]string{"Content-Type": "application/json; charset=utf-8"}, nil)
+		resp.StatusCode = status
+		resp.Request = &http.Request{URL: &url.URL{Path: path}}
+		return resp
+	}
+
+	tests := []struct {
+		name     string
+		resp     *http.Response
+		body     string
+		expected bool
+	}{
+		{"Apollo introspection", response(200, "/api/data"), fixture("apollo-introspection.json"), true},
+		{"Apollo missing query", response(400, "/graphql"), fixture("apollo-missing-query.json"), true},
+		{"missing query with trailing slash", response(400, "/v1/GraphQL/"), fixture("apollo-missing-query.json"), true},
+		{"error on another path", response(400, "/api/search"), fixture("apollo-missing-query.json"), false},
+		{"error on a 500", response(500, "/graphql"), fixture("apollo-missing-query.json"), false},
+		{"docs page on the endpoint", response(200, "/graphql"), fixture("docs-page.html"), false},
+		{"docs page elsewhere", response(200, "/docs/graphql-api"), fixture("docs-page.html"), false},
+		{"no request", buildResponse(nil, nil), fixture("apollo-missing-query.json"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found := false
+			for _, m := range DetectTechnologies(tt.resp, tt.body) {
+				if m.Name == "GraphQL" {
+					found = true
+					if m.Category != CategoryAPI {
+						t.Errorf("expected category %q, got %q", CategoryAPI, m.Category)
+					}
+				}
+			}
+			if found != tt.expected {
+				t.Errorf("expected GraphQL detected = %v, got %v", tt.expected, found)
+			}
+		})
+	}
+}
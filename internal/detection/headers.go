@@ -0,0 +1,18 @@
+package detection
+
+import "net/http"
+
+// MaxInspectedHeaders bounds how many header names and Set-Cookie lines a
+// detector looks at, so a response carrying thousands of headers costs no
+// more than an ordinary one even if it slipped past the transport limits.
+const MaxInspectedHeaders = 200
+
+// inspectedCookies parses at most MaxInspectedHeaders Set-Cookie lines.
+func inspectedCookies(resp *http.Response) []*http.Cookie {
+	lines := resp.Header.Values("Set-Cookie")
+	if len(lines) <= MaxInspectedHeaders {
+		return resp.Cookies()
+	}
+	limited := &http.Response{Header: http.Header{"Set-Cookie": lines[:MaxInspectedHeaders]}}
+	return limited.Cookies()
+}
@@ -0,0 +1,123 @@
+package detection
+
+import "strings"
+
+// LoginPanelSignature fingerprints a well-known admin or login panel.
+// A panel matches when any BodyPatterns entry is found in the response
+// body, or when PathPattern is found in the URL and the body contains a
+// password input.
+type LoginPanelSignature struct {
+	Name         string
+	BodyPatterns []string // lowercase body substrings, any one matches
+	PathPattern  string   // lowercase URL substring, requires a password field
+	DefaultCreds string   // well-known default credentials, empty if none
+}
+
+// loginPanelSignatures lists the panels most often left with factory
+// credentials. Order matters: the first match wins, so product-specific
+// entries come before the generic login form fallback.
+var loginPanelSignatures = []LoginPanelSignature{
+	{
+		Name:         "phpMyAdmin",
+		BodyPatterns: []string{"<title>phpmyadmin", "pma_username", "phpmyadmin.css"},
+		PathPattern:  "/phpmyadmin",
+		DefaultCreds: "root:(empty)",
+	},
+	{
+		Name:         "Tomcat Manager",
+		BodyPatterns: []string{"tomcat web application manager", "/manager/html/list"},
+		PathPattern:  "/manager/html",
+		DefaultCreds: "tomcat:tomcat, admin:admin",
+	},
+	{
+		Name:         "Jenkins",
+		BodyPatterns: []string{"<title>sign in [jenkins]", "j_acegi_security_check", "jenkins-head-icon"},
+		DefaultCreds: "admin:(initialAdminPassword)",
+	},
+	{
+		Name:         "Grafana",
+		BodyPatterns: []string{"<title>grafana</title>", "grafana-app"},
+		DefaultCreds: "admin:admin",
+	},
+	{
+		Name:         "WordPress Login",
+		BodyPatterns: []string{"wp-login.php", "id=\"loginform\""},
+		PathPattern:  "/wp-login.php",
+	},
+	{
+		Name:         "Joomla Administrator",
+		BodyPatterns: []string{"joomla! administration", "mod-login-username"},
+		PathPattern:  "/administrator",
+	},
+	{
+		Name:         "Adminer",
+		BodyPatterns: []string{"<title>login - adminer", "adminer.org"},
+		PathPattern:  "/adminer",
+	},
+	{
+		Name:         "RabbitMQ Management",
+		BodyPatterns: []string{"<title>rabbitmq management"},
+		DefaultCreds: "guest:guest",
+	},
+	{
+		Name:         "JBoss Console",
+		BodyPatterns: []string{"jboss management console", "<title>welcome to jboss"},
+		PathPattern:  "/jmx-console",
+		DefaultCreds: "admin:admin",
+	},
+	{
+		Name:         "WebLogic Console",
+		BodyPatterns: []string{"oracle weblogic server administration console"},
+		PathPattern:  "/console/login",
+		DefaultCreds: "weblogic:weblogic1",
+	},
+	{
+		Name:         "Router Admin",
+		BodyPatterns: []string{"routerlogin.net", "<title>tp-link", "<title>netgear router", "<title>dd-wrt", "<title>routeros"},
+		DefaultCreds: "admin:admin, admin:password",
+	},
+}
+
+// IdentifyLoginPanel inspects a response body and request URL for a
+// recognizable admin or login panel. It returns the product name, or
+// "Generic Login" for an unrecognized page with a password form, or ""
+// when the page is not a login panel at all.
+func IdentifyLoginPanel(body, url string) string {
+	if body == "" {
+		return ""
+	}
+
+	lowerBody := strings.ToLower(body)
+	lowerURL := strings.ToLower(url)
+	hasPasswordField := strings.Contains(lowerBody, `type="password"`) ||
+		strings.Contains(lowerBody, `type='password'`) ||
+		strings.Contains(lowerBody, `type=password`)
+
+	for _, sig := range loginPanelSignatures {
+		for _, pattern := range sig.BodyPatterns {
+			if strings.Contains(lowerBody, pattern) {
+				return sig.Name
+			}
+		}
+		if sig.PathPattern != "" && hasPasswordField && strings.Contains(lowerURL, sig.PathPattern) {
+			return sig.Name
+		}
+	}
+
+	if hasPasswordField && strings.Contains(lowerBody, "<form") {
+		return "Generic Login"
+	}
+
+	return ""
+}
+
+// DefaultCredentials returns the well-known default credentials for a
+// panel name returned by IdentifyLoginPanel, or "" if none are known.
+func DefaultCredentials(panel string) string {
+	for _, sig := range loginPanelSignatures {
+		if sig.Name == panel {
+			return sig.DefaultCreds
+		}
+	}
+	return ""
+}
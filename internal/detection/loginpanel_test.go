@@ -0,0 +1,93 @@
+package detection
+
+import "testing"
+
+func TestIdentifyLoginPanel(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		url      string
+		expected string
+	}{
+		{
+			"phpMyAdmin by title",
+			`<html><head><title>phpMyAdmin</title></head><body><input name="pma_username"></body></html>`,
+			"http://example.com/pma/",
+			"phpMyAdmin",
+		},
+		{
+			"Tomcat Manager",
+			`<h1>Tomcat Web Application Manager</h1>`,
+			"http://example.com/manager/html",
+			"Tomcat Manager",
+		},
+		{
+			"Jenkins sign-in",
+			`<title>Sign in [Jenkins]</title><form action="j_acegi_security_check">`,
+			"http://example.com/login",
+			"Jenkins",
+		},
+		{
+			"Grafana",
+			`<title>Grafana</title><div class="grafana-app"></div>`,
+			"http://example.com/",
+			"Grafana",
+		},
+		{
+			"RabbitMQ",
+			`<title>RabbitMQ Management</title>`,
+			"http://example.com:15672/",
+			"RabbitMQ Management",
+		},
+		{
+			"router",
+			`<html><title>TP-LINK Wireless Router</title></html>`,
+			"http://192.168.0.1/",
+			"Router Admin",
+		},
+		{
+			"path plus password field",
+			`<form><input type="password" name="pw"></form>`,
+			"http://example.com/phpMyAdmin/index.php",
+			"phpMyAdmin",
+		},
+		{
+			"generic login form",
+			`<form method="post"><input type="text"><input type="password"></form>`,
+			"http://example.com/signin",
+			"Generic Login",
+		},
+		{
+			"no login",
+			`<html><body>Hello world</body></html>`,
+			"http://example.com/",
+			"",
+		},
+		{
+			"empty body",
+			"",
+			"http://example.com/phpmyadmin",
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IdentifyLoginPanel(tt.body, tt.url); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDefaultCredentials(t *testing.T) {
+	if DefaultCredentials("Tomcat Manager") == "" {
+		t.Error("expected default credentials for Tomcat Manager")
+	}
+	if DefaultCredentials("Generic Login") != "" {
+		t.Error("expected no default credentials for a generic login form")
+	}
+	if DefaultCredentials("Unknown") != "" {
+		t.Error("expected no default credentials for an unknown panel")
+	}
+}
@@ -0,0 +1,142 @@
+package detection
+
+import (
+	"encoding/json"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MaxManifestSize caps how much of a manifest body is parsed. Larger
+// bodies are truncated first; the parsers tolerate the cut.
+const MaxManifestSize = 512 * 1024
+
+// manifestParsers maps well-known manifest file names to a parser that
+// returns "name@version" entries, or nil when the body does not look like
+// that format (e.g. a soft-404 page served at the path).
+var manifestParsers = map[string]func(string) []string{
+	"package.json":     parseJSONManifest("dependencies", "devDependencies", "peerDependencies", "optionalDependencies"),
+	"composer.json":    parseJSONManifest("require", "require-dev"),
+	"gemfile.lock":     parseGemfileLock,
+	"requirements.txt": parseRequirements,
+}
+
+var (
+	// jsonDepBlockPattern finds a dependency object in JSON too broken to
+	// unmarshal; jsonDepEntryPattern then reads its string pairs.
+	jsonDepBlockPattern = regexp.MustCompile(`"([A-Za-z-]+)"\s*:\s*\{([^{}]*)`)
+	jsonDepEntryPattern = regexp.MustCompile(`"([^"]+)"\s*:\s*"([^"]*)"`)
+	gemSpecPattern      = regexp.MustCompile(`^ {4}([A-Za-z0-9_.-]+) \(([^)]+)\)$`)
+	requirementPattern  = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?\s*(?:(===|==|>=|<=|~=|!=|>|<)\s*([^\s;,#]+))?`)
+)
+
+// ParseManifest returns the dependencies declared by a package manifest
+// served at rawURL, as sorted "name@version" entries (just "name" when no
+// version is pinned). Returns nil when the path is not a known manifest or
+// the body does not match its format.
+func ParseManifest(rawURL, body string) []string {
+	p := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		p = u.Path
+	}
+	parse, ok := manifestParsers[strings.ToLower(path.Base(p))]
+	if !ok || body == "" {
+		return nil
+	}
+	if len(body) > MaxManifestSize {
+		body = body[:MaxManifestSize]
+	}
+
+	deps := parse(body)
+	sort.Strings(deps)
+	return deps
+}
+
+// parseJSONManifest reads the named dependency objects from a JSON
+// manifest, falling back to a regex scan when the document is truncated or
+// otherwise fails to unmarshal.
+func parseJSONManifest(keys ...string) func(string) []string {
+	return func(body string) []string {
+		var doc map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(body), &doc); err == nil {
+			var deps []string
+			for _, key := range keys {
+				var section map[string]interface{}
+				if json.Unmarshal(doc[key], &section) != nil {
+					continue
+				}
+				for name, v := range section {
+					version, _ := v.(string)
+					deps = append(deps, dependency(name, version))
+				}
+			}
+			return deps
+		}
+
+		wanted := make(map[string]bool, len(keys))
+		for _, key := range keys {
+			wanted[key] = true
+		}
+		var deps []string
+		for _, block := range jsonDepBlockPattern.FindAllStringSubmatch(body, -1) {
+			if !wanted[block[1]] {
+				continue
+			}
+			for _, m := range jsonDepEntryPattern.FindAllStringSubmatch(block[2], -1) {
+				deps = append(deps, dependency(m[1], m[2]))
+			}
+		}
+		return deps
+	}
+}
+
+// parseGemfileLock reads the resolved gems from the specs: sections of a
+// Gemfile.lock. Only top-level specs (four-space indent) are reported;
+// their nested requirements are constraints, not installed versions.
+func parseGemfileLock(body string) []string {
+	if !strings.Contains(body, "specs:") {
+		return nil
+	}
+	var deps []string
+	for _, line := range strings.Split(body, "\n") {
+		if m := gemSpecPattern.FindStringSubmatch(strings.TrimRight(line, "\r")); m != nil {
+			deps = append(deps, dependency(m[1], m[2]))
+		}
+	}
+	return deps
+}
+
+// parseRequirements reads a pip requirements file. Options (-r, -e,
+// --index-url) and comments are skipped. Any other line that does not
+// parse as a requirement means the body is not a requirements file at all;
+// an HTML page served at the path would otherwise yield garbage names.
+func parseRequirements(body string) []string {
+	var deps []string
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		m := requirementPattern.FindStringSubmatch(line)
+		if m == nil {
+			return nil
+		}
+		if rest := strings.TrimSpace(line[len(m[0]):]); rest != "" && rest[0] != ';' && rest[0] != ',' {
+			return nil
+		}
+		deps = append(deps, dependency(m[1], m[4]))
+	}
+	return deps
+}
+
+func dependency(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + "@" + version
+}
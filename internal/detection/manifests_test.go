@@ -0,0 +1,80 @@
+package detection
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readManifestFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "manifests", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return string(data)
+}
+
+func TestParseManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		fixture string
+		want    []string
+	}{
+		{"package.json", "https://example.com/package.json", "package.json",
+			[]string{"express@^4.17.1", "jest@~29.0.0", "lodash@4.17.20"}},
+		{"truncated package.json", "https://example.com/package.json", "package.truncated.json",
+			[]string{"express@^4.17.1", "lodash@4.17.20"}},
+		{"composer.json", "https://example.com/app/composer.json?x=1", "composer.json",
+			[]string{"guzzlehttp/guzzle@^7.0.1", "laravel/framework@^8.0", "php@>=7.4", "phpunit/phpunit@^9.5"}},
+		{"Gemfile.lock", "https://example.com/Gemfile.lock", "Gemfile.lock",
+			[]string{"actionpack@6.1.4", "nokogiri@1.12.5-x86_64-linux", "rack@2.2.3"}},
+		{"requirements.txt", "https://example.com/requirements.txt", "requirements.txt",
+			[]string{"Django@3.2.5", "celery@5.1", "gunicorn", "pyyaml@5.4", "requests@2.25.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseManifest(tt.url, readManifestFixture(t, tt.fixture))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseManifest_NoMatch(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		body string
+	}{
+		{"unknown path", "https://example.com/index.php", readManifestFixture(t, "package.json")},
+		{"html at package.json", "https://example.com/package.json", "<html><body>Not Found</body></html>"},
+		{"html at requirements.txt", "https://example.com/requirements.txt", "<html>\n<body>Not Found</body>\n</html>"},
+		{"prose at requirements.txt", "https://example.com/requirements.txt", "Page not found"},
+		{"html at Gemfile.lock", "https://example.com/Gemfile.lock", "<html>nothing here</html>"},
+		{"empty body", "https://example.com/composer.json", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseManifest(tt.url, tt.body); len(got) != 0 {
+				t.Errorf("expected no dependencies, got %v", got)
+			}
+		})
+	}
+}
+
+func TestParseManifest_SizeGuard(t *testing.T) {
+	body := "flask==2.0.1\n" + strings.Repeat("x", MaxManifestSize) + "\nlate==1.0\n"
+	got := ParseManifest("https://example.com/requirements.txt", body)
+	for _, dep := range got {
+		if dep == "late@1.0" {
+			t.Error("expected content past MaxManifestSize to be ignored")
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package detection
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlTagPattern    = regexp.MustCompile(`<([a-zA-Z][a-zA-Z0-9]*)\b[^>]*>`)
+	httpAttrPattern   = regexp.MustCompile(`(?i)\b(src|href|action|data|poster|formaction)\s*=\s*["']?(http://[^"'\s>]+)`)
+	httpCSSURLPattern = regexp.MustCompile(`(?i)url\(\s*["']?(http://[^"')\s]+)`)
+)
+
+// MixedContent returns the plain-http URLs referenced by an https page:
+// form actions and subresources (scripts, styles, images, frames, CSS
+// url()). Ordinary <a href> links are navigation, not mixed content, and
+// are ignored. Returns nil when baseURL is not https.
+func MixedContent(baseURL, body string) []string {
+	if !strings.HasPrefix(strings.ToLower(baseURL), "https://") || body == "" {
+		return nil
+	}
+
+	var urls []string
+	seen := make(map[string]bool)
+	add := func(u string) {
+		if !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+
+	for _, tag := range htmlTagPattern.FindAllStringSubmatch(body, -1) {
+		tagName := strings.ToLower(tag[1])
+		for _, attr := range httpAttrPattern.FindAllStringSubmatch(tag[0], -1) {
+			if tagName == "a" && strings.EqualFold(attr[1], "href") {
+				continue
+			}
+			add(attr[2])
+		}
+	}
+
+	for _, match := range httpCSSURLPattern.FindAllStringSubmatch(body, -1) {
+		add(match[1])
+	}
+
+	return urls
+}
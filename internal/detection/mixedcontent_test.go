@@ -0,0 +1,48 @@
+package detection
+
+import "testing"
+
+func TestMixedContent(t *testing.T) {
+	body := `<html><head>
+<script src="http://cdn.example.com/app.js"></script>
+<link rel="stylesheet" href='http://cdn.example.com/site.css'>
+<style>body { background: url(http://img.example.com/bg.png); }</style>
+</head><body>
+<a href="http://example.org/docs">docs</a>
+<img src="https://img.example.com/logo.png">
+<form method="post" action="http://example.com/login"><button formaction=http://example.com/alt>Go</button></form>
+<script src="http://cdn.example.com/app.js"></script>
+</body></html>`
+
+	got := MixedContent("https://example.com/login", body)
+	expected := []string{
+		"http://cdn.example.com/app.js",
+		"http://cdn.example.com/site.css",
+		"http://example.com/login",
+		"http://example.com/alt",
+		"http://img.example.com/bg.png",
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected %q at %d, got %q", expected[i], i, got[i])
+		}
+	}
+}
+
+func TestMixedContent_HTTPPage(t *testing.T) {
+	body := `<form action="http://example.com/login"></form>`
+	if got := MixedContent("http://example.com/", body); got != nil {
+		t.Errorf("expected nil for an http page, got %v", got)
+	}
+}
+
+func TestMixedContent_Clean(t *testing.T) {
+	body := `<script src="/app.js"></script><form action="https://example.com/login"></form>`
+	if got := MixedContent("https://example.com/", body); len(got) != 0 {
+		t.Errorf("expected no mixed content, got %v", got)
+	}
+}
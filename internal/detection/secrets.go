@@ -4,6 +4,7 @@ import (
 	"math"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 type Severity string
@@ -23,12 +24,23 @@ type SecretPattern struct {
 	RequireContext bool
 }
 
+// SecretMatch is one detected secret. Raw holds the unredacted match and
+// must only be written to output when the user explicitly asked for it.
+// LineNumber is 1-based; Context is the text around the match with every
+// secret in it redacted.
 type SecretMatch struct {
-	Name     string
-	Severity Severity
-	Redacted string
+	Name       string   `json:"name"`
+	Severity   Severity `json:"severity"`
+	Redacted   string   `json:"redacted"`
+	Raw        string   `json:"-"`
+	LineNumber int      `json:"line"`
+	Context    string   `json:"context"`
 }
 
+// secretContextRadius is how many bytes of text either side of a match
+// SecretMatch.Context keeps.
+const secretContextRadius = 40
+
 var Patterns = []SecretPattern{
 	{
 		Name:     "AWS Access Key",
@@ -101,6 +113,31 @@ var Patterns = []SecretPattern{
 		Pattern:  regexp.MustCompile(`SK[0-9a-fA-F]{32}`),
 		Severity: SeverityHigh,
 	},
+	{
+		// The private_key_id field of a service-account JSON key file.
+		Name:       "GCP Service Account Key",
+		Pattern:    regexp.MustCompile(`"private_key_id"\s*:\s*"[0-9a-f]{40}"`),
+		Severity:   SeverityCritical,
+		MinEntropy: 3.0,
+	},
+	{
+		Name:     "GCP OAuth Client Secret",
+		Pattern:  regexp.MustCompile(`GOCSPX-[0-9A-Za-z_-]{28}`),
+		Severity: SeverityHigh,
+	},
+	{
+		// Storage account keys are 64 bytes (88 base64 characters); some
+		// services issue 32-byte keys (44 characters).
+		Name:       "Azure Storage Account Key",
+		Pattern:    regexp.MustCompile(`AccountKey=([0-9A-Za-z+/]{86}==|[0-9A-Za-z+/]{43}=)`),
+		Severity:   SeverityCritical,
+		MinEntropy: 4.0,
+	},
+	{
+		Name:     "DigitalOcean Personal Access Token",
+		Pattern:  regexp.MustCompile(`dop_v1_[a-f0-9]{64}`),
+		Severity: SeverityCritical,
+	},
 	{
 		Name:       "Generic Password",
 		Pattern:    regexp.MustCompile(`(?i)(password|passwd|pwd)["'\s:=]+([^\s"']{8,})`),
@@ -148,10 +185,11 @@ func DetectSecretsDetailed(content string) []SecretMatch {
 	secretMap := make(map[string]bool)
 
 	for _, pattern := range Patterns {
-		match := pattern.Pattern.FindString(content)
-		if match == "" {
+		loc := pattern.Pattern.FindStringIndex(content)
+		if loc == nil {
 			continue
 		}
+		match := content[loc[0]:loc[1]]
 
 		if secretMap[pattern.Name] {
 			continue
@@ -166,15 +204,74 @@ func DetectSecretsDetailed(content string) []SecretMatch {
 
 		secretMap[pattern.Name] = true
 		foundSecrets = append(foundSecrets, SecretMatch{
-			Name:     pattern.Name,
-			Severity: pattern.Severity,
-			Redacted: RedactSecret(match),
+			Name:       pattern.Name,
+			Severity:   pattern.Severity,
+			Redacted:   RedactSecret(match),
+			Raw:        match,
+			LineNumber: strings.Count(content[:loc[0]], "\n") + 1,
+			Context:    secretContext(content, loc[0], loc[1]),
 		})
 	}
 
 	return foundSecrets
 }
 
+// secretContext returns the text within secretContextRadius bytes of the
+// match content[start:end], on one line and with "..." where it was cut.
+// Every secret in the window is redacted, including one that the window
+// edge cuts in half: redaction runs over a wider span first, and since
+// RedactSecret keeps lengths the offsets still line up.
+func secretContext(content string, start, end int) string {
+	const margin = 512
+	lo := max(0, start-secretContextRadius-margin)
+	hi := min(len(content), end+secretContextRadius+margin)
+	span := redactSecrets(content[lo:hi])
+	span = span[:start-lo] + RedactSecret(content[start:end]) + span[end-lo:]
+
+	from := max(lo, start-secretContextRadius) - lo
+	to := min(hi, end+secretContextRadius) - lo
+	for from > 0 && !utf8.RuneStart(span[from]) {
+		from--
+	}
+	for to < len(span) && !utf8.RuneStart(span[to]) {
+		to++
+	}
+
+	window := strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		return r
+	}, span[from:to])
+	if lo+from > 0 {
+		window = "..." + window
+	}
+	if lo+to < len(content) {
+		window += "..."
+	}
+	return window
+}
+
+// redactSecrets replaces every match of every pattern in s with its
+// RedactSecret form.
+func redactSecrets(s string) string {
+	for _, pattern := range Patterns {
+		s = pattern.Pattern.ReplaceAllStringFunc(s, RedactSecret)
+	}
+	return s
+}
+
+// SecretSeverity returns the severity of the named secret pattern, or ""
+// when no pattern has that name.
+func SecretSeverity(name string) Severity {
+	for _, pattern := range Patterns {
+		if pattern.Name == name {
+			return pattern.Severity
+		}
+	}
+	return ""
+}
+
 func extractValue(match string) string {
 	for _, sep := range []string{"=", ":", "\"", "'"} {
 		if idx := strings.LastIndex(match, sep); idx >= 0 {
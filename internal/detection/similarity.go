@@ -0,0 +1,94 @@
+package detection
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"unicode"
+)
+
+// SimHash returns a 64-bit locality-sensitive fingerprint of body: pages
+// that share most of their words get hashes that differ in few bits. Words
+// are lowercased and counted once each, so a list that grows or shrinks
+// does not outweigh the rest of the template. Words containing a digit are
+// dropped, so request IDs, timestamps and echoed random probe paths do not
+// move the hash. An empty or digit-only body hashes to 0.
+func SimHash(body []byte) uint64 {
+	var weights [64]int
+	seen := make(map[string]bool)
+	start := -1
+	runes := []rune(string(body))
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		word := runes[start:end]
+		start = -1
+		for _, r := range word {
+			if unicode.IsDigit(r) {
+				return
+			}
+		}
+		token := string(word)
+		if seen[token] {
+			return
+		}
+		seen[token] = true
+		h := fnv.New64a()
+		h.Write([]byte(token))
+		sum := h.Sum64()
+		for i := range weights {
+			if sum&(1<<uint(i)) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+	for i, r := range runes {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			runes[i] = unicode.ToLower(r)
+			continue
+		}
+		flush(i)
+	}
+	flush(len(runes))
+
+	if len(seen) == 0 {
+		return 0
+	}
+	var hash uint64
+	for i, w := range weights {
+		if w > 0 {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// Similarity compares two SimHash values: 1.0 for identical hashes, about
+// 0.5 for unrelated pages.
+func Similarity(a, b uint64) float64 {
+	return 1 - float64(bits.OnesCount64(a^b))/64
+}
+
+// MatchesBodySimilarity reports whether a response is a soft 404 by
+// content: a baseline with the same status code has a body whose SimHash
+// is at least threshold similar to bodyHash. Baselines without a body
+// never match, and neither does an empty response.
+func MatchesBodySimilarity(statusCode int, bodyHash uint64, threshold float64, signatures []ResponseSignature) bool {
+	if bodyHash == 0 {
+		return false
+	}
+	for _, sig := range signatures {
+		if statusCode != sig.StatusCode || sig.BodyHash == 0 {
+			continue
+		}
+		if Similarity(bodyHash, sig.BodyHash) >= threshold {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package detection
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func notFoundPage(path string, items int) string {
+	var b strings.Builder
+	b.WriteString("<html><head><title>Page not found - Example Store</title></head><body>")
+	b.WriteString("<nav>Home Products Cart Account Help Orders Wishlist Stores Gift cards</nav>")
+	fmt.Fprintf(&b, "<h1>Sorry, we could not find %s</h1>", path)
+	b.WriteString("<p>The page you requested does not exist. Try one of our popular categories instead.</p><ul>")
+	for i := 0; i < items; i++ {
+		fmt.Fprintf(&b, "<li>Category %d featured deals</li>", i)
+	}
+	b.WriteString("</ul><p>Still lost? Search the catalogue, check the address for typos or contact our support team.</p>")
+	b.WriteString("<footer>Copyright Example Store, all rights reserved. Privacy policy, terms of service, cookie settings.</footer></body></html>")
+	return b.String()
+}
+
+func TestSimHash_Templated404(t *testing.T) {
+	baseline := SimHash([]byte(notFoundPage("/x7f3k2q9", 3)))
+	for _, tc := range []struct {
+		path  string
+		items int
+	}{
+		{"/admin", 3},
+		{"/backup.zip", 4},
+		{"/a1b2c3d4e5", 2},
+	} {
+		got := Similarity(baseline, SimHash([]byte(notFoundPage(tc.path, tc.items))))
+		if got < 0.9 {
+			t.Errorf("expected %s with %d items to be at least 0.9 similar, got %.2f", tc.path, tc.items, got)
+		}
+	}
+
+	page := "<html><head><title>Admin login - Example Store</title></head><body>" +
+		"<form><label>Username</label><input name=user><label>Password</label>" +
+		"<input name=pass type=password><button>Sign in</button></form>" +
+		"<p>Forgot your password? Contact the site administrator.</p></body></html>"
+	if got := Similarity(baseline, SimHash([]byte(page))); got >= 0.9 {
+		t.Errorf("expected a different page to score below 0.9, got %.2f", got)
+	}
+}
+
+func TestSimHash_Empty(t *testing.T) {
+	for _, body := range []string{"", "   \n", "12345 67890", "<>/"} {
+		if got := SimHash([]byte(body)); got != 0 {
+			t.Errorf("SimHash(%q) = %x, want 0", body, got)
+		}
+	}
+	if SimHash([]byte("Not Found")) != SimHash([]byte("not found")) {
+		t.Error("expected SimHash to ignore case")
+	}
+}
+
+func TestMatchesBodySimilarity(t *testing.T) {
+	baseline := SimHash([]byte(notFoundPage("/x7f3k2q9", 3)))
+	signatures := []ResponseSignature{
+		{StatusCode: 404},
+		{StatusCode: 200, Size: 900, BodyHash: baseline},
+	}
+	soft404 := SimHash([]byte(notFoundPage("/admin", 5)))
+
+	tests := []struct {
+		name       string
+		statusCode int
+		bodyHash   uint64
+		expected   bool
+	}{
+		{"similar body same status", 200, soft404, true},
+		{"similar body different status", 302, soft404, false},
+		{"empty response", 200, 0, false},
+		{"baseline without body", 404, soft404, false},
+		{"unrelated body", 200, SimHash([]byte("user id email created role admin")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesBodySimilarity(tt.statusCode, tt.bodyHash, 0.9, signatures); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
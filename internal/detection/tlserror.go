@@ -0,0 +1,71 @@
+package detection
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// insecureHint is appended to certificate diagnostics; -k skips the
+// verification that produced them.
+const insecureHint = " (use -k to skip verification)"
+
+// DescribeTLSError turns a certificate or handshake failure into a short
+// diagnostic such as "certificate expired 2023-01-01" or "hostname
+// mismatch". Certificate problems carry a hint about -k. Returns "" when
+// err is not TLS-related.
+func DescribeTLSError(err error) string {
+	if err == nil {
+		return ""
+	}
+	if diag := describeCertificateError(err); diag != "" {
+		return diag + insecureHint
+	}
+
+	var record tls.RecordHeaderError
+	if errors.As(err, &record) {
+		return "server did not answer with TLS (plain HTTP on an https URL?)"
+	}
+
+	if msg := err.Error(); strings.Contains(msg, "tls: ") {
+		diag := "TLS handshake failed: " + msg[strings.Index(msg, "tls: ")+len("tls: "):]
+		if strings.Contains(msg, "protocol version") {
+			// Go offers TLS 1.2+ by default; legacy servers need a lower floor.
+			diag += " (try --tls-min-version 1.0)"
+		}
+		return diag
+	}
+	return ""
+}
+
+// describeCertificateError describes a failed certificate verification, or
+// returns "" for any other error.
+func describeCertificateError(err error) string {
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &invalid) {
+		if invalid.Reason == x509.Expired && invalid.Cert != nil {
+			if time.Now().Before(invalid.Cert.NotBefore) {
+				return "certificate not valid until " + invalid.Cert.NotBefore.Format("2006-01-02")
+			}
+			return "certificate expired " + invalid.Cert.NotAfter.Format("2006-01-02")
+		}
+		return "invalid certificate: " + invalid.Error()
+	}
+
+	var hostname x509.HostnameError
+	if errors.As(err, &hostname) {
+		if hostname.Certificate != nil && len(hostname.Certificate.DNSNames) > 0 {
+			return fmt.Sprintf("hostname mismatch: certificate is valid for %s, not %s", strings.Join(hostname.Certificate.DNSNames, ", "), hostname.Host)
+		}
+		return "hostname mismatch: certificate is not valid for " + hostname.Host
+	}
+
+	var unknown x509.UnknownAuthorityError
+	if errors.As(err, &unknown) {
+		return "certificate signed by unknown authority (self-signed?)"
+	}
+	return ""
+}
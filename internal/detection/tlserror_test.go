@@ -0,0 +1,72 @@
+package detection
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDescribeTLSError(t *testing.T) {
+	expired := &x509.Certificate{
+		NotBefore: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:  time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	wrap := func(err error) error {
+		return &url.Error{Op: "Get", URL: "https://example.com/", Err: fmt.Errorf("tls: failed to verify certificate: %w", err)}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"not tls", errors.New("connection refused"), ""},
+		{"expired", wrap(x509.CertificateInvalidError{Cert: expired, Reason: x509.Expired}), "certificate expired 2023-01-01 (use -k to skip verification)"},
+		{"hostname", wrap(x509.HostnameError{Certificate: &x509.Certificate{DNSNames: []string{"a.example.com"}}, Host: "b.example.com"}),
+			"hostname mismatch: certificate is valid for a.example.com, not b.example.com (use -k to skip verification)"},
+		{"unknown authority", wrap(x509.UnknownAuthorityError{}), "certificate signed by unknown authority (self-signed?) (use -k to skip verification)"},
+		{"alert", errors.New("remote error: tls: handshake failure"), "TLS handshake failed: handshake failure"},
+		{"legacy server", errors.New("tls: server selected unsupported protocol version 301"),
+			"TLS handshake failed: server selected unsupported protocol version 301 (try --tls-min-version 1.0)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DescribeTLSError(tt.err); got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCalibrationRecordsTLSError(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(404)
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // expected handshake failures
+	server.StartTLS()
+	defer server.Close()
+
+	cache := NewCalibrationCache()
+	PerformCalibration(context.Background(), server.URL, &http.Client{Timeout: 5 * time.Second}, nil, cache)
+
+	if got := cache.TLSError(server.URL); !strings.Contains(got, "unknown authority") {
+		t.Errorf("expected unknown authority diagnostic, got %q", got)
+	}
+
+	cache = NewCalibrationCache()
+	PerformCalibration(context.Background(), server.URL, server.Client(), nil, cache)
+	if got := cache.TLSError(server.URL); got != "" {
+		t.Errorf("expected no diagnostic for a trusted certificate, got %q", got)
+	}
+}
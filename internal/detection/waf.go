@@ -5,19 +5,26 @@ import (
 	"strings"
 )
 
+// WAFSignature identifies a WAF by one or more conditions. Every
+// non-empty field must hold for the signature to match, so combining
+// fields narrows it: a vendor header alone, or a block page only when the
+// response is also a 403.
 type WAFSignature struct {
 	Name          string
-	ServerHeader  string
-	CustomHeader  string
-	CookiePattern string
-	BodyPattern   string
-	StatusPattern int
+	ServerHeader  string // substring of the Server header (case-insensitive)
+	CustomHeader  string // substring of any header name (case-insensitive)
+	CookiePattern string // substring of a Set-Cookie name
+	BodyPattern   string // substring of the body (case-insensitive)
+	StatusPattern int    // exact status code
 }
 
 var WAFSignatures = []WAFSignature{
+	{
+		Name:         "Cloudflare",
+		ServerHeader: "cloudflare",
+	},
 	{
 		Name:          "Cloudflare",
-		ServerHeader:  "cloudflare",
 		CookiePattern: "__cfduid",
 	},
 	{
@@ -36,6 +43,11 @@ var WAFSignatures = []WAFSignature{
 		Name:          "F5 BigIP",
 		CookiePattern: "BIGipServer",
 	},
+	{
+		Name:          "Imperva",
+		BodyPattern:   "Incapsula incident ID",
+		StatusPattern: 403,
+	},
 	{
 		Name:         "Sucuri",
 		ServerHeader: "Sucuri",
@@ -82,32 +94,78 @@ var WAFSignatures = []WAFSignature{
 	},
 }
 
-func DetectWAF(resp *http.Response) string {
+// DetectWAF returns the name of the first signature in WAFSignatures whose
+// conditions all hold for resp, its status code and body, or "" if none do.
+func DetectWAF(resp *http.Response, statusCode int, body string) string {
+	lowerBody := strings.ToLower(body)
 	for _, waf := range WAFSignatures {
-		if waf.ServerHeader != "" {
-			if server := resp.Header.Get("Server"); strings.Contains(strings.ToLower(server), strings.ToLower(waf.ServerHeader)) {
-				return waf.Name
-			}
+		if matchesWAFSignature(resp, statusCode, lowerBody, &waf) {
+			return waf.Name
+		}
+	}
+	return ""
+}
+
+// matchesWAFSignature reports whether every non-empty condition of waf
+// holds. A signature with no conditions never matches.
+func matchesWAFSignature(resp *http.Response, statusCode int, lowerBody string, waf *WAFSignature) bool {
+	conditions := 0
+
+	if waf.StatusPattern != 0 {
+		conditions++
+		if statusCode != waf.StatusPattern {
+			return false
 		}
+	}
+
+	if waf.ServerHeader != "" {
+		conditions++
+		server := resp.Header.Get("Server")
+		if !strings.Contains(strings.ToLower(server), strings.ToLower(waf.ServerHeader)) {
+			return false
+		}
+	}
 
-		if waf.CustomHeader != "" {
-			for header := range resp.Header {
-				if strings.Contains(strings.ToLower(header), strings.ToLower(waf.CustomHeader)) {
-					return waf.Name
-				}
+	if waf.CustomHeader != "" {
+		conditions++
+		found := false
+		inspected := 0
+		for header := range resp.Header {
+			if inspected++; inspected > MaxInspectedHeaders {
+				break
+			}
+			if strings.Contains(strings.ToLower(header), strings.ToLower(waf.CustomHeader)) {
+				found = true
+				break
 			}
 		}
+		if !found {
+			return false
+		}
+	}
 
-		if waf.CookiePattern != "" {
-			for _, cookie := range resp.Cookies() {
-				if strings.Contains(cookie.Name, waf.CookiePattern) {
-					return waf.Name
-				}
+	if waf.CookiePattern != "" {
+		conditions++
+		found := false
+		for _, cookie := range inspectedCookies(resp) {
+			if strings.Contains(cookie.Name, waf.CookiePattern) {
+				found = true
+				break
 			}
 		}
+		if !found {
+			return false
+		}
 	}
 
-	return ""
+	if waf.BodyPattern != "" {
+		conditions++
+		if !strings.Contains(lowerBody, strings.ToLower(waf.BodyPattern)) {
+			return false
+		}
+	}
+
+	return conditions > 0
 }
 
 func DetectWAFFromBody(body string, statusCode int) string {
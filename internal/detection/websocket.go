@@ -0,0 +1,38 @@
+package detection
+
+import (
+	"net/http"
+	"strings"
+)
+
+// webSocketBodyPrefix caps how much of a 400 body is searched for a
+// WebSocket handshake error; handshake errors are short.
+const webSocketBodyPrefix = 1024
+
+// WebSocketUpgrade reports whether a response comes from a WebSocket
+// endpoint. A plain GET that reaches a handshake handler is usually
+// refused with 426 Upgrade Required or 400 Bad Request, so besides 101
+// Switching Protocols this matches a 400 or 426 that names websocket in
+// its Upgrade or Sec-WebSocket-Version header or, for libraries that only
+// explain in the body, in the start of the body.
+func WebSocketUpgrade(statusCode int, header http.Header, body string) bool {
+	switch statusCode {
+	case http.StatusSwitchingProtocols:
+		return strings.EqualFold(header.Get("Upgrade"), "websocket")
+	case http.StatusBadRequest, http.StatusUpgradeRequired:
+	default:
+		return false
+	}
+	if header.Get("Sec-WebSocket-Version") != "" {
+		return true
+	}
+	for _, upgrade := range strings.Split(header.Get("Upgrade"), ",") {
+		if strings.EqualFold(strings.TrimSpace(upgrade), "websocket") {
+			return true
+		}
+	}
+	if len(body) > webSocketBodyPrefix {
+		body = body[:webSocketBodyPrefix]
+	}
+	return strings.Contains(strings.ToLower(body), "websocket")
+}
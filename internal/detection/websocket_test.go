@@ -0,0 +1,38 @@
+package detection
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWebSocketUpgrade(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		headers map[string]string
+		body    string
+		want    bool
+	}{
+		{"switching protocols", 101, map[string]string{"Upgrade": "websocket", "Connection": "Upgrade"}, "", true},
+		{"switching to h2c", 101, map[string]string{"Upgrade": "h2c"}, "", false},
+		{"426 with upgrade header", 426, map[string]string{"Upgrade": "WebSocket", "Connection": "Upgrade"}, "Upgrade Required", true},
+		{"426 listing several protocols", 426, map[string]string{"Upgrade": "TLS/1.2, websocket"}, "", true},
+		{"426 asking for TLS", 426, map[string]string{"Upgrade": "TLS/1.2"}, "Upgrade Required", false},
+		{"400 with version header", 400, map[string]string{"Sec-WebSocket-Version": "13"}, "", true},
+		{"400 handshake error body", 400, nil, "Bad Request\nwebsocket: the client is not using the websocket protocol", true},
+		{"400 body mentions upgrade late", 400, nil, strings.Repeat("x", 2000) + "websocket", false},
+		{"plain 400", 400, nil, "Bad Request", false},
+		{"200 page about websockets", 200, nil, "Our WebSocket API docs", false},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		for k, v := range tt.headers {
+			header.Set(k, v)
+		}
+		if got := WebSocketUpgrade(tt.status, header, tt.body); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
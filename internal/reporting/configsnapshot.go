@@ -0,0 +1,210 @@
+package reporting
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/capsaicin/scanner/internal/config"
+)
+
+// redacted replaces sensitive header values in the config snapshot.
+const redacted = "[REDACTED]"
+
+// ConfigSnapshot records how a scan was configured, for reproducing it
+// later. Secret header values are masked.
+type ConfigSnapshot struct {
+	Wordlist         string            `json:"wordlist"`
+	Wordlists        []string          `json:"wordlists,omitempty"`
+	TargetsJSON      string            `json:"targets_json,omitempty"` // per-target headers may be credentials; only the file is recorded
+	Threads          int               `json:"threads"`
+	ThreadsPerHost   int               `json:"threads_per_host,omitempty"`
+	TimeoutSeconds   int               `json:"timeout_seconds"`
+	RateLimit        int               `json:"rate_limit"`
+	RateLimitGlobal  int               `json:"rate_limit_global,omitempty"`
+	DelayMinMs       int               `json:"delay_min_ms,omitempty"`
+	DelayMaxMs       int               `json:"delay_max_ms,omitempty"`
+	RetryAttempts    int               `json:"retry_attempts"`
+	MaxResponseMB    int               `json:"max_response_mb"`
+	MaxDepth         int               `json:"max_depth"`
+	RecurseOn        []string          `json:"recurse_on,omitempty"`
+	RecurseExclude   []string          `json:"recurse_exclude,omitempty"`
+	MaxRedirects     int               `json:"max_redirects,omitempty"`
+	Seed             int64             `json:"seed,omitempty"`
+	Extensions       []string          `json:"extensions,omitempty"`
+	ExtMode          string            `json:"ext_mode,omitempty"`
+	Methods          []string          `json:"methods,omitempty"`
+	BodyBytes        int               `json:"body_bytes,omitempty"`
+	ContentType      string            `json:"content_type,omitempty"`
+	UserAgent        string            `json:"user_agent,omitempty"`
+	UserAgentsFile   string            `json:"user_agents_file,omitempty"`
+	Mutations        []string          `json:"mutations,omitempty"`
+	Headers          map[string]string `json:"headers,omitempty"`
+	AllowPatterns    []string          `json:"allow_patterns,omitempty"`
+	DenyPatterns     []string          `json:"deny_patterns,omitempty"`
+	Evasion          string            `json:"evasion,omitempty"`
+	BypassStrategies []string          `json:"bypass_strategies,omitempty"`
+	SkipBypass       []string          `json:"skip_bypass_strategies,omitempty"`
+	SeverityMap      []string          `json:"severity_map,omitempty"`
+	MatchCodes       []string          `json:"match_codes,omitempty"`
+	FilterCodes      []string          `json:"filter_codes,omitempty"`
+	FilterSizes      []string          `json:"filter_sizes,omitempty"`
+	FilterWords      []string          `json:"filter_words,omitempty"`
+	InterestingTypes []string          `json:"interesting_types,omitempty"`
+	FailOn           string            `json:"fail_on,omitempty"`
+	MaxFindings      int               `json:"max_findings,omitempty"`
+	Soft404Sim       float64           `json:"soft404_similarity,omitempty"`
+	ExcludeLenAuto   int               `json:"exclude_length_auto,omitempty"`
+	BackupPatterns   []string          `json:"backup_patterns,omitempty"`
+	TLSProfile       string            `json:"tls_profile,omitempty"`
+	TLSMinVersion    string            `json:"tls_min_version,omitempty"`
+	SNI              string            `json:"sni,omitempty"`
+	ConnectTo        []string          `json:"connect_to,omitempty"`
+	UnixSocket       string            `json:"unix_socket,omitempty"`
+	Proxy            string            `json:"proxy,omitempty"`
+	Modes            []string          `json:"modes"`
+}
+
+// SnapshotConfig captures the effective scan configuration for the report.
+// Values of headers that commonly carry credentials are replaced with
+// [REDACTED]; header names are kept so the scan can be reproduced.
+func SnapshotConfig(cfg config.Config) *ConfigSnapshot {
+	snapshot := &ConfigSnapshot{
+		Wordlist:         cfg.Wordlist,
+		TargetsJSON:      cfg.TargetsJSON,
+		Threads:          cfg.Threads,
+		ThreadsPerHost:   cfg.ThreadsPerHost,
+		TimeoutSeconds:   cfg.Timeout,
+		RateLimit:        cfg.RateLimit,
+		RateLimitGlobal:  cfg.RateLimitGlobal,
+		DelayMinMs:       cfg.DelayMin,
+		DelayMaxMs:       cfg.DelayMax,
+		RetryAttempts:    cfg.RetryAttempts,
+		MaxResponseMB:    cfg.MaxResponseMB,
+		MaxDepth:         cfg.MaxDepth,
+		RecurseOn:        cfg.RecurseOn,
+		RecurseExclude:   cfg.RecurseExclude,
+		Seed:             cfg.Seed,
+		Extensions:       cfg.Extensions,
+		Mutations:        cfg.Mutations,
+		AllowPatterns:    cfg.AllowPatterns,
+		DenyPatterns:     cfg.DenyPatterns,
+		BypassStrategies: cfg.BypassStrategies,
+		SkipBypass:       cfg.NoBypassStrategies,
+		SeverityMap:      cfg.SeverityMap,
+		MatchCodes:       cfg.MatchCodes,
+		FilterCodes:      cfg.FilterCodes,
+		FilterSizes:      cfg.FilterSizes,
+		FilterWords:      cfg.FilterWords,
+		InterestingTypes: cfg.InterestingTypes,
+		FailOn:           cfg.FailOn,
+		MaxFindings:      cfg.MaxFindings,
+		Soft404Sim:       cfg.Soft404Similarity,
+		ExcludeLenAuto:   cfg.ExcludeLengthAuto,
+		BackupPatterns:   cfg.BackupPatterns,
+		UserAgent:        cfg.UserAgent,
+		UserAgentsFile:   cfg.UserAgentsFile,
+		TLSProfile:       cfg.TLSProfile,
+		TLSMinVersion:    cfg.TLSMinVersion,
+		SNI:              cfg.SNI,
+		ConnectTo:        cfg.ConnectTo,
+		UnixSocket:       cfg.UnixSocket,
+		Modes:            enabledModes(cfg),
+	}
+
+	// Proxy credentials are masked the same way as secret headers.
+	if proxyURL, err := config.ProxyURL(cfg.Proxy); err == nil && proxyURL != nil {
+		snapshot.Proxy = proxyURL.Redacted()
+	}
+
+	// Wordlist is the first -w; the full list is only worth recording when
+	// several were merged.
+	if paths := cfg.WordlistPaths(); len(paths) > 1 {
+		snapshot.Wordlists = paths
+	}
+
+	// The body itself may carry credentials; record only its size.
+	if cfg.Data != "" {
+		snapshot.BodyBytes = len(cfg.Data)
+		snapshot.ContentType = cfg.ContentType
+	}
+
+	// GET alone is the default and not worth recording.
+	if methods := cfg.RequestMethods(); len(methods) > 1 || methods[0] != "GET" {
+		snapshot.Methods = methods
+	}
+
+	if cfg.FollowRedirects {
+		snapshot.MaxRedirects = cfg.MaxRedirects
+	}
+
+	// suffix is the default and not worth recording.
+	if cfg.ExtMode != "" && cfg.ExtMode != "suffix" {
+		snapshot.ExtMode = cfg.ExtMode
+	}
+
+	if cfg.Evasion != "none" {
+		snapshot.Evasion = cfg.Evasion
+	}
+
+	if len(cfg.CustomHeaders) > 0 {
+		snapshot.Headers = make(map[string]string, len(cfg.CustomHeaders))
+		for name, value := range cfg.CustomHeaders {
+			if isSensitiveHeader(name) {
+				value = redacted
+			}
+			snapshot.Headers[name] = value
+		}
+	}
+
+	return snapshot
+}
+
+// enabledModes lists the boolean scan modes that were switched on.
+func enabledModes(cfg config.Config) []string {
+	modes := []string{}
+	for name, on := range map[string]bool{
+		"safe-mode":          cfg.SafeMode,
+		"read-only":          cfg.ReadOnly,
+		"resume":             cfg.Resume != "",
+		"conditional-cache":  cfg.ConditionalCache != "",
+		"insecure":           cfg.Insecure,
+		"only-secrets":       cfg.OnlySecrets,
+		"actionable-only":    cfg.ActionableOnly,
+		"cache-probe":        cfg.CacheProbe,
+		"resolve":            cfg.Resolve,
+		"confirm-findings":   cfg.ConfirmFindings,
+		"default-creds":      cfg.DefaultCreds,
+		"insecure-downgrade": cfg.InsecureDowngrade,
+		"adaptive-timeout":   cfg.AdaptiveTimeout,
+		"analyze-dupes":      cfg.AnalyzeDupes,
+		"normalize-slash":    cfg.NormalizeSlash,
+		"waf-adaptive":       cfg.WAFAdaptive,
+		"show-secrets":       cfg.ShowSecrets,
+		"params":             cfg.ParamsWordlist != "",
+		"robots":             cfg.Robots,
+		"git-check":          cfg.GitCheck,
+		"backup-probe":       cfg.BackupProbe,
+		"http2":              cfg.HTTP2,
+		"http1":              cfg.HTTP1,
+		"follow-redirects":   cfg.FollowRedirects,
+		"use-cookie-jar":     cfg.UseCookieJar,
+		"verbose":            cfg.Verbose,
+	} {
+		if on {
+			modes = append(modes, name)
+		}
+	}
+	sort.Strings(modes)
+	return modes
+}
+
+// isSensitiveHeader reports whether a header's value is likely a credential.
+func isSensitiveHeader(name string) bool {
+	lower := strings.ToLower(name)
+	for _, marker := range []string{"auth", "cookie", "token", "secret", "key", "session", "password", "signature"} {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
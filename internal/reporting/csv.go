@@ -0,0 +1,61 @@
+package reporting
+
+import (
+	"encoding/csv"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+// csvHeader is the first row written by SaveCSV.
+var csvHeader = []string{"url", "status_code", "size", "method", "severity", "secret_found", "secret_types", "waf_detected", "technologies"}
+
+// SaveCSV writes one row per result under a header row, for spreadsheets.
+// Results are sorted as in SaveJSON and list fields are joined with
+// semicolons. Quoting follows RFC 4180.
+func SaveCSV(results []scanner.Result, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(file)
+	w.Write(csvHeader)
+	for _, r := range sortResults(results) {
+		w.Write(csvRow(r))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// csvRow returns the csvHeader columns for r.
+func csvRow(r scanner.Result) []string {
+	return []string{
+		csvText(r.URL),
+		strconv.Itoa(r.StatusCode),
+		strconv.Itoa(r.Size),
+		csvText(r.Method),
+		r.Severity,
+		strconv.FormatBool(r.SecretFound),
+		csvText(strings.Join(r.SecretTypes, ";")),
+		csvText(r.WAFDetected),
+		csvText(strings.Join(r.Technologies, ";")),
+	}
+}
+
+// csvText guards a value that came from the target (URLs, headers) against
+// spreadsheet formula injection: a leading =, +, -, @, tab or carriage
+// return makes Excel and LibreOffice evaluate the cell, so it is prefixed
+// with a single quote.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,75 @@
+package reporting
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+func TestSaveCSV(t *testing.T) {
+	results := testResults()
+	results[1].SecretTypes = []string{"AWS Access Key", "Generic API Key"}
+	results[1].Severity = "critical"
+	results = append(results, scanner.Result{
+		URL:          `http://example.com/search?q=a,b&x="y"`,
+		StatusCode:   200,
+		Method:       "GET",
+		Technologies: []string{"nginx", "PHP"},
+		WAFDetected:  "=HYPERLINK(\"http://evil.example\")",
+	})
+
+	filename := filepath.Join(t.TempDir(), "findings.csv")
+	if err := SaveCSV(results, filename); err != nil {
+		t.Fatalf("SaveCSV failed: %v", err)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+
+	if strings.Join(rows[0], ",") != "url,status_code,size,method,severity,secret_found,secret_types,waf_detected,technologies" {
+		t.Errorf("unexpected header: %v", rows[0])
+	}
+	if len(rows) != 5 {
+		t.Fatalf("expected header and 4 rows, got %d", len(rows))
+	}
+
+	var urls []string
+	for _, row := range rows[1:] {
+		urls = append(urls, row[0])
+	}
+	want := []string{"http://example.com/admin", "http://example.com/api", `http://example.com/search?q=a,b&x="y"`, "http://example.com/secret"}
+	if strings.Join(urls, " ") != strings.Join(want, " ") {
+		t.Errorf("expected rows sorted by URL %v, got %v", want, urls)
+	}
+
+	secret := rows[4]
+	if secret[1] != "200" || secret[2] != "512" || secret[4] != "critical" || secret[5] != "true" || secret[6] != "AWS Access Key;Generic API Key" {
+		t.Errorf("unexpected secret row: %v", secret)
+	}
+	if rows[2][7] != "Cloudflare" {
+		t.Errorf("expected WAF column, got %v", rows[2])
+	}
+	if rows[3][8] != "nginx;PHP" {
+		t.Errorf("expected technologies joined with semicolons, got %q", rows[3][8])
+	}
+	if rows[3][7] != `'=HYPERLINK("http://evil.example")` {
+		t.Errorf("expected formula to be neutralized, got %q", rows[3][7])
+	}
+}
+
+func TestSaveCSV_InvalidPath(t *testing.T) {
+	if err := SaveCSV(testResults(), "/nonexistent/dir/findings.csv"); err == nil {
+		t.Error("expected error for invalid path")
+	}
+}
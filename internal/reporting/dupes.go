@@ -0,0 +1,50 @@
+package reporting
+
+import (
+	"sort"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+// BodyCluster is a group of results that returned the same response body.
+type BodyCluster struct {
+	Hash  string   `json:"hash"`
+	Count int      `json:"count"`
+	URLs  []string `json:"urls"`
+}
+
+// GroupByBodyHash clusters results sharing a BodyHash, largest cluster
+// first. Only clusters of two or more results are returned; results with an
+// empty body are skipped since every bodiless redirect would share a hash.
+func GroupByBodyHash(results []scanner.Result) []BodyCluster {
+	byHash := make(map[string]*BodyCluster)
+	var order []string
+	for _, r := range results {
+		if r.BodyHash == "" || r.Size == 0 {
+			continue
+		}
+		c, ok := byHash[r.BodyHash]
+		if !ok {
+			c = &BodyCluster{Hash: r.BodyHash}
+			byHash[r.BodyHash] = c
+			order = append(order, r.BodyHash)
+		}
+		c.Count++
+		c.URLs = append(c.URLs, r.URL)
+	}
+
+	var clusters []BodyCluster
+	for _, hash := range order {
+		if c := byHash[hash]; c.Count > 1 {
+			sort.Strings(c.URLs)
+			clusters = append(clusters, *c)
+		}
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		if clusters[i].Count != clusters[j].Count {
+			return clusters[i].Count > clusters[j].Count
+		}
+		return clusters[i].Hash < clusters[j].Hash
+	})
+	return clusters
+}
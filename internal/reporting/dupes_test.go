@@ -0,0 +1,45 @@
+package reporting
+
+import (
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+func TestGroupByBodyHash(t *testing.T) {
+	results := []scanner.Result{
+		{URL: "http://example.com/a", Size: 10, BodyHash: "aaa"},
+		{URL: "http://example.com/b", Size: 20, BodyHash: "bbb"},
+		{URL: "http://example.com/c", Size: 10, BodyHash: "aaa"},
+		{URL: "http://example.com/d", Size: 20, BodyHash: "bbb"},
+		{URL: "http://example.com/e", Size: 10, BodyHash: "aaa"},
+		{URL: "http://example.com/unique", Size: 5, BodyHash: "ccc"},
+		{URL: "http://example.com/r1", Size: 0, BodyHash: "empty"},
+		{URL: "http://example.com/r2", Size: 0, BodyHash: "empty"},
+	}
+
+	clusters := GroupByBodyHash(results)
+
+	if len(clusters) != 2 {
+		t.Fatalf("expected 2 clusters, got %d: %+v", len(clusters), clusters)
+	}
+	if clusters[0].Hash != "aaa" || clusters[0].Count != 3 {
+		t.Errorf("expected largest cluster aaa with 3 URLs, got %+v", clusters[0])
+	}
+	if clusters[1].Hash != "bbb" || clusters[1].Count != 2 {
+		t.Errorf("expected second cluster bbb with 2 URLs, got %+v", clusters[1])
+	}
+	if clusters[0].URLs[0] != "http://example.com/a" || clusters[0].URLs[2] != "http://example.com/e" {
+		t.Errorf("expected sorted URLs, got %v", clusters[0].URLs)
+	}
+}
+
+func TestGroupByBodyHash_NoDuplicates(t *testing.T) {
+	results := []scanner.Result{
+		{URL: "http://example.com/a", Size: 10, BodyHash: "aaa"},
+		{URL: "http://example.com/b", Size: 10, BodyHash: "bbb"},
+	}
+	if clusters := GroupByBodyHash(results); len(clusters) != 0 {
+		t.Errorf("expected no clusters, got %+v", clusters)
+	}
+}
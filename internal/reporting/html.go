@@ -2,19 +2,26 @@ package reporting
 
 import (
 	"fmt"
-	"os"
+	"html"
 	"strings"
 	"time"
 
+	"github.com/capsaicin/scanner/internal/detection"
 	"github.com/capsaicin/scanner/internal/scanner"
 )
 
 func GenerateHTML(results []scanner.Result, filename string) error {
+	return writeFileAtomic(filename, []byte(renderHTML(results, 0)))
+}
+
+// renderHTML builds the report page. A positive refreshSeconds adds a
+// meta-refresh so an open browser tab keeps up with a live scan.
+func renderHTML(results []scanner.Result, refreshSeconds int) string {
 	htmlTemplate := `<!DOCTYPE html>
 <html lang="en">
 <head>
 	<meta charset="UTF-8">
-	<meta name="viewport" content="width=device-width, initial-scale=1.0">
+	<meta name="viewport" content="width=device-width, initial-scale=1.0">%s
 	<title>Capsaicin Scan Report</title>
 	<style>
 		* { margin: 0; padding: 0; box-sizing: border-box; }
@@ -64,6 +71,17 @@ func GenerateHTML(results []scanner.Result, filename string) error {
 		.badge-secret { background: #ffc107; color: #333; }
 		.badge-waf { background: #6f42c1; color: white; }
 		.badge-tech { background: #17a2b8; color: white; }
+		.badge-login { background: #fd7e14; color: white; }
+		.badge-cookie { background: #6c757d; color: white; }
+		.reasons { color: #6c757d; font-size: 0.75em; margin-left: 4px; }
+		.inventory { margin-bottom: 30px; }
+		.inventory h2 { font-size: 16px; margin-bottom: 10px; color: #222; }
+		.findings { margin-bottom: 30px; }
+		.findings h2 { font-size: 16px; margin-bottom: 10px; color: #222; }
+		.findings-actionable h2 { color: #dc3545; }
+		.findings .empty { color: #6c757d; font-size: 13px; }
+		.inventory table { width: auto; min-width: 300px; }
+		.inventory-bar { display: inline-block; height: 10px; background: #17a2b8; border-radius: 2px; }
 		code { background: #f4f4f4; padding: 2px 6px; border-radius: 3px; font-family: monospace; font-size: 13px; }
 	</style>
 </head>
@@ -77,6 +95,10 @@ func GenerateHTML(results []scanner.Result, filename string) error {
 				<div class="stat-value">%d</div>
 				<div class="stat-label">Total Findings</div>
 			</div>
+			<div class="stat-card">
+				<div class="stat-value">%d</div>
+				<div class="stat-label">Actionable</div>
+			</div>
 			<div class="stat-card">
 				<div class="stat-value">%d</div>
 				<div class="stat-label">Success (2xx)</div>
@@ -99,29 +121,20 @@ func GenerateHTML(results []scanner.Result, filename string) error {
 			</div>
 		</div>
 
+		%s
+
 		<div class="search-box">
 			<input type="text" id="searchInput" placeholder="Search findings...">
 		</div>
 
-		<table id="resultsTable">
-			<thead>
-				<tr>
-					<th>Status</th>
-					<th>URL</th>
-					<th>Size</th>
-					<th>Details</th>
-				</tr>
-			</thead>
-			<tbody>
-				%s
-			</tbody>
-		</table>
+		%s
+		%s
 	</div>
 
 	<script>
 		document.getElementById('searchInput').addEventListener('input', function(e) {
 			const searchTerm = e.target.value.toLowerCase();
-			const rows = document.querySelectorAll('#resultsTable tbody tr');
+			const rows = document.querySelectorAll('.findings tbody tr');
 			
 			rows.forEach(row => {
 				const text = row.textContent.toLowerCase();
@@ -132,7 +145,8 @@ func GenerateHTML(results []scanner.Result, filename string) error {
 </body>
 </html>`
 
-	var tableRows strings.Builder
+	var actionableRows, informationalRows strings.Builder
+	countActionable := 0
 	count2xx := 0
 	count3xx := 0
 	countCritical := 0
@@ -175,8 +189,47 @@ func GenerateHTML(results []scanner.Result, filename string) error {
 		if len(result.Technologies) > 0 {
 			badges += fmt.Sprintf(`<span class="badge badge-tech">%s</span>`, strings.Join(result.Technologies, ", "))
 		}
+		if result.LoginPanel != "" {
+			badges += fmt.Sprintf(`<span class="badge badge-login">LOGIN: %s</span>`, result.LoginPanel)
+		}
+		if result.DefaultCreds != "" {
+			badges += fmt.Sprintf(`<span class="badge badge-login">DEFAULT CREDS: %s</span>`, result.DefaultCreds)
+		}
+
+		if result.CachePoisoningHint != "" {
+			badges += fmt.Sprintf(`<span class="badge badge-critical">CACHE POISONING: %s</span>`, html.EscapeString(result.CachePoisoningHint))
+		}
+
+		if len(result.CookieIssues) > 0 {
+			badges += fmt.Sprintf(`<span class="badge badge-cookie">COOKIES: %s</span>`, html.EscapeString(strings.Join(result.CookieIssues, ", ")))
+		}
+
+		if len(result.MixedContent) > 0 {
+			badges += fmt.Sprintf(`<span class="badge badge-cookie">MIXED CONTENT: %s</span>`, html.EscapeString(strings.Join(result.MixedContent, ", ")))
+		}
+
+		if len(result.Dependencies) > 0 {
+			badges += fmt.Sprintf(`<span class="badge badge-secret" title="%s">DEPENDENCIES: %d</span>`, html.EscapeString(strings.Join(result.Dependencies, ", ")), len(result.Dependencies))
+		}
+
+		if len(result.SecretValues) > 0 {
+			badges += fmt.Sprintf(` <code>%s</code>`, html.EscapeString(strings.Join(result.SecretValues, "; ")))
+		}
+
+		for _, secret := range result.SecretDetails {
+			badges += fmt.Sprintf(` <span class="reasons">%s, line %d: <code>%s</code></span>`, html.EscapeString(secret.Name), secret.LineNumber, html.EscapeString(secret.Context))
+		}
 
 		details := badges
+		if result.RequestedURL != "" && result.RequestedURL != result.URL {
+			details += fmt.Sprintf(` <span class="reasons">sent: <code>%s</code></span>`, html.EscapeString(result.RequestedURL))
+		}
+		if len(result.RedirectChain) > 1 {
+			details += fmt.Sprintf(` <span class="reasons">redirects: <code>%s</code></span>`, html.EscapeString(strings.Join(result.RedirectChain, " → ")))
+		}
+		if len(result.Reasons) > 0 {
+			details += fmt.Sprintf(`<span class="reasons">why: %s</span>`, strings.Join(result.Reasons, ", "))
+		}
 		if result.Server != "" || result.PoweredBy != "" {
 			tech := []string{}
 			if result.Server != "" {
@@ -191,25 +244,111 @@ func GenerateHTML(results []scanner.Result, filename string) error {
 			details += fmt.Sprintf(`<code>%s</code>`, strings.Join(tech, ", "))
 		}
 
+		tableRows := &informationalRows
+		if resultClass(result) == scanner.ClassActionable {
+			tableRows = &actionableRows
+			countActionable++
+		}
 		tableRows.WriteString(fmt.Sprintf(`
 				<tr>
 					<td class="%s">%d</td>
 					<td><code>%s</code></td>
 					<td>%d bytes</td>
+					<td>%d ms</td>
+					<td><code>%s</code></td>
 					<td>%s</td>
 				</tr>`,
-			statusClass, result.StatusCode, result.URL, result.Size, details))
+			statusClass, result.StatusCode, result.URL, result.Size, result.LatencyMs, html.EscapeString(resultMediaType(result)), details))
 	}
 
-	finalHTML := fmt.Sprintf(htmlTemplate,
-		time.Now().Format("2006-01-02 15:04:05"),
+	refreshTag := ""
+	generated := time.Now().Format("2006-01-02 15:04:05")
+	if refreshSeconds > 0 {
+		refreshTag = fmt.Sprintf("\n\t<meta http-equiv=\"refresh\" content=\"%d\">", refreshSeconds)
+		generated += fmt.Sprintf(" (live, refreshing every %ds)", refreshSeconds)
+	}
+
+	return fmt.Sprintf(htmlTemplate,
+		refreshTag,
+		generated,
 		len(results),
+		countActionable,
 		count2xx,
 		count3xx,
 		countCritical,
 		countSecrets,
 		countWAF,
-		tableRows.String())
+		buildInventoryHTML(SortTechInventory(TechInventory(results))),
+		buildFindingsHTML("actionable", "Actionable", countActionable, actionableRows.String()),
+		buildFindingsHTML("informational", "Informational", len(results)-countActionable, informationalRows.String()))
+}
+
+// buildFindingsHTML renders one findings section (actionable or
+// informational) around its table rows.
+func buildFindingsHTML(class, title string, count int, rows string) string {
+	if count == 0 {
+		return fmt.Sprintf(`
+		<div class="findings findings-%s">
+			<h2>%s (0)</h2>
+			<p class="empty">No %s findings.</p>
+		</div>`, class, title, class)
+	}
+	return fmt.Sprintf(`
+		<div class="findings findings-%s">
+			<h2>%s (%d)</h2>
+			<table id="%sTable">
+				<thead>
+					<tr>
+						<th>Status</th>
+						<th>URL</th>
+						<th>Size</th>
+						<th>Latency</th>
+						<th>Type</th>
+						<th>Details</th>
+					</tr>
+				</thead>
+				<tbody>
+					%s
+				</tbody>
+			</table>
+		</div>`, class, title, count, class, rows)
+}
+
+// buildInventoryHTML renders the technology inventory as a table with a
+// proportional bar per row. Returns "" when no technologies were detected.
+func buildInventoryHTML(inventory []TechCount) string {
+	if len(inventory) == 0 {
+		return ""
+	}
+
+	maxCount := inventory[0].Count
+	var rows strings.Builder
+	for _, tc := range inventory {
+		width := tc.Count * 200 / maxCount
+		rows.WriteString(fmt.Sprintf(`
+					<tr>
+						<td>%s</td>
+						<td>%d</td>
+						<td><span class="inventory-bar" style="width: %dpx"></span></td>
+					</tr>`, html.EscapeString(tc.Name), tc.Count, width))
+	}
+
+	return fmt.Sprintf(`<div class="inventory">
+			<h2>Technology Inventory</h2>
+			<table>
+				<thead>
+					<tr><th>Technology</th><th>URLs</th><th></th></tr>
+				</thead>
+				<tbody>%s
+				</tbody>
+			</table>
+		</div>`, rows.String())
+}
 
-	return os.WriteFile(filename, []byte(finalHTML), 0644)
-}
\ No newline at end of file
+// resultMediaType is the Content-Type shown for a result, without
+// parameters; a sniffed type replaces a missing or generic one.
+func resultMediaType(result scanner.Result) string {
+	contentType := detection.EffectiveContentType(result.ContentType, result.SniffedContentType)
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.TrimSpace(mediaType)
+}
@@ -0,0 +1,49 @@
+package reporting
+
+import (
+	"sort"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+// TechCount is one row of the technology inventory.
+type TechCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// TechInventory counts, for each detected technology, the number of distinct
+// URLs it was seen on. A URL that reports the same technology more than once
+// (e.g. via several results for different methods) is counted once.
+func TechInventory(results []scanner.Result) map[string]int {
+	seen := make(map[string]bool)
+	inventory := make(map[string]int)
+
+	for _, r := range results {
+		for _, tech := range r.Technologies {
+			key := r.URL + "|" + tech
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			inventory[tech]++
+		}
+	}
+	return inventory
+}
+
+// SortTechInventory orders an inventory by descending count, breaking ties
+// by name so output is deterministic.
+func SortTechInventory(inventory map[string]int) []TechCount {
+	counts := make([]TechCount, 0, len(inventory))
+	for name, n := range inventory {
+		counts = append(counts, TechCount{Name: name, Count: n})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
@@ -0,0 +1,77 @@
+package reporting
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+func TestTechInventory(t *testing.T) {
+	results := []scanner.Result{
+		{URL: "http://a.example.com/", Method: "GET", Technologies: []string{"Nginx", "PHP", "jQuery"}},
+		{URL: "http://a.example.com/", Method: "POST", Technologies: []string{"Nginx", "jQuery"}},
+		{URL: "http://b.example.com/", Method: "GET", Technologies: []string{"Nginx"}},
+		{URL: "http://c.example.com/", Method: "GET", Technologies: []string{"Nginx", "jQuery", "jQuery"}},
+		{URL: "http://d.example.com/", Method: "GET"},
+	}
+
+	inventory := TechInventory(results)
+	expected := map[string]int{"Nginx": 3, "jQuery": 2, "PHP": 1}
+	if len(inventory) != len(expected) {
+		t.Fatalf("expected %d technologies, got %v", len(expected), inventory)
+	}
+	for name, n := range expected {
+		if inventory[name] != n {
+			t.Errorf("expected %s=%d, got %d", name, n, inventory[name])
+		}
+	}
+}
+
+func TestSortTechInventory(t *testing.T) {
+	sorted := SortTechInventory(map[string]int{"PHP": 1, "Nginx": 3, "jQuery": 2, "Apache": 1})
+
+	want := []string{"Nginx", "jQuery", "Apache", "PHP"}
+	if len(sorted) != len(want) {
+		t.Fatalf("expected %d rows, got %d", len(want), len(sorted))
+	}
+	for i, name := range want {
+		if sorted[i].Name != name {
+			t.Errorf("row %d: expected %s, got %s", i, name, sorted[i].Name)
+		}
+	}
+}
+
+func TestGenerateHTML_TechInventory(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "report-*.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(tmpFile.Name())
+	tmpFile.Close()
+
+	results := []scanner.Result{
+		{URL: "http://example.com/", StatusCode: 200, Technologies: []string{"WordPress"}},
+	}
+	if err := GenerateHTML(results, tmpFile.Name()); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	data, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Technology Inventory") {
+		t.Error("expected technology inventory section in HTML")
+	}
+
+	// No technologies → no inventory section.
+	if err := GenerateHTML(testResults(), tmpFile.Name()); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+	data, _ = os.ReadFile(tmpFile.Name())
+	if strings.Contains(string(data), "Technology Inventory") {
+		t.Error("expected no inventory section without technologies")
+	}
+}
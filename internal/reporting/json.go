@@ -4,13 +4,19 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"time"
 
 	"github.com/capsaicin/scanner/internal/scanner"
+	"github.com/capsaicin/scanner/internal/transport"
+	"github.com/capsaicin/scanner/internal/version"
 )
 
+// ReportSchemaVersion is the schema_version written to JSON reports.
+const ReportSchemaVersion = "3.1"
+
 type ScanReport struct {
 	SchemaVersion string           `json:"schema_version"`
 	RunID         string           `json:"run_id"`
@@ -20,14 +26,25 @@ type ScanReport struct {
 }
 
 type ScanMetadata struct {
-	StartTime    string `json:"start_time"`
-	EndTime      string `json:"end_time"`
-	Duration     string `json:"duration"`
-	TargetCount  int    `json:"target_count"`
-	TargetsHash  string `json:"targets_hash"`
-	TotalResults int    `json:"total_results"`
-	Version      string `json:"version"`
-	Profile      string `json:"profile,omitempty"`
+	StartTime       string                        `json:"start_time"`
+	EndTime         string                        `json:"end_time"`
+	Duration        string                        `json:"duration"`
+	TargetCount     int                           `json:"target_count"`
+	TargetsHash     string                        `json:"targets_hash"`
+	TotalResults    int                           `json:"total_results"`
+	Version         string                        `json:"version"`
+	Profile         string                        `json:"profile,omitempty"`
+	BypassSuccesses map[string]int                `json:"bypass_successes,omitempty"`
+	TechInventory   []TechCount                   `json:"tech_inventory,omitempty"`
+	Partial         bool                          `json:"partial,omitempty"`
+	StopReason      string                        `json:"stop_reason,omitempty"`
+	Config          *ConfigSnapshot               `json:"config,omitempty"`
+	TargetErrors    map[string]string             `json:"target_errors,omitempty"`
+	SecretTypes     []scanner.SecretTypeCount     `json:"secret_types,omitempty"`
+	AbandonedHosts  map[string]int64              `json:"abandoned_hosts,omitempty"`
+	WAFThrottled    map[string]string             `json:"waf_throttled_hosts,omitempty"`
+	TargetHosts     map[string]transport.HostInfo `json:"target_hosts,omitempty"`
+	AutoFiltered    []scanner.AutoFilteredSize    `json:"auto_filtered_sizes,omitempty"`
 }
 
 type ScanSummary struct {
@@ -35,18 +52,13 @@ type ScanSummary struct {
 	BySeverity       map[string]int `json:"by_severity"`
 	SecretsFound     int            `json:"secrets_found"`
 	CriticalFindings int            `json:"critical_findings"`
+	Actionable       int            `json:"actionable_findings"`
+	Informational    int            `json:"informational_findings"`
 	MaxSeverity      string         `json:"max_severity"`
 }
 
 func SaveJSON(results []scanner.Result, filename string) error {
-	sorted := make([]scanner.Result, len(results))
-	copy(sorted, results)
-	sort.Slice(sorted, func(i, j int) bool {
-		if sorted[i].URL != sorted[j].URL {
-			return sorted[i].URL < sorted[j].URL
-		}
-		return sorted[i].StatusCode < sorted[j].StatusCode
-	})
+	sorted := sortResults(results)
 
 	file, err := os.Create(filename)
 	if err != nil {
@@ -59,46 +71,91 @@ func SaveJSON(results []scanner.Result, filename string) error {
 	return encoder.Encode(sorted)
 }
 
+// Stdout as a report filename writes the report to standard output.
+const Stdout = "-"
+
+// stdout is where Stdout reports go; tests replace it.
+var stdout io.Writer = os.Stdout
+
 func SaveJSONReport(results []scanner.Result, filename string, targets []string, runID string, startTime time.Time, duration time.Duration) error {
-	sorted := make([]scanner.Result, len(results))
-	copy(sorted, results)
-	sort.Slice(sorted, func(i, j int) bool {
-		if sorted[i].URL != sorted[j].URL {
-			return sorted[i].URL < sorted[j].URL
-		}
-		return sorted[i].StatusCode < sorted[j].StatusCode
-	})
+	return saveJSONReport(results, filename, targets, runID, startTime, duration, reportExtras{})
+}
+
+// reportExtras carries optional metadata for saveJSONReport.
+type reportExtras struct {
+	stopReason   string // non-empty marks the report as partial
+	config       *ConfigSnapshot
+	targetErrors map[string]string // targets skipped, with the reason
+	secretTypes  []scanner.SecretTypeCount
+	abandoned    map[string]int64              // hosts over --host-error-budget, with their error counts
+	wafHosts     map[string]string             // hosts throttled by --waf-adaptive, with the WAF seen
+	targetHosts  map[string]transport.HostInfo // --resolve answers per target
+	autoSizes    []scanner.AutoFilteredSize    // sizes --exclude-length-auto dropped
+}
+
+// saveJSONReport writes the versioned report with optional extra metadata.
+func saveJSONReport(results []scanner.Result, filename string, targets []string, runID string, startTime time.Time, duration time.Duration, extras reportExtras) error {
+	sorted := sortResults(results)
 
 	targetsHash := hashStrings(targets)
 	summary := buildSummary(sorted)
 
 	report := ScanReport{
-		SchemaVersion: "3.1",
+		SchemaVersion: ReportSchemaVersion,
 		RunID:         runID,
 		Metadata: ScanMetadata{
-			StartTime:    startTime.Format(time.RFC3339),
-			EndTime:      startTime.Add(duration).Format(time.RFC3339),
-			Duration:     duration.Round(time.Millisecond).String(),
-			TargetCount:  len(targets),
-			TargetsHash:  targetsHash,
-			TotalResults: len(sorted),
-			Version:      "3.1.0",
+			StartTime:       startTime.Format(time.RFC3339),
+			EndTime:         startTime.Add(duration).Format(time.RFC3339),
+			Duration:        duration.Round(time.Millisecond).String(),
+			TargetCount:     len(targets),
+			TargetsHash:     targetsHash,
+			TotalResults:    len(sorted),
+			Version:         version.Version,
+			BypassSuccesses: CountBypassStrategies(sorted),
+			TechInventory:   SortTechInventory(TechInventory(sorted)),
+			Partial:         extras.stopReason != "",
+			StopReason:      extras.stopReason,
+			Config:          extras.config,
+			TargetErrors:    extras.targetErrors,
+			SecretTypes:     extras.secretTypes,
+			AbandonedHosts:  extras.abandoned,
+			WAFThrottled:    extras.wafHosts,
+			TargetHosts:     extras.targetHosts,
+			AutoFiltered:    extras.autoSizes,
 		},
 		Summary: summary,
 		Results: sorted,
 	}
 
-	file, err := os.Create(filename)
-	if err != nil {
-		return err
+	var w io.Writer = stdout
+	if filename != Stdout {
+		file, err := os.Create(filename)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
 	}
-	defer file.Close()
 
-	encoder := json.NewEncoder(file)
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	return encoder.Encode(report)
 }
 
+// sortResults returns a copy of results ordered by URL, then status code,
+// so reports are stable across runs.
+func sortResults(results []scanner.Result) []scanner.Result {
+	sorted := make([]scanner.Result, len(results))
+	copy(sorted, results)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].URL != sorted[j].URL {
+			return sorted[i].URL < sorted[j].URL
+		}
+		return sorted[i].StatusCode < sorted[j].StatusCode
+	})
+	return sorted
+}
+
 // buildSummary computes aggregate statistics from results for the report envelope.
 func buildSummary(results []scanner.Result) ScanSummary {
 	severityRank := map[string]int{"critical": 5, "high": 4, "medium": 3, "low": 2, "info": 1}
@@ -123,10 +180,24 @@ func buildSummary(results []scanner.Result) ScanSummary {
 		if r.Critical {
 			summary.CriticalFindings++
 		}
+		if resultClass(r) == scanner.ClassActionable {
+			summary.Actionable++
+		} else {
+			summary.Informational++
+		}
 	}
 	return summary
 }
 
+// resultClass returns r.Class, classifying results from older reports that
+// predate the field.
+func resultClass(r scanner.Result) string {
+	if r.Class != "" {
+		return r.Class
+	}
+	return scanner.Classify(&r)
+}
+
 func hashStrings(ss []string) string {
 	h := sha256.New()
 	for _, s := range ss {
@@ -198,3 +269,15 @@ func CountByStatus(results []scanner.Result) map[string]int {
 
 	return counts
 }
+
+// CountBypassStrategies tallies successful bypass findings by the strategy
+// that produced them.
+func CountBypassStrategies(results []scanner.Result) map[string]int {
+	counts := make(map[string]int)
+	for _, r := range results {
+		if r.BypassStrategy != "" {
+			counts[r.BypassStrategy]++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,50 @@
+package reporting
+
+import (
+	"sort"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+// LatencySummary describes the spread of request latencies, in
+// milliseconds, across a set of results.
+type LatencySummary struct {
+	Count int
+	Avg   int64
+	P50   int64
+	P95   int64
+	P99   int64
+	Max   int64
+}
+
+// SummarizeLatency computes the average, nearest-rank percentiles and
+// maximum of the results' LatencyMs. Count is 0 when there are no results.
+func SummarizeLatency(results []scanner.Result) LatencySummary {
+	if len(results) == 0 {
+		return LatencySummary{}
+	}
+	latencies := make([]int64, len(results))
+	var total int64
+	for i, r := range results {
+		latencies[i] = r.LatencyMs
+		total += r.LatencyMs
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	n := len(latencies)
+	percentile := func(p int) int64 {
+		rank := (p*n + 99) / 100 // ceil(p/100 * n)
+		if rank < 1 {
+			rank = 1
+		}
+		return latencies[rank-1]
+	}
+	return LatencySummary{
+		Count: n,
+		Avg:   total / int64(n),
+		P50:   percentile(50),
+		P95:   percentile(95),
+		P99:   percentile(99),
+		Max:   latencies[n-1],
+	}
+}
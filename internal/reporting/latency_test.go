@@ -0,0 +1,29 @@
+package reporting
+
+import (
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+func TestSummarizeLatency(t *testing.T) {
+	if got := SummarizeLatency(nil); got.Count != 0 {
+		t.Errorf("expected an empty summary without results, got %+v", got)
+	}
+
+	// 1..100 ms in reverse order, so sorting is exercised.
+	results := make([]scanner.Result, 100)
+	for i := range results {
+		results[i].LatencyMs = int64(100 - i)
+	}
+	got := SummarizeLatency(results)
+	want := LatencySummary{Count: 100, Avg: 50, P50: 50, P95: 95, P99: 99, Max: 100}
+	if got != want {
+		t.Errorf("SummarizeLatency = %+v, want %+v", got, want)
+	}
+
+	one := SummarizeLatency([]scanner.Result{{LatencyMs: 7}})
+	if one.P50 != 7 || one.P99 != 7 || one.Max != 7 || one.Avg != 7 {
+		t.Errorf("expected every statistic of a single sample to be 7, got %+v", one)
+	}
+}
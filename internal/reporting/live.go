@@ -0,0 +1,71 @@
+package reporting
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+// LiveHTMLInterval is how often RunLiveHTML rewrites the report.
+const LiveHTMLInterval = 5 * time.Second
+
+// GenerateLiveHTML writes an HTML report that tells the browser to reload
+// itself every refreshSeconds.
+func GenerateLiveHTML(results []scanner.Result, filename string, refreshSeconds int) error {
+	return writeFileAtomic(filename, []byte(renderHTML(results, refreshSeconds)))
+}
+
+// RunLiveHTML rewrites the live HTML report from snapshot every interval
+// until ctx is cancelled. Write errors are retried on the next tick; the
+// caller writes the final report once the scan finishes.
+func RunLiveHTML(ctx context.Context, filename string, interval time.Duration, snapshot func() []scanner.Result) {
+	refreshSeconds := int(interval / time.Second)
+	if refreshSeconds < 1 {
+		refreshSeconds = 1
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	GenerateLiveHTML(snapshot(), filename, refreshSeconds)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			GenerateLiveHTML(snapshot(), filename, refreshSeconds)
+		}
+	}
+}
+
+// writeFileAtomic writes data to a temp file next to filename and renames
+// it into place, so readers never see a partially written report.
+func writeFileAtomic(filename string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpName)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Chmod(tmpName, 0644); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	if err := os.Rename(tmpName, filename); err != nil {
+		os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package reporting
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+func TestGenerateLiveHTML_Refresh(t *testing.T) {
+	dir := t.TempDir()
+	live := filepath.Join(dir, "live.html")
+	final := filepath.Join(dir, "final.html")
+
+	if err := GenerateLiveHTML(testResults(), live, 5); err != nil {
+		t.Fatalf("GenerateLiveHTML failed: %v", err)
+	}
+	if err := GenerateHTML(testResults(), final); err != nil {
+		t.Fatalf("GenerateHTML failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(live)
+	if !strings.Contains(string(data), `<meta http-equiv="refresh" content="5">`) {
+		t.Error("expected meta refresh in live report")
+	}
+	data, _ = os.ReadFile(final)
+	if strings.Contains(string(data), "http-equiv=\"refresh\"") {
+		t.Error("expected no meta refresh in final report")
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("expected only the two reports in %s, got %d entries", dir, len(entries))
+	}
+}
+
+func TestRunLiveHTML(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "report.html")
+
+	var calls int
+	snapshot := func() []scanner.Result {
+		calls++
+		return testResults()[:calls%len(testResults())+1]
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		RunLiveHTML(ctx, filename, 10*time.Millisecond, snapshot)
+		close(done)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunLiveHTML did not stop after cancel")
+	}
+
+	if calls < 2 {
+		t.Errorf("expected the report to be rewritten several times, got %d writes", calls)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read live report: %v", err)
+	}
+	if !strings.Contains(string(data), "</html>") {
+		t.Error("expected a complete HTML document")
+	}
+}
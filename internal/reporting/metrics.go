@@ -0,0 +1,75 @@
+package reporting
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// metricsContentType is the Prometheus text exposition format.
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metric is one sample in the /metrics exposition.
+type metric struct {
+	name  string
+	kind  string
+	help  string
+	value float64
+}
+
+// scanMetrics reads the live counters of source. Before the scan has
+// initialized its Stats every sample is zero, so scrapers see the full
+// set of series from the start.
+func scanMetrics(source ScanSource) []metric {
+	var requests, findings, secrets, errs, wafHits int64
+	var rps float64
+	if stats := source.Stats(); stats != nil {
+		requests = stats.GetProcessed()
+		findings = stats.GetFound()
+		secrets = stats.GetSecrets()
+		errs = stats.GetErrors()
+		wafHits = stats.GetWAFHits()
+		if elapsed := time.Since(stats.StartTime).Seconds(); elapsed > 0 {
+			rps = float64(requests) / elapsed
+		}
+	}
+	return []metric{
+		{"capsaicin_requests_total", "counter", "Scan requests completed.", float64(requests)},
+		{"capsaicin_findings_total", "counter", "Findings reported.", float64(findings)},
+		{"capsaicin_secrets_total", "counter", "Responses in which a secret was found.", float64(secrets)},
+		{"capsaicin_errors_total", "counter", "Requests that failed.", float64(errs)},
+		{"capsaicin_waf_hits_total", "counter", "Responses identified as WAF blocks.", float64(wafHits)},
+		{"capsaicin_requests_per_second", "gauge", "Requests per second averaged over the scan so far.", rps},
+	}
+}
+
+// NewMetricsHandler serves GET /metrics with the live scan counters in
+// the Prometheus text format.
+func NewMetricsHandler(source ScanSource) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", metricsContentType)
+		w.Header().Set("Cache-Control", "no-store")
+		bw := bufio.NewWriter(w)
+		for _, m := range scanMetrics(source) {
+			fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n%s %s\n", m.name, m.help, m.name, m.kind, m.name, strconv.FormatFloat(m.value, 'f', -1, 64))
+		}
+		bw.Flush()
+	})
+	return mux
+}
+
+// StartMetricsServer listens on addr and serves NewMetricsHandler(source)
+// until ctx is cancelled, like StartStatusServer.
+func StartMetricsServer(ctx context.Context, addr string, source ScanSource) (net.Addr, <-chan struct{}, error) {
+	return startServer(ctx, addr, NewMetricsHandler(source))
+}
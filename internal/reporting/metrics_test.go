@@ -0,0 +1,85 @@
+package reporting
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+func TestMetricsHandler(t *testing.T) {
+	stats := scanner.NewStats(10)
+	for i := 0; i < 3; i++ {
+		stats.IncrementProcessed()
+	}
+	stats.IncrementFound()
+	stats.IncrementSecrets()
+	stats.IncrementErrors()
+	stats.IncrementErrors()
+	stats.IncrementWAFHits()
+	handler := NewMetricsHandler(fakeSource{stats: stats})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("expected the Prometheus text content type, got %q", ct)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE capsaicin_requests_total counter\ncapsaicin_requests_total 3\n",
+		"capsaicin_findings_total 1\n",
+		"capsaicin_secrets_total 1\n",
+		"capsaicin_errors_total 2\n",
+		"capsaicin_waf_hits_total 1\n",
+		"# TYPE capsaicin_requests_per_second gauge\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in metrics:\n%s", want, body)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/metrics", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for POST, got %d", rec.Code)
+	}
+}
+
+func TestMetricsHandlerBeforeStart(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewMetricsHandler(fakeSource{}).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{"capsaicin_requests_total 0\n", "capsaicin_requests_per_second 0\n"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q before the scan starts:\n%s", want, body)
+		}
+	}
+}
+
+func TestStartMetricsServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	addr, done, err := StartMetricsServer(ctx, "127.0.0.1:0", fakeSource{stats: scanner.NewStats(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get("http://" + addr.String() + "/metrics")
+	if err != nil {
+		t.Fatalf("metrics request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the server to stop when the context is cancelled")
+	}
+}
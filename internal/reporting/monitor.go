@@ -0,0 +1,213 @@
+package reporting
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+// MonitorChangesFile is the name of the diff written to the monitor
+// directory after each run.
+const MonitorChangesFile = "changes.json"
+
+// ResultChange pairs the previous and current version of a finding whose
+// status, severity or evidence changed between runs.
+type ResultChange struct {
+	Before scanner.Result `json:"before"`
+	After  scanner.Result `json:"after"`
+}
+
+// ReportDiff lists the findings added, removed and changed between two runs.
+type ReportDiff struct {
+	Added   []scanner.Result `json:"added"`
+	Removed []scanner.Result `json:"removed"`
+	Changed []ResultChange   `json:"changed"`
+}
+
+// Empty reports whether the diff contains no changes.
+func (d ReportDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// MonitorResult is the outcome of a -monitor run.
+type MonitorResult struct {
+	Diff      ReportDiff
+	Baselined []string // targets seen for the first time; their findings are not diffed
+}
+
+// DiffResults compares two result sets keyed by method and URL. A finding
+// counts as changed when its status code, severity or evidence (secrets,
+// login panel, dependencies) differs; size and timing are ignored since
+// they drift on dynamic pages.
+func DiffResults(previous, current []scanner.Result) ReportDiff {
+	key := func(r scanner.Result) string { return r.Method + " " + r.URL }
+
+	before := make(map[string]scanner.Result, len(previous))
+	for _, r := range previous {
+		before[key(r)] = r
+	}
+
+	var diff ReportDiff
+	seen := make(map[string]bool, len(current))
+	for _, r := range current {
+		k := key(r)
+		seen[k] = true
+		old, ok := before[k]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, r)
+		case resultChanged(old, r):
+			diff.Changed = append(diff.Changed, ResultChange{Before: old, After: r})
+		}
+	}
+	for _, r := range previous {
+		if !seen[key(r)] {
+			diff.Removed = append(diff.Removed, r)
+		}
+	}
+
+	sortByKey := func(rs []scanner.Result) {
+		sort.Slice(rs, func(i, j int) bool { return key(rs[i]) < key(rs[j]) })
+	}
+	sortByKey(diff.Added)
+	sortByKey(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return key(diff.Changed[i].After) < key(diff.Changed[j].After) })
+	return diff
+}
+
+// carryNotModified replaces each --conditional-cache finding the server
+// answered with 304 by the previous run's finding for the same request, so
+// an unchanged resource keeps its evidence and is not reported as changed.
+func carryNotModified(previous, current []scanner.Result) []scanner.Result {
+	key := func(r scanner.Result) string { return r.Method + " " + r.URL }
+	before := make(map[string]scanner.Result, len(previous))
+	for _, r := range previous {
+		before[key(r)] = r
+	}
+	carried := make([]scanner.Result, len(current))
+	for i, r := range current {
+		carried[i] = r
+		if old, ok := before[key(r)]; ok && notModified(r) {
+			carried[i] = old
+		}
+	}
+	return carried
+}
+
+func notModified(r scanner.Result) bool {
+	for _, reason := range r.Reasons {
+		if reason == scanner.ReasonNotModified {
+			return true
+		}
+	}
+	return false
+}
+
+func resultChanged(a, b scanner.Result) bool {
+	return a.StatusCode != b.StatusCode ||
+		a.Severity != b.Severity ||
+		a.LoginPanel != b.LoginPanel ||
+		strings.Join(a.SecretTypes, ",") != strings.Join(b.SecretTypes, ",") ||
+		strings.Join(a.Dependencies, ",") != strings.Join(b.Dependencies, ",")
+}
+
+// LoadJSONReport reads a report written by SaveJSONReport.
+func LoadJSONReport(filename string) (*ScanReport, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var report ScanReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", filename, err)
+	}
+	return &report, nil
+}
+
+// RunMonitor diffs results against the state stored in dir by the previous
+// run, then replaces that state with the current results. State is kept as
+// one JSON report per target, so scanning a different set of targets does
+// not report the missing ones as removed. The diff is also written to
+// MonitorChangesFile in dir.
+//
+// An incremental run (--wordlist-diff) only scanned part of the wordlist,
+// so nothing is reported as removed and previous findings that were not
+// rescanned are carried over into the new state.
+func RunMonitor(dir string, targets []string, results []scanner.Result, runID string, startTime time.Time, duration time.Duration, incremental bool) (MonitorResult, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return MonitorResult{}, fmt.Errorf("cannot create monitor directory: %w", err)
+	}
+
+	byTarget := groupByTarget(targets, results)
+
+	var out MonitorResult
+	for _, target := range targets {
+		current := byTarget[target]
+		stateFile := monitorStatePath(dir, target)
+
+		previous, err := LoadJSONReport(stateFile)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			out.Baselined = append(out.Baselined, target)
+		case err != nil:
+			return MonitorResult{}, err
+		default:
+			current = carryNotModified(previous.Results, current)
+			d := DiffResults(previous.Results, current)
+			out.Diff.Added = append(out.Diff.Added, d.Added...)
+			out.Diff.Changed = append(out.Diff.Changed, d.Changed...)
+			if incremental {
+				current = append(current, d.Removed...)
+			} else {
+				out.Diff.Removed = append(out.Diff.Removed, d.Removed...)
+			}
+		}
+
+		if err := SaveJSONReport(current, stateFile, []string{target}, runID, startTime, duration); err != nil {
+			return MonitorResult{}, err
+		}
+	}
+
+	data, err := json.MarshalIndent(out.Diff, "", "  ")
+	if err != nil {
+		return MonitorResult{}, err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, MonitorChangesFile), append(data, '\n')); err != nil {
+		return MonitorResult{}, err
+	}
+	return out, nil
+}
+
+// monitorStatePath names the per-target state file by a hash of the target
+// so arbitrary URLs map to safe file names.
+func monitorStatePath(dir, target string) string {
+	sum := sha256.Sum256([]byte(target))
+	return filepath.Join(dir, fmt.Sprintf("target-%x.json", sum[:8]))
+}
+
+// groupByTarget assigns each result to the longest target its URL starts
+// with. Results matching no target are dropped.
+func groupByTarget(targets []string, results []scanner.Result) map[string][]scanner.Result {
+	grouped := make(map[string][]scanner.Result, len(targets))
+	for _, r := range results {
+		best := ""
+		for _, t := range targets {
+			prefix := strings.TrimRight(t, "/")
+			if strings.HasPrefix(r.URL, prefix) && len(prefix) > len(strings.TrimRight(best, "/")) {
+				best = t
+			}
+		}
+		if best != "" {
+			grouped[best] = append(grouped[best], r)
+		}
+	}
+	return grouped
+}
@@ -0,0 +1,152 @@
+package reporting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+func TestDiffResults(t *testing.T) {
+	previous := []scanner.Result{
+		{URL: "http://example.com/admin", Method: "GET", StatusCode: 403, Severity: "low"},
+		{URL: "http://example.com/old", Method: "GET", StatusCode: 200, Severity: "info"},
+		{URL: "http://example.com/same", Method: "GET", StatusCode: 200, Severity: "info", Size: 100},
+	}
+	current := []scanner.Result{
+		{URL: "http://example.com/admin", Method: "GET", StatusCode: 200, Severity: "info"},
+		{URL: "http://example.com/new", Method: "GET", StatusCode: 200, Severity: "info"},
+		{URL: "http://example.com/same", Method: "GET", StatusCode: 200, Severity: "info", Size: 120},
+	}
+
+	diff := DiffResults(previous, current)
+
+	if len(diff.Added) != 1 || diff.Added[0].URL != "http://example.com/new" {
+		t.Errorf("expected /new added, got %+v", diff.Added)
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].URL != "http://example.com/old" {
+		t.Errorf("expected /old removed, got %+v", diff.Removed)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].Before.StatusCode != 403 || diff.Changed[0].After.StatusCode != 200 {
+		t.Errorf("expected /admin changed 403→200, got %+v", diff.Changed)
+	}
+	if !DiffResults(current, current).Empty() {
+		t.Error("expected no changes between identical result sets")
+	}
+}
+
+func TestRunMonitor(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	targets := []string{"http://a.example.com", "http://b.example.com"}
+	start := time.Now()
+
+	first := []scanner.Result{
+		{URL: "http://a.example.com/admin", Method: "GET", StatusCode: 200},
+		{URL: "http://b.example.com/login", Method: "GET", StatusCode: 200},
+	}
+	m, err := RunMonitor(dir, targets, first, "run1", start, time.Second, false)
+	if err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	if len(m.Baselined) != 2 || !m.Diff.Empty() {
+		t.Errorf("expected both targets baselined with no changes, got %+v", m)
+	}
+
+	// Second run scans only target a; b's state must not be reported removed.
+	second := []scanner.Result{
+		{URL: "http://a.example.com/admin", Method: "GET", StatusCode: 200},
+		{URL: "http://a.example.com/backup.zip", Method: "GET", StatusCode: 200},
+	}
+	m, err = RunMonitor(dir, targets[:1], second, "run2", start, time.Second, false)
+	if err != nil {
+		t.Fatalf("second run failed: %v", err)
+	}
+	if len(m.Baselined) != 0 {
+		t.Errorf("expected no baselined targets, got %v", m.Baselined)
+	}
+	if len(m.Diff.Added) != 1 || m.Diff.Added[0].URL != "http://a.example.com/backup.zip" {
+		t.Errorf("expected backup.zip added, got %+v", m.Diff.Added)
+	}
+	if len(m.Diff.Removed) != 0 {
+		t.Errorf("expected nothing removed, got %+v", m.Diff.Removed)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, MonitorChangesFile)); err != nil {
+		t.Errorf("expected changes file: %v", err)
+	}
+	report, err := LoadJSONReport(monitorStatePath(dir, targets[0]))
+	if err != nil {
+		t.Fatalf("failed to load state: %v", err)
+	}
+	if report.RunID != "run2" || len(report.Results) != 2 {
+		t.Errorf("expected state from run2 with 2 results, got %s with %d", report.RunID, len(report.Results))
+	}
+}
+
+func TestRunMonitor_Incremental(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	targets := []string{"http://a.example.com"}
+	start := time.Now()
+
+	first := []scanner.Result{{URL: "http://a.example.com/admin", Method: "GET", StatusCode: 200}}
+	if _, err := RunMonitor(dir, targets, first, "run1", start, time.Second, false); err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+
+	// Only the new words were scanned; /admin must be neither removed nor lost.
+	second := []scanner.Result{{URL: "http://a.example.com/backup.zip", Method: "GET", StatusCode: 200}}
+	m, err := RunMonitor(dir, targets, second, "run2", start, time.Second, true)
+	if err != nil {
+		t.Fatalf("incremental run failed: %v", err)
+	}
+	if len(m.Diff.Added) != 1 || len(m.Diff.Removed) != 0 {
+		t.Errorf("expected 1 added and 0 removed, got %+v", m.Diff)
+	}
+
+	report, err := LoadJSONReport(monitorStatePath(dir, targets[0]))
+	if err != nil {
+		t.Fatalf("failed to load state: %v", err)
+	}
+	if len(report.Results) != 2 {
+		t.Errorf("expected previous finding carried over, got %d results", len(report.Results))
+	}
+}
+
+func TestCarryNotModified(t *testing.T) {
+	previous := []scanner.Result{
+		{URL: "http://example.com/report.pdf", Method: "GET", StatusCode: 200, Severity: "low", SecretTypes: []string{"AWS Access Key"}},
+	}
+	current := []scanner.Result{
+		{URL: "http://example.com/report.pdf", Method: "GET", StatusCode: 304, Severity: "info", Reasons: []string{scanner.ReasonNotModified}},
+		{URL: "http://example.com/unknown", Method: "GET", StatusCode: 304, Severity: "info", Reasons: []string{scanner.ReasonNotModified}},
+		{URL: "http://example.com/new", Method: "GET", StatusCode: 200, Severity: "info"},
+	}
+
+	carried := carryNotModified(previous, current)
+	if carried[0].StatusCode != 200 || len(carried[0].SecretTypes) != 1 {
+		t.Errorf("expected the previous finding carried over, got %+v", carried[0])
+	}
+	if carried[1].StatusCode != 304 || carried[2].StatusCode != 200 {
+		t.Errorf("expected findings without a previous one kept as they are, got %+v", carried[1:])
+	}
+	if d := DiffResults(previous, carried); len(d.Changed) != 0 || len(d.Added) != 2 {
+		t.Errorf("expected the unchanged resource not reported as changed, got %+v", d)
+	}
+}
+
+func TestGroupByTarget(t *testing.T) {
+	targets := []string{"http://example.com/", "http://example.com/app"}
+	results := []scanner.Result{
+		{URL: "http://example.com/admin"},
+		{URL: "http://example.com/app/config"},
+		{URL: "http://other.com/x"},
+	}
+
+	grouped := groupByTarget(targets, results)
+
+	if len(grouped[targets[0]]) != 1 || len(grouped[targets[1]]) != 1 {
+		t.Errorf("expected one result per target, got %+v", grouped)
+	}
+}
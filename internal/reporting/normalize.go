@@ -0,0 +1,85 @@
+package reporting
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+// ResultFilter rewrites the result set before a sink writes its report.
+type ResultFilter func([]scanner.Result) []scanner.Result
+
+// NormalizeTrailingSlash collapses findings for /path and /path/ into one.
+// When one variant redirects to the other, the redirect target is kept as
+// the canonical form; otherwise the pair is collapsed only if both share a
+// status code and body hash, keeping the slash form. Bypass results and URLs
+// with a query string or fragment are left alone. Order is preserved.
+func NormalizeTrailingSlash(results []scanner.Result) []scanner.Result {
+	type pair struct{ plain, slash int }
+	pairs := make(map[string]*pair)
+	var order []string
+	for i, r := range results {
+		if strings.Contains(r.URL, " [") || strings.ContainsAny(r.URL, "?#") {
+			continue
+		}
+		trimmed := strings.TrimSuffix(r.URL, "/")
+		key := r.Method + " " + trimmed
+		p, ok := pairs[key]
+		if !ok {
+			p = &pair{plain: -1, slash: -1}
+			pairs[key] = p
+			order = append(order, key)
+		}
+		if trimmed == r.URL {
+			p.plain = i
+		} else {
+			p.slash = i
+		}
+	}
+
+	drop := make(map[int]bool)
+	for _, key := range order {
+		p := pairs[key]
+		if p.plain < 0 || p.slash < 0 {
+			continue
+		}
+		plain, slash := results[p.plain], results[p.slash]
+		switch {
+		case redirectsTo(plain, slash.URL):
+			drop[p.plain] = true
+		case redirectsTo(slash, plain.URL):
+			drop[p.slash] = true
+		case plain.StatusCode == slash.StatusCode && plain.BodyHash == slash.BodyHash:
+			drop[p.plain] = true
+		}
+	}
+	if len(drop) == 0 {
+		return results
+	}
+
+	kept := make([]scanner.Result, 0, len(results)-len(drop))
+	for i, r := range results {
+		if !drop[i] {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// redirectsTo reports whether r is a redirect whose Location resolves to
+// target.
+func redirectsTo(r scanner.Result, target string) bool {
+	if r.StatusCode < 300 || r.StatusCode > 399 || r.Location == "" {
+		return false
+	}
+	base, err := url.Parse(r.URL)
+	if err != nil {
+		return false
+	}
+	loc, err := url.Parse(r.Location)
+	if err != nil {
+		return false
+	}
+	return base.ResolveReference(loc).String() == target
+}
@@ -0,0 +1,144 @@
+package reporting
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/config"
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+func TestNormalizeTrailingSlash(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []scanner.Result
+		want    []string
+	}{
+		{
+			name: "redirect to slash keeps slash",
+			results: []scanner.Result{
+				{URL: "http://h/admin", Method: "GET", StatusCode: 301, Location: "/admin/", BodyHash: "e"},
+				{URL: "http://h/admin/", Method: "GET", StatusCode: 200, BodyHash: "x"},
+			},
+			want: []string{"http://h/admin/"},
+		},
+		{
+			name: "redirect away from slash keeps plain",
+			results: []scanner.Result{
+				{URL: "http://h/login/", Method: "GET", StatusCode: 308, Location: "http://h/login", BodyHash: "e"},
+				{URL: "http://h/login", Method: "GET", StatusCode: 200, BodyHash: "x"},
+			},
+			want: []string{"http://h/login"},
+		},
+		{
+			name: "identical response keeps slash",
+			results: []scanner.Result{
+				{URL: "http://h/docs", Method: "GET", StatusCode: 200, BodyHash: "x"},
+				{URL: "http://h/docs/", Method: "GET", StatusCode: 200, BodyHash: "x"},
+			},
+			want: []string{"http://h/docs/"},
+		},
+		{
+			name: "different body kept",
+			results: []scanner.Result{
+				{URL: "http://h/docs", Method: "GET", StatusCode: 200, BodyHash: "x"},
+				{URL: "http://h/docs/", Method: "GET", StatusCode: 200, BodyHash: "y"},
+			},
+			want: []string{"http://h/docs", "http://h/docs/"},
+		},
+		{
+			name: "redirect elsewhere kept",
+			results: []scanner.Result{
+				{URL: "http://h/old", Method: "GET", StatusCode: 302, Location: "/login", BodyHash: "e"},
+				{URL: "http://h/old/", Method: "GET", StatusCode: 200, BodyHash: "x"},
+			},
+			want: []string{"http://h/old", "http://h/old/"},
+		},
+		{
+			name: "different methods kept",
+			results: []scanner.Result{
+				{URL: "http://h/api", Method: "GET", StatusCode: 200, BodyHash: "x"},
+				{URL: "http://h/api/", Method: "POST", StatusCode: 200, BodyHash: "x"},
+			},
+			want: []string{"http://h/api", "http://h/api/"},
+		},
+		{
+			name: "bypass and query results untouched",
+			results: []scanner.Result{
+				{URL: "http://h/a [BYPASS:header]", Method: "GET", StatusCode: 200, BodyHash: "x"},
+				{URL: "http://h/a/ [BYPASS:header]", Method: "GET", StatusCode: 200, BodyHash: "x"},
+				{URL: "http://h/b?x=1", Method: "GET", StatusCode: 200, BodyHash: "x"},
+				{URL: "http://h/b/?x=1", Method: "GET", StatusCode: 200, BodyHash: "x"},
+			},
+			want: []string{"http://h/a [BYPASS:header]", "http://h/a/ [BYPASS:header]", "http://h/b?x=1", "http://h/b/?x=1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeTrailingSlash(tt.results)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %+v", tt.want, got)
+			}
+			for i, r := range got {
+				if r.URL != tt.want[i] {
+					t.Errorf("expected %s at %d, got %s", tt.want[i], i, r.URL)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeTrailingSlash_RecursiveScan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api" {
+			w.Header().Set("Location", r.URL.Path+"/")
+			w.WriteHeader(301)
+		} else if r.URL.Path == "/api/" {
+			w.WriteHeader(200)
+		} else if r.URL.Path == "/api/users" {
+			w.WriteHeader(200)
+		} else {
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	wordlistPath := filepath.Join(t.TempDir(), "wordlist.txt")
+	if err := os.WriteFile(wordlistPath, []byte("api\napi/\nusers\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Config{
+		Wordlist:      wordlistPath,
+		Threads:       2,
+		Timeout:       10,
+		MaxDepth:      2,
+		MaxResponseMB: 10,
+	}
+	results, _, err := scanner.NewEngine(cfg).Run([]string{server.URL})
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 raw results, got %d", len(results))
+	}
+
+	normalized := NormalizeTrailingSlash(results)
+	urls := make(map[string]bool)
+	for _, r := range normalized {
+		urls[r.URL] = true
+	}
+	if len(normalized) != 2 {
+		t.Errorf("expected 2 results after normalizing, got %d: %v", len(normalized), urls)
+	}
+	if urls[server.URL+"/api"] || !urls[server.URL+"/api/"] {
+		t.Errorf("expected /api to collapse into its redirect target /api/, got %v", urls)
+	}
+	if !urls[server.URL+"/api/users"] {
+		t.Errorf("expected /api/users to be kept, got %v", urls)
+	}
+}
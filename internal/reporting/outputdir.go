@@ -0,0 +1,31 @@
+package reporting
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// OutputDirReports holds the report paths --output-dir writes for one run.
+type OutputDirReports struct {
+	Dir  string
+	JSON string
+	HTML string
+	CSV  string
+}
+
+// PrepareOutputDir creates dir/runID and returns the JSON, HTML and CSV
+// report paths inside it. Naming the directory after the run ID keeps
+// repeated scans into the same dir from overwriting each other.
+func PrepareOutputDir(dir, runID string) (OutputDirReports, error) {
+	runDir := filepath.Join(dir, runID)
+	if err := os.MkdirAll(runDir, 0o755); err != nil {
+		return OutputDirReports{}, fmt.Errorf("cannot create output directory: %w", err)
+	}
+	return OutputDirReports{
+		Dir:  runDir,
+		JSON: filepath.Join(runDir, "report.json"),
+		HTML: filepath.Join(runDir, "report.html"),
+		CSV:  filepath.Join(runDir, "report.csv"),
+	}, nil
+}
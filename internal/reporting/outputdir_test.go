@@ -0,0 +1,60 @@
+package reporting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPrepareOutputDir(t *testing.T) {
+	base := filepath.Join(t.TempDir(), "reports")
+	runID := "abc123def456"
+
+	reports, err := PrepareOutputDir(base, runID)
+	if err != nil {
+		t.Fatalf("PrepareOutputDir failed: %v", err)
+	}
+	if reports.Dir != filepath.Join(base, runID) {
+		t.Errorf("expected the run directory to be named after the run ID, got %s", reports.Dir)
+	}
+	if info, err := os.Stat(reports.Dir); err != nil || !info.IsDir() {
+		t.Fatalf("expected %s to be created: %v", reports.Dir, err)
+	}
+
+	results := testResults()
+	targets := []string{"http://example.com"}
+	if err := SaveJSONReport(results, reports.JSON, targets, runID, time.Now(), time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateHTML(results, reports.HTML); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveCSV(results, reports.CSV); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"report.json", "report.html", "report.csv"} {
+		if _, err := os.Stat(filepath.Join(reports.Dir, name)); err != nil {
+			t.Errorf("expected %s in the run directory: %v", name, err)
+		}
+	}
+
+	report, err := LoadJSONReport(reports.JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.RunID != runID {
+		t.Errorf("expected run_id %s in report.json, got %s", runID, report.RunID)
+	}
+	if report.Metadata.TargetsHash == "" {
+		t.Error("expected report.json to record the targets hash")
+	}
+
+	// A second run into the same base directory gets its own folder.
+	if _, err := PrepareOutputDir(base, "fedcba654321"); err != nil {
+		t.Fatalf("second run failed: %v", err)
+	}
+	if _, err := os.Stat(reports.JSON); err != nil {
+		t.Errorf("expected the first run's report to be kept: %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/capsaicin/scanner/internal/detection"
 	"github.com/capsaicin/scanner/internal/scanner"
 )
 
@@ -23,17 +24,19 @@ func testResults() []scanner.Result {
 			UserAgent:  "test-agent",
 		},
 		{
-			URL:         "http://example.com/secret",
-			StatusCode:  200,
-			Size:        512,
-			WordCount:   25,
-			LineCount:   5,
-			Method:      "GET",
-			Timestamp:   "2025-01-01T00:00:01Z",
-			UserAgent:   "test-agent",
-			SecretFound: true,
-			SecretTypes: []string{"AWS Access Key"},
-			Critical:    true,
+			URL:          "http://example.com/secret",
+			StatusCode:   200,
+			Size:         512,
+			WordCount:    25,
+			LineCount:    5,
+			Method:       "GET",
+			Timestamp:    "2025-01-01T00:00:01Z",
+			UserAgent:    "test-agent",
+			SecretFound:  true,
+			SecretTypes:  []string{"AWS Access Key"},
+			Critical:     true,
+			Reasons:      []string{"status-interesting", "secret-found"},
+			CookieIssues: []string{"session: missing-httponly"},
 		},
 		{
 			URL:         "http://example.com/api",
@@ -229,6 +232,26 @@ func TestCountByStatus(t *testing.T) {
 	}
 }
 
+func TestCountBypassStrategies(t *testing.T) {
+	results := []scanner.Result{
+		{URL: "http://example.com/a [BYPASS:headers]", BypassStrategy: "headers"},
+		{URL: "http://example.com/b [BYPASS:headers]", BypassStrategy: "headers"},
+		{URL: "http://example.com/c [BYPASS:case-upper]", BypassStrategy: "case-upper"},
+		{URL: "http://example.com/d"},
+	}
+
+	counts := CountBypassStrategies(results)
+	if len(counts) != 2 {
+		t.Errorf("expected 2 strategies, got %d", len(counts))
+	}
+	if counts["headers"] != 2 {
+		t.Errorf("expected 2 header bypasses, got %d", counts["headers"])
+	}
+	if counts["case-upper"] != 1 {
+		t.Errorf("expected 1 case-upper bypass, got %d", counts["case-upper"])
+	}
+}
+
 func TestGenerateHTML_Basic(t *testing.T) {
 	tmpFile, err := os.CreateTemp("", "report-*.html")
 	if err != nil {
@@ -268,6 +291,70 @@ func TestGenerateHTML_Basic(t *testing.T) {
 	if !strings.Contains(html, "Cloudflare") {
 		t.Error("expected Cloudflare WAF in HTML")
 	}
+	if !strings.Contains(html, "why: status-interesting, secret-found") {
+		t.Error("expected result reasons in HTML")
+	}
+	if !strings.Contains(html, "COOKIES: session: missing-httponly") {
+		t.Error("expected cookie issues badge in HTML")
+	}
+}
+
+func TestGenerateHTML_ClassSections(t *testing.T) {
+	html := renderHTML(testResults(), 0)
+
+	actionable := strings.Index(html, "Actionable (1)")
+	informational := strings.Index(html, "Informational (2)")
+	if actionable < 0 || informational < 0 {
+		t.Fatalf("expected actionable and informational sections, got:\n%s", html)
+	}
+	if actionable > informational {
+		t.Error("expected the actionable section before the informational one")
+	}
+	secret := strings.Index(html, "http://example.com/secret")
+	about := strings.Index(html, "<code>http://example.com/admin</code>")
+	if !(actionable < secret && secret < informational && informational < about) {
+		t.Error("expected each finding in the section matching its class")
+	}
+}
+
+func TestSecretDetails_Redacted(t *testing.T) {
+	raw := "AK" + "IA" + "IOSFODNN7" + "TESTONLY1"
+	results := testResults()
+	results[1].SecretDetails = []detection.SecretMatch{{
+		Name:       "AWS Access Key",
+		Severity:   detection.SeverityCritical,
+		Redacted:   detection.RedactSecret(raw),
+		Raw:        raw,
+		LineNumber: 12,
+		Context:    `...accessKeyId: "` + detection.RedactSecret(raw) + `"};...`,
+	}}
+
+	html := renderHTML(results, 0)
+	if !strings.Contains(html, "AWS Access Key, line 12: <code>...accessKeyId: &#34;AKIA") {
+		t.Error("expected the secret's line and escaped context in HTML")
+	}
+
+	data, err := json.Marshal(results[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), raw) || strings.Contains(string(html), raw) {
+		t.Error("expected the raw secret to stay out of the reports")
+	}
+	if !strings.Contains(string(data), `"secret_details":[{"name":"AWS Access Key","severity":"critical"`) || !strings.Contains(string(data), `"line":12`) {
+		t.Errorf("expected secret details in JSON, got %s", data)
+	}
+}
+
+func TestBuildSummary_Classes(t *testing.T) {
+	results := testResults()
+	results[0].Class = scanner.ClassInformational
+	// results[1] and results[2] have no class and are classified on the fly.
+
+	summary := buildSummary(results)
+	if summary.Actionable != 1 || summary.Informational != 2 {
+		t.Errorf("expected 1 actionable and 2 informational, got %d and %d", summary.Actionable, summary.Informational)
+	}
 }
 
 func TestGenerateHTML_EmptyResults(t *testing.T) {
@@ -0,0 +1,266 @@
+package reporting
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+	"github.com/capsaicin/scanner/internal/version"
+)
+
+// SARIF identifiers written by GenerateSARIF.
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Rules   []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+	Properties           sarifRuleProps     `json:"properties"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifRuleProps struct {
+	Tags             []string `json:"tags"`
+	SecuritySeverity string   `json:"security-severity"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+	Properties          sarifResultProps  `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifResultProps struct {
+	Method     string   `json:"method"`
+	StatusCode int      `json:"status_code"`
+	Severity   string   `json:"severity"`
+	Reasons    []string `json:"reasons,omitempty"`
+}
+
+// sarifLevel maps a finding severity to a SARIF result level.
+func sarifLevel(severity string) string {
+	switch severity {
+	case scanner.SeverityCritical, scanner.SeverityHigh:
+		return "error"
+	case scanner.SeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifSecuritySeverity is the CVSS-like score GitHub code scanning uses to
+// label a rule critical (9.0+), high (7.0+), medium (4.0+) or low.
+var sarifSecuritySeverity = map[string]string{
+	scanner.SeverityCritical: "9.5",
+	scanner.SeverityHigh:     "8.0",
+	scanner.SeverityMedium:   "5.5",
+	scanner.SeverityLow:      "3.0",
+	scanner.SeverityInfo:     "0.0",
+}
+
+// sarifReasonOrder ranks reasons when picking the rule for a finding
+// without secrets; the most specific reason wins.
+var sarifReasonOrder = []string{
+	scanner.ReasonBypassSuccess,
+	scanner.ReasonCachePoisoning,
+	scanner.ReasonMethodFuzz,
+	scanner.ReasonManifestExposed,
+	scanner.ReasonBodyMatch,
+	scanner.ReasonParamAccepted,
+	scanner.ReasonHeaderTrigger,
+	scanner.ReasonStatusInteresting,
+}
+
+// GenerateSARIF writes the findings as a SARIF 2.1.0 log for code scanning
+// dashboards such as GitHub's Security tab. Each secret type on a result
+// becomes its own result under a rule like "secret/aws-access-key"; other
+// findings use their most specific reason as the rule ID. Results are
+// sorted as in SaveJSON.
+func GenerateSARIF(results []scanner.Result, filename string) error {
+	data, err := json.MarshalIndent(buildSARIF(results), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+func buildSARIF(results []scanner.Result) sarifLog {
+	rules := make(map[string]*sarifRule)
+	worst := make(map[string]string)
+	sarifResults := []sarifResult{}
+
+	add := func(r scanner.Result, ruleID, name, text string, tags []string) {
+		if _, ok := rules[ruleID]; !ok {
+			rules[ruleID] = &sarifRule{
+				ID:               ruleID,
+				Name:             name,
+				ShortDescription: sarifMessage{Text: name},
+				Properties:       sarifRuleProps{Tags: tags},
+			}
+			worst[ruleID] = scanner.SeverityInfo
+		}
+		// A rule is as severe as its worst finding.
+		if scanner.CompareSeverity(r.Severity, worst[ruleID]) > 0 {
+			worst[ruleID] = r.Severity
+		}
+
+		fingerprint := sha256.Sum256([]byte(ruleID + "|" + r.Method + "|" + r.URL))
+		sarifResults = append(sarifResults, sarifResult{
+			RuleID:    ruleID,
+			Level:     sarifLevel(r.Severity),
+			Message:   sarifMessage{Text: text},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: r.URL}}}},
+			PartialFingerprints: map[string]string{
+				"capsaicinFinding/v1": fmt.Sprintf("%x", fingerprint[:8]),
+			},
+			Properties: sarifResultProps{
+				Method:     r.Method,
+				StatusCode: r.StatusCode,
+				Severity:   r.Severity,
+				Reasons:    r.Reasons,
+			},
+		})
+	}
+
+	for _, r := range sortResults(results) {
+		if r.SecretFound && len(r.SecretTypes) > 0 {
+			for _, secretType := range r.SecretTypes {
+				add(r, "secret/"+sarifSlug(secretType), secretType+" exposed",
+					fmt.Sprintf("%s exposed in the response to %s %s (HTTP %d)", secretType, r.Method, r.URL, r.StatusCode),
+					[]string{"security", "secret"})
+			}
+			continue
+		}
+		reason := sarifReason(r)
+		add(r, reason, sarifRuleName(reason),
+			fmt.Sprintf("%s %s returned HTTP %d (%s)", r.Method, r.URL, r.StatusCode, strings.Join(r.Reasons, ", ")),
+			[]string{"security", "web"})
+	}
+
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	driverRules := make([]sarifRule, 0, len(ids))
+	for _, id := range ids {
+		rule := rules[id]
+		rule.DefaultConfiguration.Level = sarifLevel(worst[id])
+		rule.Properties.SecuritySeverity = sarifSecuritySeverity[worst[id]]
+		driverRules = append(driverRules, *rule)
+	}
+
+	return sarifLog{
+		Schema:  SARIFSchema,
+		Version: SARIFVersion,
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "capsaicin", Version: version.Version, Rules: driverRules}},
+			Results: sarifResults,
+		}},
+	}
+}
+
+// sarifReason picks the rule ID for a finding without secrets.
+func sarifReason(r scanner.Result) string {
+	for _, reason := range sarifReasonOrder {
+		for _, have := range r.Reasons {
+			if have == reason {
+				return reason
+			}
+		}
+	}
+	if len(r.Reasons) > 0 {
+		return r.Reasons[0]
+	}
+	return scanner.ReasonStatusInteresting
+}
+
+// sarifRuleName is the human-readable name of a reason rule.
+func sarifRuleName(reason string) string {
+	switch reason {
+	case scanner.ReasonBypassSuccess:
+		return "Access control bypass"
+	case scanner.ReasonCachePoisoning:
+		return "Possible web cache poisoning"
+	case scanner.ReasonMethodFuzz:
+		return "Alternative HTTP method accepted"
+	case scanner.ReasonManifestExposed:
+		return "Dependency manifest exposed"
+	case scanner.ReasonBodyMatch:
+		return "Login panel exposed"
+	case scanner.ReasonParamAccepted:
+		return "Hidden parameter accepted"
+	case scanner.ReasonHeaderTrigger:
+		return "WAF signature in response"
+	default:
+		return "Interesting path discovered"
+	}
+}
+
+// sarifSlug turns "AWS Access Key" into "aws-access-key".
+func sarifSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(name) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
@@ -0,0 +1,138 @@
+package reporting
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+func TestGenerateSARIF(t *testing.T) {
+	results := testResults()
+	results[0].Severity = scanner.SeverityMedium
+	results[0].Reasons = []string{scanner.ReasonStatusInteresting, scanner.ReasonBypassSuccess}
+	results[1].Severity = scanner.SeverityCritical
+	results[1].SecretTypes = []string{"AWS Access Key", "GitHub Token"}
+	results[2].Severity = scanner.SeverityInfo
+	results[2].Reasons = []string{scanner.ReasonStatusInteresting}
+
+	filename := filepath.Join(t.TempDir(), "findings.sarif")
+	if err := GenerateSARIF(results, filename); err != nil {
+		t.Fatalf("GenerateSARIF failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	var doc struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID                   string `json:"id"`
+						DefaultConfiguration struct {
+							Level string `json:"level"`
+						} `json:"defaultConfiguration"`
+						Properties map[string]interface{} `json:"properties"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+				PartialFingerprints map[string]string `json:"partialFingerprints"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	if doc.Version != "2.1.0" || doc.Schema != SARIFSchema {
+		t.Errorf("expected SARIF 2.1.0 with schema, got %q %q", doc.Version, doc.Schema)
+	}
+	if len(doc.Runs) != 1 || doc.Runs[0].Tool.Driver.Name != "capsaicin" {
+		t.Fatalf("expected one run by capsaicin, got %+v", doc.Runs)
+	}
+	run := doc.Runs[0]
+
+	// Sorted by URL: /admin, /api, then one result per secret type on /secret.
+	want := []struct{ ruleID, level, uri string }{
+		{scanner.ReasonBypassSuccess, "warning", "http://example.com/admin"},
+		{scanner.ReasonStatusInteresting, "note", "http://example.com/api"},
+		{"secret/aws-access-key", "error", "http://example.com/secret"},
+		{"secret/github-token", "error", "http://example.com/secret"},
+	}
+	if len(run.Results) != len(want) {
+		t.Fatalf("expected %d results, got %d", len(want), len(run.Results))
+	}
+	fingerprints := make(map[string]bool)
+	for i, w := range want {
+		got := run.Results[i]
+		if got.RuleID != w.ruleID || got.Level != w.level {
+			t.Errorf("result %d: expected %s/%s, got %s/%s", i, w.ruleID, w.level, got.RuleID, got.Level)
+		}
+		if len(got.Locations) != 1 || got.Locations[0].PhysicalLocation.ArtifactLocation.URI != w.uri {
+			t.Errorf("result %d: expected location %s, got %+v", i, w.uri, got.Locations)
+		}
+		fingerprints[got.PartialFingerprints["capsaicinFinding/v1"]] = true
+	}
+	if len(fingerprints) != len(want) {
+		t.Errorf("expected a distinct fingerprint per result, got %v", fingerprints)
+	}
+
+	rules := make(map[string]string)
+	for _, rule := range run.Tool.Driver.Rules {
+		rules[rule.ID] = rule.DefaultConfiguration.Level
+		if rule.ID == "secret/aws-access-key" && rule.Properties["security-severity"] != "9.5" {
+			t.Errorf("expected critical security-severity on the secret rule, got %v", rule.Properties)
+		}
+	}
+	if len(rules) != 4 || rules["secret/github-token"] != "error" || rules[scanner.ReasonStatusInteresting] != "note" {
+		t.Errorf("unexpected rules: %v", rules)
+	}
+}
+
+func TestSARIFSlug(t *testing.T) {
+	tests := map[string]string{
+		"AWS Access Key":             "aws-access-key",
+		"Database Connection String": "database-connection-string",
+		"JWT Token":                  "jwt-token",
+		"  Odd -- Name! ":            "odd-name",
+	}
+	for in, want := range tests {
+		if got := sarifSlug(in); got != want {
+			t.Errorf("sarifSlug(%q): expected %q, got %q", in, want, got)
+		}
+	}
+}
+
+func TestGenerateSARIF_Empty(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "empty.sarif")
+	if err := GenerateSARIF(nil, filename); err != nil {
+		t.Fatalf("GenerateSARIF failed: %v", err)
+	}
+	data, _ := os.ReadFile(filename)
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	run := doc["runs"].([]interface{})[0].(map[string]interface{})
+	if results, ok := run["results"].([]interface{}); !ok || len(results) != 0 {
+		t.Errorf("expected an empty results array, got %v", run["results"])
+	}
+}
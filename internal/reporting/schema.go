@@ -0,0 +1,121 @@
+package reporting
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// schemaDraft is the JSON Schema dialect emitted by JSONSchema.
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema returns a JSON Schema document describing the ScanReport
+// written by SaveJSONReport. The schema is derived from the Go structs via
+// reflection, so it cannot drift from the actual report format: every
+// field with a json tag becomes a property, fields without omitempty are
+// required, and unknown properties are rejected.
+func JSONSchema() string {
+	schema := schemaFor(reflect.TypeOf(ScanReport{}))
+	schema["$schema"] = schemaDraft
+	schema["title"] = "Capsaicin scan report"
+	schema["description"] = "Report written by capsaicin -o (schema_version " + ReportSchemaVersion + ")"
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		// The schema is built from static maps of strings; this cannot fail.
+		panic(err)
+	}
+	return string(data)
+}
+
+// schemaFor builds the schema fragment for a Go type.
+func schemaFor(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaFor(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": schemaFor(t.Elem()),
+		}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": schemaFor(t.Elem()),
+		}
+	case reflect.Struct:
+		return structSchema(t)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema describes a struct as an object whose properties follow the
+// struct's json tags.
+func structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	required := []string{}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		name, omitEmpty := parseJSONTag(field)
+		if name == "-" {
+			continue
+		}
+
+		prop := schemaFor(field.Type)
+		kind := field.Type.Kind()
+		if !omitEmpty && (kind == reflect.Slice || kind == reflect.Map || kind == reflect.Ptr) {
+			// A nil slice, map, or pointer without omitempty encodes as null.
+			prop["type"] = []string{prop["type"].(string), "null"}
+		}
+		properties[name] = prop
+
+		if !omitEmpty {
+			required = append(required, name)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// parseJSONTag returns the encoded name of a struct field and whether it
+// carries the omitempty option.
+func parseJSONTag(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "" {
+		return field.Name, false
+	}
+
+	parts := strings.Split(tag, ",")
+	name := parts[0]
+	if name == "" {
+		name = field.Name
+	}
+
+	omitEmpty := false
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty
+}
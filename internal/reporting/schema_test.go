@@ -0,0 +1,148 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJSONSchema_ValidDocument(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(JSONSchema()), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if schema["$schema"] != schemaDraft {
+		t.Errorf("expected $schema %q, got %v", schemaDraft, schema["$schema"])
+	}
+
+	props := schema["properties"].(map[string]interface{})
+	for _, key := range []string{"schema_version", "run_id", "metadata", "summary", "results"} {
+		if _, ok := props[key]; !ok {
+			t.Errorf("schema missing top-level property %q", key)
+		}
+	}
+}
+
+func TestJSONSchema_ValidatesReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := SaveJSONReport(testResults(), path, []string{"http://example.com"}, "abc123", time.Now(), time.Second); err != nil {
+		t.Fatalf("SaveJSONReport failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema, report interface{}
+	if err := json.Unmarshal([]byte(JSONSchema()), &schema); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := validateSchema(schema.(map[string]interface{}), report, "$"); err != nil {
+		t.Errorf("report does not match schema: %v", err)
+	}
+}
+
+func TestJSONSchema_RejectsUnknownField(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(JSONSchema()), &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := map[string]interface{}{"unexpected": true}
+	if err := validateSchema(schema, doc, "$"); err == nil {
+		t.Error("expected validation error for unknown/missing fields")
+	}
+}
+
+// validateSchema is a minimal JSON Schema validator covering the subset of
+// keywords JSONSchema emits: type, properties, required, items, and
+// additionalProperties.
+func validateSchema(schema map[string]interface{}, value interface{}, path string) error {
+	if typ, ok := schema["type"]; ok {
+		if !matchesType(typ, value) {
+			return fmt.Errorf("%s: expected type %v, got %T", path, typ, value)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		props, _ := schema["properties"].(map[string]interface{})
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, present := v[name.(string)]; !present {
+					return fmt.Errorf("%s: missing required property %q", path, name)
+				}
+			}
+		}
+		for key, child := range v {
+			if propSchema, ok := props[key]; ok {
+				if err := validateSchema(propSchema.(map[string]interface{}), child, path+"."+key); err != nil {
+					return err
+				}
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					return fmt.Errorf("%s: unexpected property %q", path, key)
+				}
+			case map[string]interface{}:
+				if err := validateSchema(extra, child, path+"."+key); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, child := range v {
+				if err := validateSchema(items, child, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func matchesType(typ interface{}, value interface{}) bool {
+	if types, ok := typ.([]interface{}); ok {
+		for _, t := range types {
+			if matchesType(t, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == float64(int64(n))
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "null":
+		return value == nil
+	}
+	return false
+}
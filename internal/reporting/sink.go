@@ -0,0 +1,292 @@
+package reporting
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/capsaicin/scanner/internal/config"
+	"github.com/capsaicin/scanner/internal/scanner"
+	"github.com/capsaicin/scanner/internal/transport"
+)
+
+// Sink receives scan results as the engine collects them. Open is called
+// before the scan starts, Write once per deduplicated result, and Close
+// after the scan ends. Implementations must be safe for concurrent use.
+//
+// Every Sink satisfies scanner.ResultSink, so it can be passed straight to
+// Engine.AddSink.
+type Sink interface {
+	Open() error
+	Write(result scanner.Result) error
+	Close() error
+}
+
+// JSONSink writes the versioned JSON report (see SaveJSONReport). The report
+// carries a summary over all results, so it is written on Close.
+type JSONSink struct {
+	filename  string
+	targets   []string
+	runID     string
+	startTime time.Time
+
+	mu      sync.Mutex
+	results []scanner.Result
+	filters []ResultFilter
+	extras  reportExtras
+}
+
+// NewJSONSink returns a sink that writes the JSON report to filename.
+func NewJSONSink(filename string, targets []string, runID string, startTime time.Time) *JSONSink {
+	return &JSONSink{filename: filename, targets: targets, runID: runID, startTime: startTime}
+}
+
+// Open checks that the report file can be created, so a bad path fails
+// before the scan rather than after it.
+func (s *JSONSink) Open() error {
+	return checkWritable(s.filename)
+}
+
+func (s *JSONSink) Write(result scanner.Result) error {
+	s.mu.Lock()
+	s.results = append(s.results, result)
+	s.mu.Unlock()
+	return nil
+}
+
+// SetStopReason marks the report as partial, e.g. after --max-findings
+// stopped the scan early.
+func (s *JSONSink) SetStopReason(reason string) {
+	s.mu.Lock()
+	s.extras.stopReason = reason
+	s.mu.Unlock()
+}
+
+// SetTargetErrors records targets that were skipped and why, e.g. TLS
+// certificate problems found during calibration.
+func (s *JSONSink) SetTargetErrors(errs map[string]string) {
+	s.mu.Lock()
+	s.extras.targetErrors = errs
+	s.mu.Unlock()
+}
+
+// SetSecretTypes records the per-type secret breakdown in the report
+// metadata.
+func (s *JSONSink) SetSecretTypes(counts []scanner.SecretTypeCount) {
+	s.mu.Lock()
+	s.extras.secretTypes = counts
+	s.mu.Unlock()
+}
+
+// SetAbandonedHosts records hosts that exhausted --host-error-budget, with
+// their error counts. Findings for these hosts are incomplete.
+func (s *JSONSink) SetAbandonedHosts(hosts map[string]int64) {
+	s.mu.Lock()
+	s.extras.abandoned = hosts
+	s.mu.Unlock()
+}
+
+// SetWAFHosts records hosts that --waf-adaptive throttled, with the WAF
+// that triggered it. Bypass was not attempted on these hosts.
+func (s *JSONSink) SetWAFHosts(hosts map[string]string) {
+	s.mu.Lock()
+	s.extras.wafHosts = hosts
+	s.mu.Unlock()
+}
+
+// SetTargetHosts records each target's resolved IPs and PTR names
+// (--resolve).
+func (s *JSONSink) SetTargetHosts(hosts map[string]transport.HostInfo) {
+	s.mu.Lock()
+	s.extras.targetHosts = hosts
+	s.mu.Unlock()
+}
+
+// SetAutoFilteredSizes records the status codes and sizes that
+// --exclude-length-auto dropped as noise.
+func (s *JSONSink) SetAutoFilteredSizes(sizes []scanner.AutoFilteredSize) {
+	s.mu.Lock()
+	s.extras.autoSizes = sizes
+	s.mu.Unlock()
+}
+
+// AddFilter registers a pass applied to the results before the report is
+// written, e.g. NormalizeTrailingSlash.
+func (s *JSONSink) AddFilter(f ResultFilter) {
+	s.mu.Lock()
+	s.filters = append(s.filters, f)
+	s.mu.Unlock()
+}
+
+// SetConfig records a sanitized snapshot of the scan configuration in the
+// report metadata.
+func (s *JSONSink) SetConfig(cfg config.Config) {
+	s.mu.Lock()
+	s.extras.config = SnapshotConfig(cfg)
+	s.mu.Unlock()
+}
+
+func (s *JSONSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return saveJSONReport(applyFilters(s.results, s.filters), s.filename, s.targets, s.runID, s.startTime, time.Since(s.startTime), s.extras)
+}
+
+// FilteredSink is a Sink that writes its report from the whole result set
+// on Close, so ResultFilters can rewrite the set first.
+type FilteredSink interface {
+	Sink
+	AddFilter(f ResultFilter)
+}
+
+// bufferedSink collects results and hands them to save on Close. It backs
+// the report formats that need every result before writing.
+type bufferedSink struct {
+	filename string
+	save     func([]scanner.Result, string) error
+
+	mu      sync.Mutex
+	results []scanner.Result
+	filters []ResultFilter
+}
+
+// NewHTMLSink returns a sink that writes the HTML report to filename.
+func NewHTMLSink(filename string) FilteredSink {
+	return &bufferedSink{filename: filename, save: GenerateHTML}
+}
+
+// NewYAMLSink returns a sink that writes the YAML findings document (see
+// SaveYAML) to filename.
+func NewYAMLSink(filename string) FilteredSink {
+	return &bufferedSink{filename: filename, save: SaveYAML}
+}
+
+// NewCSVSink returns a sink that writes the CSV findings table (see
+// SaveCSV) to filename.
+func NewCSVSink(filename string) FilteredSink {
+	return &bufferedSink{filename: filename, save: SaveCSV}
+}
+
+// NewSARIFSink returns a sink that writes the SARIF log (see GenerateSARIF)
+// to filename.
+func NewSARIFSink(filename string) FilteredSink {
+	return &bufferedSink{filename: filename, save: GenerateSARIF}
+}
+
+func (s *bufferedSink) Open() error {
+	return checkWritable(s.filename)
+}
+
+func (s *bufferedSink) Write(result scanner.Result) error {
+	s.mu.Lock()
+	s.results = append(s.results, result)
+	s.mu.Unlock()
+	return nil
+}
+
+// AddFilter registers a pass applied to the results before the report is
+// written.
+func (s *bufferedSink) AddFilter(f ResultFilter) {
+	s.mu.Lock()
+	s.filters = append(s.filters, f)
+	s.mu.Unlock()
+}
+
+func (s *bufferedSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(applyFilters(s.results, s.filters), s.filename)
+}
+
+func applyFilters(results []scanner.Result, filters []ResultFilter) []scanner.Result {
+	for _, f := range filters {
+		results = f(results)
+	}
+	return results
+}
+
+// NDJSONSink streams each result as one JSON line the moment the engine
+// collects it, for piping findings into another tool during a long scan.
+// Lines are written unfiltered and in collection order; use the JSON report
+// for a sorted, summarized view.
+type NDJSONSink struct {
+	filename string
+
+	mu   sync.Mutex
+	file *os.File
+	w    *bufio.Writer
+	err  error
+}
+
+// NewNDJSONSink returns a sink that streams results to filename, or to
+// standard output for Stdout.
+func NewNDJSONSink(filename string) *NDJSONSink {
+	return &NDJSONSink{filename: filename}
+}
+
+// Open creates the output file, truncating an existing one.
+func (s *NDJSONSink) Open() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.filename == Stdout {
+		s.w = bufio.NewWriter(stdout)
+		return nil
+	}
+	file, err := os.Create(s.filename)
+	if err != nil {
+		return fmt.Errorf("cannot write report %s: %w", s.filename, err)
+	}
+	s.file = file
+	s.w = bufio.NewWriter(file)
+	return nil
+}
+
+// Write appends result as a JSON line and flushes it, so a reader sees it
+// immediately. The first failure is kept and returned by Close; later
+// results are dropped.
+func (s *NDJSONSink) Write(result scanner.Result) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.w.Write(data)
+	s.w.WriteByte('\n')
+	s.err = s.w.Flush()
+	return s.err
+}
+
+func (s *NDJSONSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.err
+	if err == nil {
+		err = s.w.Flush()
+	}
+	if s.file != nil {
+		if cerr := s.file.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// checkWritable reports whether filename can be opened for writing without
+// disturbing an existing file's contents.
+func checkWritable(filename string) error {
+	if filename == Stdout {
+		return nil
+	}
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot write report %s: %w", filename, err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,267 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+// The JSON report must pick up the report filters main applies to every
+// FilteredSink.
+var _ FilteredSink = (*JSONSink)(nil)
+
+func TestJSONSink(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "report.json")
+	var sink Sink = NewJSONSink(filename, []string{"http://example.com"}, "run-1", time.Now())
+
+	if err := sink.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, r := range testResults() {
+		wg.Add(1)
+		go func(r scanner.Result) {
+			defer wg.Done()
+			sink.Write(r)
+		}(r)
+	}
+	wg.Wait()
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	var report ScanReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if report.RunID != "run-1" {
+		t.Errorf("expected run ID run-1, got %q", report.RunID)
+	}
+	if len(report.Results) != len(testResults()) {
+		t.Errorf("expected %d results, got %d", len(testResults()), len(report.Results))
+	}
+}
+
+func TestJSONSinkStdout(t *testing.T) {
+	var buf bytes.Buffer
+	stdout = &buf
+	defer func() { stdout = os.Stdout }()
+
+	sink := NewJSONSink(Stdout, []string{"http://example.com"}, "run-1", time.Now())
+	if err := sink.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for _, r := range testResults() {
+		sink.Write(r)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	var report ScanReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON on stdout: %v", err)
+	}
+	if len(report.Results) != len(testResults()) {
+		t.Errorf("expected %d results, got %d", len(testResults()), len(report.Results))
+	}
+	if _, err := os.Stat(Stdout); err == nil {
+		os.Remove(Stdout)
+		t.Error("expected no file named - to be created")
+	}
+}
+
+func TestHTMLSink(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "report.html")
+	var sink Sink = NewHTMLSink(filename)
+
+	if err := sink.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for _, r := range testResults() {
+		sink.Write(r)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(filename)
+	if !strings.Contains(string(data), "http://example.com/admin") {
+		t.Error("expected results in HTML report")
+	}
+}
+
+func TestNDJSONSink(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "findings.ndjson")
+	var sink Sink = NewNDJSONSink(filename)
+
+	if err := sink.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	// Each line must be on disk before Close, while the scan still runs.
+	first := testResults()[0]
+	sink.Write(first)
+	data, _ := os.ReadFile(filename)
+	if !strings.Contains(string(data), first.URL) || !strings.HasSuffix(string(data), "\n") {
+		t.Errorf("expected the first result to be flushed immediately, got %q", data)
+	}
+
+	var wg sync.WaitGroup
+	for _, r := range testResults()[1:] {
+		wg.Add(1)
+		go func(r scanner.Result) {
+			defer wg.Done()
+			sink.Write(r)
+		}(r)
+	}
+	wg.Wait()
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, _ = os.ReadFile(filename)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != len(testResults()) {
+		t.Fatalf("expected %d lines, got %d:\n%s", len(testResults()), len(lines), data)
+	}
+	for _, line := range lines {
+		var r scanner.Result
+		if err := json.Unmarshal([]byte(line), &r); err != nil || r.URL == "" {
+			t.Errorf("expected a JSON result per line, got %q (%v)", line, err)
+		}
+	}
+}
+
+func TestCSVSink(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "findings.csv")
+	sink := NewCSVSink(filename)
+	sink.AddFilter(func(results []scanner.Result) []scanner.Result { return results[1:] })
+
+	if err := sink.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	results := testResults()
+	for i := len(results) - 1; i >= 0; i-- {
+		sink.Write(results[i])
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// The results were written in reverse and the filter dropped the first
+	// one written; the rest must come out sorted like the JSON report.
+	data, _ := os.ReadFile(filename)
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil || len(rows) != len(results) || rows[0][0] != "url" {
+		t.Fatalf("expected a header and %d filtered rows, got %q (%v)", len(results)-1, data, err)
+	}
+	want := sortResults(results[:len(results)-1])
+	for i, r := range want {
+		if rows[i+1][0] != r.URL {
+			t.Errorf("row %d: expected %s, got %s", i+1, r.URL, rows[i+1][0])
+		}
+	}
+}
+
+func TestBufferedSinkFilters(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "findings.yaml")
+	sink := NewYAMLSink(filename)
+	sink.AddFilter(func(results []scanner.Result) []scanner.Result { return results[:1] })
+
+	if err := sink.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	for _, r := range testResults() {
+		sink.Write(r)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	data, _ := os.ReadFile(filename)
+	if !strings.Contains(string(data), testResults()[0].URL) || strings.Contains(string(data), testResults()[1].URL) {
+		t.Errorf("expected only the filtered result in the report, got:\n%s", data)
+	}
+}
+
+func TestNDJSONSinkStdout(t *testing.T) {
+	var buf bytes.Buffer
+	stdout = &buf
+	defer func() { stdout = os.Stdout }()
+
+	sink := NewNDJSONSink(Stdout)
+	if err := sink.Open(); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	sink.Write(testResults()[0])
+	if !strings.Contains(buf.String(), testResults()[0].URL) {
+		t.Errorf("expected the result on stdout before Close, got %q", buf.String())
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := os.Stat(Stdout); err == nil {
+		os.Remove(Stdout)
+		t.Error("expected no file named - to be created")
+	}
+}
+
+func TestSinkOpenUnwritable(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "missing-dir", "report.json")
+	if err := NewJSONSink(filename, nil, "run-1", time.Now()).Open(); err == nil {
+		t.Error("expected error opening a report in a missing directory")
+	}
+}
+
+func TestJSONSinkPartial(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "report.json")
+	sink := NewJSONSink(filename, []string{"http://example.com"}, "run-1", time.Now())
+	sink.Write(testResults()[0])
+	sink.SetStopReason("max findings reached (1)")
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(filename)
+	var report ScanReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !report.Metadata.Partial || report.Metadata.StopReason != "max findings reached (1)" {
+		t.Errorf("expected partial report with stop reason, got %+v", report.Metadata)
+	}
+}
+
+func TestJSONSinkSecretTypes(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "report.json")
+	sink := NewJSONSink(filename, []string{"http://example.com"}, "run-1", time.Now())
+	sink.Write(testResults()[0])
+	sink.SetSecretTypes([]scanner.SecretTypeCount{{Type: "AWS Access Key", Severity: "critical", Count: 3}})
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(filename)
+	var report ScanReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	got := report.Metadata.SecretTypes
+	if len(got) != 1 || got[0].Type != "AWS Access Key" || got[0].Severity != "critical" || got[0].Count != 3 {
+		t.Errorf("expected secret type breakdown in metadata, got %+v", got)
+	}
+}
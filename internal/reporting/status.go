@@ -0,0 +1,148 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+// StatusShutdownTimeout bounds how long the status server waits for
+// in-flight requests when the scan ends.
+const StatusShutdownTimeout = 2 * time.Second
+
+// ScanSource is what the status server reads from; *scanner.Engine
+// satisfies it. Stats returns nil until the scan has initialized.
+type ScanSource interface {
+	Stats() *scanner.Stats
+	Results() []scanner.Result
+}
+
+// ScanStatus is the /status response body.
+type ScanStatus struct {
+	State             string  `json:"state"`
+	Processed         int64   `json:"processed"`
+	Total             int64   `json:"total"`
+	Found             int64   `json:"found"`
+	Errors            int64   `json:"errors"`
+	Secrets           int64   `json:"secrets"`
+	WAFHits           int64   `json:"waf_hits"`
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	ElapsedSeconds    float64 `json:"elapsed_seconds"`
+	CurrentURL        string  `json:"current_url,omitempty"`
+	StopReason        string  `json:"stop_reason,omitempty"`
+}
+
+// snapshotStatus reads the live counters of stats. A nil stats means the
+// scan is still loading wordlists and calibrating.
+func snapshotStatus(stats *scanner.Stats) ScanStatus {
+	if stats == nil {
+		return ScanStatus{State: "starting"}
+	}
+	status := ScanStatus{
+		State:      "running",
+		Processed:  stats.GetProcessed(),
+		Total:      stats.GetTotal(),
+		Found:      stats.GetFound(),
+		Errors:     stats.GetErrors(),
+		Secrets:    stats.GetSecrets(),
+		WAFHits:    stats.GetWAFHits(),
+		CurrentURL: stats.GetCurrentURL(),
+		StopReason: stats.GetStopReason(),
+	}
+	elapsed := time.Since(stats.StartTime).Seconds()
+	status.ElapsedSeconds = elapsed
+	if elapsed > 0 {
+		status.RequestsPerSecond = float64(status.Processed) / elapsed
+	}
+	if status.StopReason != "" {
+		status.State = "stopping"
+	}
+	return status
+}
+
+// NewStatusHandler serves GET /status with the live ScanStatus and
+// GET /results with the results collected so far, both as JSON.
+func NewStatusHandler(source ScanSource) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		writeStatusJSON(w, r, snapshotStatus(source.Stats()))
+	})
+	mux.HandleFunc("/results", func(w http.ResponseWriter, r *http.Request) {
+		results := source.Results()
+		if results == nil {
+			results = []scanner.Result{}
+		}
+		writeStatusJSON(w, r, results)
+	})
+	return mux
+}
+
+func writeStatusJSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(v)
+}
+
+// StartStatusServer listens on addr and serves NewStatusHandler(source)
+// until ctx is cancelled, then shuts down gracefully. Listening happens
+// before it returns, so a busy port fails the scan up front. The returned
+// channel is closed once the server has stopped.
+func StartStatusServer(ctx context.Context, addr string, source ScanSource) (net.Addr, <-chan struct{}, error) {
+	return startServer(ctx, addr, NewStatusHandler(source))
+}
+
+// ListensOnAllInterfaces reports whether addr leaves the host empty or
+// names an unspecified address (0.0.0.0, ::), so the status and metrics
+// servers would be reachable from other machines.
+func ListensOnAllInterfaces(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsUnspecified()
+}
+
+// startServer listens on addr and serves handler until ctx is cancelled.
+func startServer(ctx context.Context, addr string, handler http.Handler) (net.Addr, <-chan struct{}, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, err
+	}
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		serveErr := make(chan error, 1)
+		go func() { serveErr <- server.Serve(listener) }()
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), StatusShutdownTimeout)
+			defer cancel()
+			server.Shutdown(shutdownCtx)
+			<-serveErr
+		case err := <-serveErr:
+			if !errors.Is(err, http.ErrServerClosed) {
+				listener.Close()
+			}
+		}
+	}()
+	return listener.Addr(), done, nil
+}
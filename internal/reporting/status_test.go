@@ -0,0 +1,127 @@
+package reporting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+var _ ScanSource = (*scanner.Engine)(nil)
+
+type fakeSource struct {
+	stats   *scanner.Stats
+	results []scanner.Result
+}
+
+func (f fakeSource) Stats() *scanner.Stats     { return f.stats }
+func (f fakeSource) Results() []scanner.Result { return f.results }
+
+func TestStatusHandler(t *testing.T) {
+	stats := scanner.NewStats(10)
+	stats.IncrementProcessed()
+	stats.IncrementProcessed()
+	stats.IncrementFound()
+	stats.IncrementSecrets()
+	stats.IncrementWAFHits()
+	handler := NewStatusHandler(fakeSource{stats: stats, results: testResults()})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/status", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+	var status ScanStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("invalid status JSON: %v\n%s", err, rec.Body.String())
+	}
+	if status.State != "running" || status.Processed != 2 || status.Total != 10 || status.Found != 1 || status.Secrets != 1 || status.WAFHits != 1 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/results", nil))
+	var results []scanner.Result
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+		t.Fatalf("invalid results JSON: %v", err)
+	}
+	if len(results) != len(testResults()) {
+		t.Errorf("expected %d results, got %d", len(testResults()), len(results))
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/status", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for POST, got %d", rec.Code)
+	}
+}
+
+func TestStatusHandlerBeforeStart(t *testing.T) {
+	handler := NewStatusHandler(fakeSource{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/status", nil))
+	var status ScanStatus
+	json.Unmarshal(rec.Body.Bytes(), &status)
+	if status.State != "starting" {
+		t.Errorf("expected state starting before stats exist, got %+v", status)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/results", nil))
+	if body := rec.Body.String(); body != "[]\n" {
+		t.Errorf("expected an empty array, got %q", body)
+	}
+}
+
+func TestStartStatusServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	addr, done, err := StartStatusServer(ctx, "127.0.0.1:0", fakeSource{stats: scanner.NewStats(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Get("http://" + addr.String() + "/status")
+	if err != nil {
+		t.Fatalf("status request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	if _, _, err := StartStatusServer(context.Background(), addr.String(), fakeSource{}); err == nil {
+		t.Error("expected an error for an address already in use")
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the server to stop when the context is cancelled")
+	}
+	if _, err := http.Get("http://" + addr.String() + "/status"); err == nil {
+		t.Error("expected the server to be closed")
+	}
+}
+
+func TestListensOnAllInterfaces(t *testing.T) {
+	tests := map[string]bool{
+		":8080":          true,
+		"0.0.0.0:8080":   true,
+		"[::]:8080":      true,
+		"127.0.0.1:8080": false,
+		"localhost:8080": false,
+		"[::1]:9090":     false,
+		"10.0.0.5:9090":  false,
+	}
+	for addr, want := range tests {
+		if got := ListensOnAllInterfaces(addr); got != want {
+			t.Errorf("ListensOnAllInterfaces(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package reporting
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+// TreeNode is one path segment in the discovered directory tree. The root
+// has no name and one child per target (scheme://host). Status is 0 for
+// segments that were only implied by a deeper result.
+type TreeNode struct {
+	Name     string      `json:"name"`
+	Status   int         `json:"status,omitempty"`
+	Children []*TreeNode `json:"children,omitempty"`
+
+	index map[string]*TreeNode
+}
+
+// child returns the named child, creating it if needed.
+func (n *TreeNode) child(name string) *TreeNode {
+	if n.index == nil {
+		n.index = make(map[string]*TreeNode)
+	}
+	c, ok := n.index[name]
+	if !ok {
+		c = &TreeNode{Name: name}
+		n.index[name] = c
+		n.Children = append(n.Children, c)
+	}
+	return c
+}
+
+// BuildTree arranges result URLs into a per-target directory tree with
+// children sorted by name. Bypass annotations (" [BYPASS:...]") are
+// stripped so a bypassed path lands on the same node as its original; a
+// GET result's status takes precedence over other methods for the node.
+func BuildTree(results []scanner.Result) *TreeNode {
+	root := &TreeNode{}
+	for _, r := range results {
+		raw := r.URL
+		if i := strings.Index(raw, " ["); i >= 0 {
+			raw = raw[:i]
+		}
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			continue
+		}
+
+		node := root.child(u.Scheme + "://" + u.Host)
+		for _, seg := range strings.Split(strings.Trim(u.Path, "/"), "/") {
+			if seg != "" {
+				node = node.child(seg)
+			}
+		}
+		if node.Status == 0 || r.Method == "GET" {
+			node.Status = r.StatusCode
+		}
+	}
+	sortTree(root)
+	return root
+}
+
+func sortTree(n *TreeNode) {
+	sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Name < n.Children[j].Name })
+	for _, c := range n.Children {
+		sortTree(c)
+	}
+}
+
+// RenderTree draws the tree with box-drawing connectors, one target per
+// block:
+//
+//	http://example.com
+//	├── admin (403)
+//	└── api (200)
+//	    └── users (200)
+func RenderTree(root *TreeNode) string {
+	var b strings.Builder
+	for i, target := range root.Children {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(target.Name + statusSuffix(target) + "\n")
+		renderChildren(&b, target, "")
+	}
+	return b.String()
+}
+
+func renderChildren(b *strings.Builder, n *TreeNode, prefix string) {
+	for i, c := range n.Children {
+		connector, indent := "├── ", "│   "
+		if i == len(n.Children)-1 {
+			connector, indent = "└── ", "    "
+		}
+		b.WriteString(prefix + connector + c.Name + statusSuffix(c) + "\n")
+		renderChildren(b, c, prefix+indent)
+	}
+}
+
+func statusSuffix(n *TreeNode) string {
+	if n.Status == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d)", n.Status)
+}
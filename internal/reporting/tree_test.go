@@ -0,0 +1,54 @@
+package reporting
+
+import (
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+func TestBuildTree(t *testing.T) {
+	results := []scanner.Result{
+		{URL: "http://example.com/api/users", Method: "GET", StatusCode: 200},
+		{URL: "http://example.com/admin", Method: "GET", StatusCode: 403},
+		{URL: "http://example.com/admin [BYPASS:headers]", Method: "GET+BYPASS", StatusCode: 200},
+		{URL: "http://example.com/api/", Method: "GET", StatusCode: 200},
+		{URL: "http://example.com/upload", Method: "POST", StatusCode: 200},
+		{URL: "https://other.com/.git/config", Method: "GET", StatusCode: 200},
+	}
+
+	root := BuildTree(results)
+
+	if len(root.Children) != 2 {
+		t.Fatalf("expected 2 targets, got %d", len(root.Children))
+	}
+	target := root.Children[0]
+	if target.Name != "http://example.com" {
+		t.Errorf("expected first target http://example.com, got %q", target.Name)
+	}
+	if len(target.Children) != 3 {
+		t.Fatalf("expected admin, api and upload under target, got %d children", len(target.Children))
+	}
+	if admin := target.Children[0]; admin.Name != "admin" || admin.Status != 403 {
+		t.Errorf("expected admin (403) with the GET status kept, got %s (%d)", admin.Name, admin.Status)
+	}
+
+	want := `http://example.com
+├── admin (403)
+├── api (200)
+│   └── users (200)
+└── upload (200)
+
+https://other.com
+└── .git
+    └── config (200)
+`
+	if got := RenderTree(root); got != want {
+		t.Errorf("unexpected tree rendering:\n%s\nexpected:\n%s", got, want)
+	}
+}
+
+func TestBuildTree_Empty(t *testing.T) {
+	if got := RenderTree(BuildTree(nil)); got != "" {
+		t.Errorf("expected empty rendering, got %q", got)
+	}
+}
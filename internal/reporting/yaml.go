@@ -0,0 +1,105 @@
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+	"github.com/capsaicin/scanner/internal/version"
+)
+
+// YAMLReport is the document written by SaveYAML. It carries no run
+// timestamps or run ID so that rescanning an unchanged target produces an
+// identical file.
+type YAMLReport struct {
+	SchemaVersion   string           `json:"schema_version"`
+	Version         string           `json:"version"`
+	Summary         ScanSummary      `json:"summary"`
+	BypassSuccesses map[string]int   `json:"bypass_successes,omitempty"`
+	TechInventory   []TechCount      `json:"tech_inventory,omitempty"`
+	Results         []scanner.Result `json:"results"`
+}
+
+// SaveYAML writes the findings as a YAML document meant to be committed and
+// diffed. Results are sorted as in SaveJSON and per-result timestamps are
+// dropped. Keys follow the JSON report's field names and order, with map
+// keys sorted, so the output is deterministic.
+func SaveYAML(results []scanner.Result, filename string) error {
+	data, err := renderYAML(results)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0644)
+}
+
+func renderYAML(results []scanner.Result) ([]byte, error) {
+	sorted := sortResults(results)
+	report := YAMLReport{
+		SchemaVersion:   ReportSchemaVersion,
+		Version:         version.Version,
+		Summary:         buildSummary(sorted),
+		BypassSuccesses: CountBypassStrategies(sorted),
+		TechInventory:   SortTechInventory(TechInventory(sorted)),
+		Results:         sorted,
+	}
+
+	// Going through encoding/json reuses the report's json tags (names,
+	// omitempty, sorted map keys); a yaml.Node keeps that key order.
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	blockStyle(&doc)
+	if found := mappingValue(doc.Content[0], "results"); found != nil {
+		for _, r := range found.Content {
+			dropKey(r, "timestamp")
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil.
+func mappingValue(m *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// dropKey removes key and its value from a mapping node.
+func dropKey(m *yaml.Node, key string) {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			m.Content = append(m.Content[:i], m.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// blockStyle clears the flow and quoting styles a JSON source leaves on
+// every node, so the document is written as plain block YAML.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
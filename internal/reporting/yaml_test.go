@@ -0,0 +1,88 @@
+package reporting
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/capsaicin/scanner/internal/scanner"
+)
+
+func TestSaveYAML_RoundTrip(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "findings.yaml")
+	if err := SaveYAML(testResults(), filename); err != nil {
+		t.Fatalf("SaveYAML failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	var doc struct {
+		SchemaVersion string `yaml:"schema_version"`
+		Summary       struct {
+			TotalFindings int `yaml:"total_findings"`
+			SecretsFound  int `yaml:"secrets_found"`
+		} `yaml:"summary"`
+		Results []map[string]interface{} `yaml:"results"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("failed to parse YAML: %v\n%s", err, data)
+	}
+
+	if doc.SchemaVersion != ReportSchemaVersion {
+		t.Errorf("expected schema version %s, got %q", ReportSchemaVersion, doc.SchemaVersion)
+	}
+	if doc.Summary.TotalFindings != 3 || doc.Summary.SecretsFound != 1 {
+		t.Errorf("expected 3 findings with 1 secret, got %+v", doc.Summary)
+	}
+	if len(doc.Results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(doc.Results))
+	}
+	if doc.Results[0]["url"] != "http://example.com/admin" || doc.Results[2]["url"] != "http://example.com/secret" {
+		t.Errorf("expected results sorted by URL, got %v, %v", doc.Results[0]["url"], doc.Results[2]["url"])
+	}
+	if doc.Results[1]["waf_detected"] != "Cloudflare" {
+		t.Errorf("expected JSON field names to be kept, got %v", doc.Results[1])
+	}
+	if _, ok := doc.Results[0]["timestamp"]; ok {
+		t.Error("expected per-result timestamps to be dropped")
+	}
+	if strings.Contains(string(data), "{") {
+		t.Errorf("expected block style YAML, got:\n%s", data)
+	}
+}
+
+func TestSaveYAML_Deterministic(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")
+
+	results := testResults()
+	reordered := []scanner.Result{results[2], results[0], results[1]}
+	for i := range reordered {
+		reordered[i].Timestamp = "2026-06-01T12:00:00Z"
+	}
+
+	if err := SaveYAML(results, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveYAML(reordered, second); err != nil {
+		t.Fatal(err)
+	}
+
+	data1, _ := os.ReadFile(first)
+	data2, _ := os.ReadFile(second)
+	if string(data1) != string(data2) {
+		t.Errorf("expected identical YAML for the same findings in another order and run:\n%s\n---\n%s", data1, data2)
+	}
+}
+
+func TestSaveYAML_InvalidPath(t *testing.T) {
+	if err := SaveYAML(testResults(), "/nonexistent/dir/findings.yaml"); err == nil {
+		t.Error("expected error for invalid path")
+	}
+}
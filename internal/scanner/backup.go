@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/capsaicin/scanner/internal/config"
+)
+
+// defaultBackupPatterns are the --backup-probe permutations tried when
+// --backup-patterns is not set. "*" stands for the discovered file name.
+var defaultBackupPatterns = []string{"*.bak", "*.old", "*.orig", "*.save", "*~", "*.swp", ".*.swp", "*.tmp", "*.copy"}
+
+// backupPatterns returns the permutations --backup-probe tries.
+func backupPatterns(cfg config.Config) []string {
+	if len(cfg.BackupPatterns) > 0 {
+		return cfg.BackupPatterns
+	}
+	return defaultBackupPatterns
+}
+
+// backupPaths applies each pattern to the file name at the end of path,
+// keeping its directory: "/app/config.php" with ".*.swp" gives
+// "/app/.config.php.swp".
+func backupPaths(path string, patterns []string) []string {
+	dir, name := "", path
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		dir, name = path[:i+1], path[i+1:]
+	}
+	paths := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		paths = append(paths, dir+strings.Replace(pattern, "*", name, 1))
+	}
+	return paths
+}
+
+// backupCandidate reports whether a result is a file worth probing for
+// backup copies: a 200 or 403 whose last path segment has an extension.
+// Backup permutations themselves are never probed again.
+func backupCandidate(task Task, result *Result) bool {
+	if task.Backup || task.Param != "" || strings.ContainsAny(task.Path, "?#") {
+		return false
+	}
+	if result.StatusCode != 200 && result.StatusCode != 403 {
+		return false
+	}
+	name := task.Path[strings.LastIndex(task.Path, "/")+1:]
+	return strings.Contains(name, ".")
+}
+
+// enqueueBackupProbe hands a discovered file to the engine, which queues
+// its backup permutations (--backup-probe). Safe mode skips the probe.
+func enqueueBackupProbe(ctx context.Context, task Task, result *Result, cfg config.Config, newTasks chan<- Task, taskWg *sync.WaitGroup) {
+	if !cfg.BackupProbe || cfg.SafeMode || !backupCandidate(task, result) {
+		return
+	}
+	task.spawn(taskWg)
+	select {
+	case newTasks <- Task{
+		TargetURL: task.TargetURL,
+		Path:      task.Path,
+		Depth:     task.Depth,
+		Backup:    true,
+		active:    task.active,
+	}:
+	case <-ctx.Done():
+		task.done(taskWg)
+	}
+}
@@ -0,0 +1,38 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBackupPaths(t *testing.T) {
+	got := backupPaths("/app/config.php", []string{"*.bak", "*~", ".*.swp"})
+	want := []string{"/app/config.php.bak", "/app/config.php~", "/app/.config.php.swp"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("backupPaths = %q, want %q", got, want)
+	}
+	if got := backupPaths("index.php", []string{".*.swp"}); !reflect.DeepEqual(got, []string{".index.php.swp"}) {
+		t.Errorf("expected a bare word to be permuted in place, got %q", got)
+	}
+}
+
+func TestBackupCandidate(t *testing.T) {
+	tests := []struct {
+		task   Task
+		status int
+		want   bool
+	}{
+		{Task{Path: "config.php"}, 200, true},
+		{Task{Path: "/app/.env.local"}, 403, true},
+		{Task{Path: "config.php"}, 301, false},
+		{Task{Path: "admin"}, 200, false},
+		{Task{Path: "/v1.2/admin/"}, 403, false},
+		{Task{Path: "config.php.bak", Backup: true}, 200, false},
+		{Task{Path: "search.php?q=1"}, 200, false},
+	}
+	for _, tt := range tests {
+		if got := backupCandidate(tt.task, &Result{StatusCode: tt.status}); got != tt.want {
+			t.Errorf("backupCandidate(%q, %d) = %v, want %v", tt.task.Path, tt.status, got, tt.want)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/capsaicin/scanner/internal/config"
@@ -30,11 +31,17 @@ type BypassResult struct {
 // attemptBypassStrategies runs all configured bypass strategies against a 403/401
 // URL until one succeeds or all are exhausted. Returns the first successful result.
 // This replaces the old single-shot attemptBypass function with a multi-strategy approach.
+// When cfg.BypassConcurrency is above 1 the strategies run in parallel; see
+// attemptBypassConcurrent.
 func attemptBypassStrategies(ctx context.Context, originalURL, userAgent string, cfg config.Config, client *transport.Client) *BypassResult {
 	path := extractPath(originalURL)
 	baseURL := extractBaseURL(originalURL)
 
-	strategies := buildBypassStrategies(baseURL, path)
+	strategies := buildBypassStrategies(baseURL, path, cfg.BypassStrategies, cfg.NoBypassStrategies)
+
+	if cfg.BypassConcurrency > 1 {
+		return attemptBypassConcurrent(ctx, strategies, originalURL, userAgent, cfg, client)
+	}
 
 	for _, strategy := range strategies {
 		select {
@@ -45,21 +52,101 @@ func attemptBypassStrategies(ctx context.Context, originalURL, userAgent string,
 
 		result, body := strategy.Execute(ctx, originalURL, userAgent, cfg, client)
 		if result != nil && isBypassSuccess(result.StatusCode) {
-			result.URL = originalURL + " [BYPASS:" + strategy.Name + "]"
-			result.Method = "GET+BYPASS"
-			return &BypassResult{
-				Result:   result,
-				Body:     body,
-				Strategy: strategy.Name,
-			}
+			return newBypassResult(result, body, strategy.Name, originalURL)
 		}
 	}
 
 	return nil
 }
 
-// buildBypassStrategies assembles the full list of bypass techniques to try.
-func buildBypassStrategies(baseURL, path string) []BypassStrategy {
+// attemptBypassConcurrent runs strategies with at most cfg.BypassConcurrency
+// in flight. Once a strategy succeeds, every lower-priority strategy is
+// cancelled and no new ones are started, but higher-priority strategies
+// already in flight are allowed to finish so the reported strategy is
+// always the earliest successful one in list order — the same answer the
+// sequential path would give.
+func attemptBypassConcurrent(ctx context.Context, strategies []BypassStrategy, originalURL, userAgent string, cfg config.Config, client *transport.Client) *BypassResult {
+	type outcome struct {
+		result *Result
+		body   string
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		best     = len(strategies)
+		outcomes = make([]outcome, len(strategies))
+		cancels  = make([]context.CancelFunc, len(strategies))
+		sem      = make(chan struct{}, cfg.BypassConcurrency)
+	)
+
+launch:
+	for i, strategy := range strategies {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			break launch
+		}
+
+		mu.Lock()
+		if best < i {
+			mu.Unlock()
+			<-sem
+			break
+		}
+		strategyCtx, cancel := context.WithCancel(ctx)
+		cancels[i] = cancel
+		mu.Unlock()
+
+		wg.Add(1)
+		go func(i int, strategy BypassStrategy) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			defer cancel()
+
+			result, body := strategy.Execute(strategyCtx, originalURL, userAgent, cfg, client)
+			if result == nil || !isBypassSuccess(result.StatusCode) {
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			outcomes[i] = outcome{result: result, body: body}
+			if i < best {
+				best = i
+				for j := i + 1; j < len(cancels); j++ {
+					if cancels[j] != nil {
+						cancels[j]()
+					}
+				}
+			}
+		}(i, strategy)
+	}
+
+	wg.Wait()
+
+	if ctx.Err() != nil || best == len(strategies) {
+		return nil
+	}
+	return newBypassResult(outcomes[best].result, outcomes[best].body, strategies[best].Name, originalURL)
+}
+
+// newBypassResult labels a successful strategy response for reporting.
+func newBypassResult(result *Result, body, strategy, originalURL string) *BypassResult {
+	result.URL = originalURL + " [BYPASS:" + strategy + "]"
+	result.Method = "GET+BYPASS"
+	result.BypassStrategy = strategy
+	return &BypassResult{
+		Result:   result,
+		Body:     body,
+		Strategy: strategy,
+	}
+}
+
+// buildBypassStrategies assembles the list of bypass techniques to try. A
+// non-empty include list keeps only the named strategies; exclude then drops
+// any named there. Priority order is preserved either way.
+func buildBypassStrategies(baseURL, path string, include, exclude []string) []BypassStrategy {
 	strategies := []BypassStrategy{
 		// 1. Header-based bypass — expanded set of IP spoofing & URL override headers
 		headerBypass("headers", map[string]string{
@@ -97,7 +184,64 @@ func buildBypassStrategies(baseURL, path string) []BypassStrategy {
 		methodOverrideBypass("method-override", path),
 	}
 
-	return strategies
+	return filterBypassStrategies(strategies, include, exclude)
+}
+
+// filterBypassStrategies applies the include/exclude name lists.
+func filterBypassStrategies(strategies []BypassStrategy, include, exclude []string) []BypassStrategy {
+	if len(include) == 0 && len(exclude) == 0 {
+		return strategies
+	}
+
+	included := make(map[string]bool, len(include))
+	for _, name := range include {
+		included[name] = true
+	}
+	excluded := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		excluded[name] = true
+	}
+
+	filtered := make([]BypassStrategy, 0, len(strategies))
+	for _, strategy := range strategies {
+		if len(included) > 0 && !included[strategy.Name] {
+			continue
+		}
+		if excluded[strategy.Name] {
+			continue
+		}
+		filtered = append(filtered, strategy)
+	}
+	return filtered
+}
+
+// BypassStrategyNames returns the names of all built-in bypass strategies
+// in priority order.
+func BypassStrategyNames() []string {
+	strategies := buildBypassStrategies("", "/", nil, nil)
+	names := make([]string, 0, len(strategies))
+	for _, strategy := range strategies {
+		names = append(names, strategy.Name)
+	}
+	return names
+}
+
+// ValidateBypassStrategies checks that every name in the include and exclude
+// lists refers to a known bypass strategy.
+func ValidateBypassStrategies(include, exclude []string) error {
+	known := make(map[string]bool)
+	for _, name := range BypassStrategyNames() {
+		known[name] = true
+	}
+
+	for _, list := range [][]string{include, exclude} {
+		for _, name := range list {
+			if !known[name] {
+				return fmt.Errorf("unknown bypass strategy %q. Valid values: %s", name, strings.Join(BypassStrategyNames(), ", "))
+			}
+		}
+	}
+	return nil
 }
 
 // isBypassSuccess returns true if the status code indicates the bypass worked.
@@ -125,7 +269,7 @@ func headerBypass(name string, headers map[string]string) BypassStrategy {
 				req.Header.Set(key, value)
 			}
 
-			return executeBypassRequest(req, cfg, client)
+			return executeBypassRequest(req, targetURL, cfg, client)
 		},
 	}
 }
@@ -146,7 +290,7 @@ func pathBypass(name, baseURL, altPath string) BypassStrategy {
 				req.Header.Set(key, value)
 			}
 
-			return executeBypassRequest(req, cfg, client)
+			return executeBypassRequest(req, altURL, cfg, client)
 		},
 	}
 }
@@ -177,7 +321,7 @@ func urlEncodeBypass(name, baseURL, path string) BypassStrategy {
 				req.Header.Set(key, value)
 			}
 
-			return executeBypassRequest(req, cfg, client)
+			return executeBypassRequest(req, altURL, cfg, client)
 		},
 	}
 }
@@ -204,7 +348,7 @@ func caseBypass(name, baseURL, path string) BypassStrategy {
 				req.Header.Set(key, value)
 			}
 
-			return executeBypassRequest(req, cfg, client)
+			return executeBypassRequest(req, altURL, cfg, client)
 		},
 	}
 }
@@ -231,7 +375,7 @@ func methodOverrideBypass(name, path string) BypassStrategy {
 				req.Header.Set(key, value)
 			}
 
-			return executeBypassRequest(req, cfg, client)
+			return executeBypassRequest(req, targetURL, cfg, client)
 		},
 	}
 }
@@ -239,26 +383,36 @@ func methodOverrideBypass(name, path string) BypassStrategy {
 // ── Helper functions ─────────────────────────────────────────────────────
 
 // executeBypassRequest performs the HTTP request and assembles a Result.
-func executeBypassRequest(req *http.Request, cfg config.Config, client *transport.Client) (*Result, string) {
+// requestedURL is the URL exactly as the strategy built it, which can differ
+// from req.URL.String() once net/url has parsed and re-escaped it.
+func executeBypassRequest(req *http.Request, requestedURL string, cfg config.Config, client *transport.Client) (*Result, string) {
+	start := time.Now()
 	resp, body, err := client.DoContext(req.Context(), req, cfg.RateLimit)
 	if err != nil {
 		return nil, ""
 	}
+	latency := time.Since(start)
 
 	bodyContent := string(body)
 	result := &Result{
-		URL:        req.URL.String(),
-		StatusCode: resp.StatusCode,
-		Size:       len(body),
-		WordCount:  len(strings.Fields(bodyContent)),
-		LineCount:  strings.Count(bodyContent, "\n") + 1,
-		Method:     req.Method,
-		Timestamp:  time.Now().Format(time.RFC3339),
-		Server:     resp.Header.Get("Server"),
-		PoweredBy:  resp.Header.Get("X-Powered-By"),
+		URL:          requestedURL,
+		RequestedURL: requestedURL,
+		StatusCode:   resp.StatusCode,
+		Size:         len(body),
+		BodyHash:     bodyHash(body),
+		WordCount:    len(strings.Fields(bodyContent)),
+		LineCount:    strings.Count(bodyContent, "\n") + 1,
+		Method:       req.Method,
+		Timestamp:    time.Now().Format(time.RFC3339),
+		Server:       resp.Header.Get("Server"),
+		PoweredBy:    resp.Header.Get("X-Powered-By"),
+		ContentType:  resp.Header.Get("Content-Type"),
+		Proto:        resp.Proto,
+		LatencyMs:    latency.Milliseconds(),
 	}
+	result.SniffedContentType = detection.SniffContentType(result.ContentType, body)
 
-	if wafName := detection.DetectWAF(resp); wafName != "" {
+	if wafName := detection.DetectWAF(resp, resp.StatusCode, bodyContent); wafName != "" {
 		result.WAFDetected = wafName
 	}
 
@@ -280,53 +434,3 @@ func extractBaseURL(rawURL string) string {
 	}
 	return rawURL[:idx+3+slashIdx]
 }
-
-// encodePathSegment percent-encodes the last segment of the path.
-// /api/admin -> /api/%61%64%6d%69%6e
-func encodePathSegment(path string) string {
-	lastSlash := strings.LastIndex(path, "/")
-	if lastSlash < 0 {
-		return path
-	}
-
-	prefix := path[:lastSlash+1]
-	segment := path[lastSlash+1:]
-
-	if segment == "" {
-		return path
-	}
-
-	var encoded strings.Builder
-	encoded.WriteString(prefix)
-	for _, ch := range segment {
-		if (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') {
-			encoded.WriteString(fmt.Sprintf("%%%02x", ch))
-		} else {
-			encoded.WriteRune(ch)
-		}
-	}
-
-	return encoded.String()
-}
-
-// manipulateCase toggles the case of the first character of the last path segment.
-// /admin -> /Admin, /Admin -> /admin
-func manipulateCase(path string) string {
-	lastSlash := strings.LastIndex(path, "/")
-	if lastSlash < 0 || lastSlash >= len(path)-1 {
-		return path
-	}
-
-	prefix := path[:lastSlash+1]
-	segment := path[lastSlash+1:]
-	first := rune(segment[0])
-
-	if first >= 'a' && first <= 'z' {
-		return prefix + strings.ToUpper(string(first)) + segment[1:]
-	}
-	if first >= 'A' && first <= 'Z' {
-		return prefix + strings.ToLower(string(first)) + segment[1:]
-	}
-
-	return path
-}
@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/capsaicin/scanner/internal/config"
 	"github.com/capsaicin/scanner/internal/transport"
@@ -99,7 +101,7 @@ func TestIsBypassSuccess(t *testing.T) {
 }
 
 func TestBuildBypassStrategies(t *testing.T) {
-	strategies := buildBypassStrategies("https://example.com", "/admin")
+	strategies := buildBypassStrategies("https://example.com", "/admin", nil, nil)
 	if len(strategies) == 0 {
 		t.Fatal("expected at least one bypass strategy")
 	}
@@ -119,6 +121,40 @@ func TestBuildBypassStrategies(t *testing.T) {
 	}
 }
 
+func TestBuildBypassStrategies_Filter(t *testing.T) {
+	strategies := buildBypassStrategies("https://example.com", "/admin", []string{"case-upper", "headers"}, nil)
+	if len(strategies) != 2 {
+		t.Fatalf("expected 2 strategies, got %d", len(strategies))
+	}
+	// Priority order comes from the built-in list, not the include list.
+	if strategies[0].Name != "headers" || strategies[1].Name != "case-upper" {
+		t.Errorf("expected [headers case-upper], got [%s %s]", strategies[0].Name, strategies[1].Name)
+	}
+
+	all := buildBypassStrategies("https://example.com", "/admin", nil, nil)
+	strategies = buildBypassStrategies("https://example.com", "/admin", nil, []string{"path-null-byte"})
+	if len(strategies) != len(all)-1 {
+		t.Errorf("expected %d strategies after exclusion, got %d", len(all)-1, len(strategies))
+	}
+	for _, s := range strategies {
+		if s.Name == "path-null-byte" {
+			t.Error("excluded strategy path-null-byte still present")
+		}
+	}
+}
+
+func TestValidateBypassStrategies(t *testing.T) {
+	if err := ValidateBypassStrategies([]string{"headers"}, []string{"path-null-byte"}); err != nil {
+		t.Errorf("unexpected error for known strategies: %v", err)
+	}
+	if err := ValidateBypassStrategies([]string{"no-such-strategy"}, nil); err == nil {
+		t.Error("expected error for unknown include strategy")
+	}
+	if err := ValidateBypassStrategies(nil, []string{"nope"}); err == nil {
+		t.Error("expected error for unknown exclude strategy")
+	}
+}
+
 func TestAttemptBypassStrategies_HeaderBypass(t *testing.T) {
 	// Server returns 403 normally, 200 when X-Forwarded-For is 127.0.0.1
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -299,6 +335,71 @@ func TestAttemptBypassStrategies_MethodOverride(t *testing.T) {
 	}
 }
 
+func TestAttemptBypassStrategies_ConcurrentKeepsPriority(t *testing.T) {
+	// Every strategy succeeds, but the highest-priority one ("headers") is
+	// the slowest to answer. The concurrent runner must still report it.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Forwarded-For") == "127.0.0.1" {
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(200)
+			return
+		}
+		if r.URL.Path == "/admin" && r.Method == "GET" {
+			w.WriteHeader(403)
+			return
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	cfg := testBypassConfig()
+	cfg.BypassConcurrency = 4
+	client := testBypassClient()
+
+	result := attemptBypassStrategies(
+		context.Background(),
+		server.URL+"/admin",
+		"test-agent",
+		cfg,
+		client,
+	)
+
+	if result == nil {
+		t.Fatal("expected bypass to succeed")
+	}
+	if result.Strategy != "headers" {
+		t.Errorf("expected highest-priority strategy 'headers', got %q", result.Strategy)
+	}
+}
+
+func TestAttemptBypassStrategies_ConcurrentAllFail(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(403)
+	}))
+	defer server.Close()
+
+	cfg := testBypassConfig()
+	cfg.BypassConcurrency = 3
+	client := testBypassClient()
+
+	result := attemptBypassStrategies(
+		context.Background(),
+		server.URL+"/admin",
+		"test-agent",
+		cfg,
+		client,
+	)
+
+	if result != nil {
+		t.Errorf("expected nil result when all strategies fail, got strategy=%q", result.Strategy)
+	}
+	if got, want := int(atomic.LoadInt32(&requests)), len(buildBypassStrategies(server.URL, "/admin", nil, nil)); got != want {
+		t.Errorf("expected every strategy to run once (%d requests), got %d", want, got)
+	}
+}
+
 // ── test helpers ─────────────────────────────────────────────────────────
 
 func testBypassConfig() config.Config {
@@ -314,3 +415,58 @@ func testBypassConfig() config.Config {
 func testBypassClient() *transport.Client {
 	return transport.NewClient(10, 0, 0, 10)
 }
+
+func TestAttemptBypassStrategies_RequestedURLKeepsRawPath(t *testing.T) {
+	// Only the raw crafted path is allowed through; a normalized /admin is not.
+	var sentURI string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.RequestURI == "/admin..;/" {
+			sentURI = r.RequestURI
+			w.WriteHeader(200)
+			return
+		}
+		w.WriteHeader(403)
+	}))
+	defer server.Close()
+
+	cfg := testBypassConfig()
+	cfg.BypassStrategies = []string{"path-semicolon-slash"}
+
+	result := attemptBypassStrategies(context.Background(), server.URL+"/admin", "test-agent", cfg, testBypassClient())
+
+	if result == nil {
+		t.Fatal("expected bypass to succeed via path-semicolon-slash")
+	}
+	if sentURI != "/admin..;/" {
+		t.Errorf("expected server to receive /admin..;/, got %q", sentURI)
+	}
+	if result.Result.RequestedURL != server.URL+"/admin..;/" {
+		t.Errorf("expected requested URL %s/admin..;/, got %q", server.URL, result.Result.RequestedURL)
+	}
+	if result.Result.URL != server.URL+"/admin [BYPASS:path-semicolon-slash]" {
+		t.Errorf("expected labeled original URL, got %q", result.Result.URL)
+	}
+}
+
+func TestAttemptBypassStrategies_RequestedURLEncoded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() == "/%61%64%6d%69%6e" {
+			w.WriteHeader(200)
+			return
+		}
+		w.WriteHeader(403)
+	}))
+	defer server.Close()
+
+	cfg := testBypassConfig()
+	cfg.BypassStrategies = []string{"url-encode"}
+
+	result := attemptBypassStrategies(context.Background(), server.URL+"/admin", "test-agent", cfg, testBypassClient())
+
+	if result == nil {
+		t.Fatal("expected bypass to succeed via url-encode")
+	}
+	if result.Result.RequestedURL != server.URL+"/%61%64%6d%69%6e" {
+		t.Errorf("expected raw encoded URL, got %q", result.Result.RequestedURL)
+	}
+}
@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+
+	"github.com/capsaicin/scanner/internal/config"
+	"github.com/capsaicin/scanner/internal/detection"
+	"github.com/capsaicin/scanner/internal/transport"
+)
+
+// probeCachePoisoning re-requests url once per detection.CacheProbeHeaders
+// entry with a random canary host and returns a hint such as
+// "X-Forwarded-Host reflected" when the canary comes back in the body or a
+// Location header. Each probe carries a unique cache-buster parameter so a
+// poisoned response can only land under a key no real user requests.
+func probeCachePoisoning(ctx context.Context, url, userAgent string, cfg config.Config, client *transport.Client, rng *rand.Rand) string {
+	for _, header := range detection.CacheProbeHeaders {
+		canary := fmt.Sprintf("capsaicin-%08x.example", rng.Uint32())
+		probeURL := cacheBusted(url, fmt.Sprintf("%08x", rng.Uint32()))
+
+		req, err := http.NewRequestWithContext(ctx, "GET", probeURL, nil)
+		if err != nil {
+			return ""
+		}
+		req.Header.Set("User-Agent", userAgent)
+		for key, value := range cfg.CustomHeaders {
+			req.Header.Set(key, value)
+		}
+		req.Header.Set(header, canary)
+
+		resp, body, err := client.DoContext(ctx, req, cfg.RateLimit)
+		if err != nil {
+			continue
+		}
+		if detection.ReflectedHost(string(body), canary) || detection.ReflectedHost(resp.Header.Get("Location"), canary) {
+			return header + " reflected"
+		}
+	}
+	return ""
+}
+
+// cacheBusted appends a throwaway query parameter to url, before any
+// fragment.
+func cacheBusted(url, token string) string {
+	fragment := ""
+	if i := strings.Index(url, "#"); i >= 0 {
+		url, fragment = url[:i], url[i:]
+	}
+	sep := "?"
+	if strings.Contains(url, "?") {
+		sep = "&"
+	}
+	return url + sep + "cb=" + token + fragment
+}
@@ -0,0 +1,265 @@
+package scanner
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CheckpointVersion is written in the header line of every checkpoint.
+const CheckpointVersion = 1
+
+// CheckpointFlushInterval is how often recorded tasks reach the disk. A
+// crash loses at most this much progress.
+const CheckpointFlushInterval = time.Second
+
+// checkpointHeader is the first line of a checkpoint file. Wordlist
+// fingerprints the task list the entries refer to.
+type checkpointHeader struct {
+	Checkpoint int    `json:"checkpoint"`
+	Wordlist   string `json:"wordlist"`
+}
+
+// checkpointEntry is one completed initial-pass task.
+type checkpointEntry struct {
+	Target string `json:"target"`
+	Path   string `json:"path,omitempty"`
+	Param  string `json:"param,omitempty"`
+	Method string `json:"method,omitempty"` // empty for GET
+}
+
+func (c checkpointEntry) key() string {
+	return c.Target + "\x00" + c.Path + "\x00" + c.Param + "\x00" + c.Method
+}
+
+func taskEntry(task Task) checkpointEntry {
+	method := task.Method
+	if method == "GET" {
+		method = ""
+	}
+	return checkpointEntry{Target: task.TargetURL, Path: task.Path, Param: task.Param, Method: method}
+}
+
+// Checkpoint records which initial-pass tasks of a scan have completed, as
+// JSON lines, so --resume can skip them after an interruption. It is safe
+// for concurrent use.
+type Checkpoint struct {
+	path string
+
+	// WordlistChanged is set when the file was written for a different
+	// wordlist, extension set, method list or mode. Its entries are still honoured.
+	WordlistChanged bool
+
+	// resumed is the set loaded at open. It never changes, so the count
+	// of tasks a run skips is fixed up front.
+	resumed map[string]bool
+
+	mu     sync.Mutex
+	done   map[string]bool
+	file   *os.File
+	w      *bufio.Writer
+	closed bool
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// wordlistFingerprint identifies the initial task list a checkpoint was
+// written for.
+func wordlistFingerprint(words, extensions []string, extMode string, methods []string, paramMode bool) string {
+	h := sha256.New()
+	for _, word := range words {
+		h.Write([]byte(word))
+		h.Write([]byte{'\n'})
+	}
+	fmt.Fprintf(h, "\x00%s\x00%s\x00%t", strings.Join(extensions, ","), strings.Join(methods, ","), paramMode)
+	// Written only for other modes, so checkpoints from before --ext-mode
+	// still match.
+	if extMode != "" && extMode != ExtModeSuffix {
+		fmt.Fprintf(h, "\x00%s", extMode)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// readCheckpoint loads the completed tasks in path. A missing or empty file
+// is an empty checkpoint. Malformed lines, such as one cut short by a
+// crash, are skipped; a file whose first line is not a checkpoint header is
+// an error so an unrelated file is never overwritten.
+func readCheckpoint(path string) (checkpointHeader, map[string]checkpointEntry, error) {
+	var header checkpointHeader
+	entries := make(map[string]checkpointEntry)
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return header, entries, nil
+	}
+	if err != nil {
+		return header, nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	first := true
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if first {
+			first = false
+			if err := json.Unmarshal(line, &header); err != nil || header.Checkpoint == 0 {
+				return header, nil, fmt.Errorf("%s is not a capsaicin checkpoint", path)
+			}
+			if header.Checkpoint != CheckpointVersion {
+				return header, nil, fmt.Errorf("checkpoint %s has unsupported version %d", path, header.Checkpoint)
+			}
+			continue
+		}
+		var entry checkpointEntry
+		if err := json.Unmarshal(line, &entry); err != nil || entry.Target == "" {
+			continue
+		}
+		entries[entry.key()] = entry
+	}
+	if err := scanner.Err(); err != nil {
+		return header, nil, err
+	}
+	return header, entries, nil
+}
+
+// CheckCheckpoint reports whether path can be used with --resume: it must
+// be missing, empty or a checkpoint this version writes.
+func CheckCheckpoint(path string) error {
+	_, _, err := readCheckpoint(path)
+	return err
+}
+
+// OpenCheckpoint loads the checkpoint at path, creating it if missing, and
+// starts appending to it. The file is rewritten with the current
+// fingerprint and the entries kept, dropping any damaged lines.
+func OpenCheckpoint(path, fingerprint string) (*Checkpoint, error) {
+	header, entries, err := readCheckpoint(path)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Checkpoint{
+		path:            path,
+		WordlistChanged: header.Wordlist != "" && header.Wordlist != fingerprint,
+		resumed:         make(map[string]bool, len(entries)),
+		done:            make(map[string]bool, len(entries)),
+		stop:            make(chan struct{}),
+		stopped:         make(chan struct{}),
+	}
+
+	// Write the compacted file aside and swap it in, so a crash here
+	// leaves the old checkpoint intact.
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(checkpointHeader{Checkpoint: CheckpointVersion, Wordlist: fingerprint}); err != nil {
+		file.Close()
+		return nil, err
+	}
+	for key, entry := range entries {
+		c.resumed[key] = true
+		c.done[key] = true
+		if err := enc.Encode(entry); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return nil, err
+	}
+
+	c.file = file
+	c.w = w
+	go c.flushLoop()
+	return c, nil
+}
+
+func (c *Checkpoint) flushLoop() {
+	defer close(c.stopped)
+	ticker := time.NewTicker(CheckpointFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.mu.Lock()
+			if !c.closed {
+				c.w.Flush()
+			}
+			c.mu.Unlock()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// Done reports whether task had completed when the checkpoint was opened.
+// Tasks recorded since do not count, so a run never skips work it has
+// already counted.
+func (c *Checkpoint) Done(task Task) bool {
+	return c.resumed[taskEntry(task).key()]
+}
+
+// Record marks task completed. Only initial-pass tasks are recorded;
+// recursion is not known up front and cannot be skipped on resume.
+func (c *Checkpoint) Record(task Task) {
+	if task.Depth != 1 {
+		return
+	}
+	entry := taskEntry(task)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || c.done[entry.key()] {
+		return
+	}
+	c.done[entry.key()] = true
+	data, _ := json.Marshal(entry)
+	c.w.Write(data)
+	c.w.WriteByte('\n')
+}
+
+// Close flushes recorded tasks and closes the file, keeping it for the next
+// --resume. Closing twice is a no-op.
+func (c *Checkpoint) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	err := c.w.Flush()
+	if cerr := c.file.Close(); err == nil {
+		err = cerr
+	}
+	c.mu.Unlock()
+
+	close(c.stop)
+	<-c.stopped
+	return err
+}
+
+// Remove closes the checkpoint and deletes its file; a scan that ran to
+// completion has nothing left to resume.
+func (c *Checkpoint) Remove() error {
+	c.Close()
+	return os.Remove(c.path)
+}
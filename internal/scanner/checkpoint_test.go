@@ -0,0 +1,201 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/config"
+)
+
+func TestCheckpoint_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.checkpoint")
+	fp := wordlistFingerprint([]string{"admin", "login"}, []string{"php"}, "", []string{"GET"}, false)
+
+	cp, err := OpenCheckpoint(path, fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp.Record(Task{TargetURL: "http://a", Path: "admin", Depth: 1})
+	cp.Record(Task{TargetURL: "http://a", Path: "admin", Depth: 1})
+	cp.Record(Task{TargetURL: "http://a", Path: "admin/login", Depth: 2})
+	cp.Record(Task{TargetURL: "http://a", Param: "debug", Depth: 1})
+	if cp.Done(Task{TargetURL: "http://a", Path: "admin", Depth: 1}) {
+		t.Error("expected tasks recorded in this run not to count as resumed")
+	}
+	if err := cp.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err = OpenCheckpoint(path, fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Close()
+	if cp.WordlistChanged {
+		t.Error("expected the same fingerprint not to be flagged as changed")
+	}
+	for _, task := range []Task{
+		{TargetURL: "http://a", Path: "admin", Depth: 1},
+		{TargetURL: "http://a", Param: "debug", Depth: 1},
+	} {
+		if !cp.Done(task) {
+			t.Errorf("expected %+v to be resumed", task)
+		}
+	}
+	for _, task := range []Task{
+		{TargetURL: "http://a", Path: "admin/login", Depth: 2},
+		{TargetURL: "http://b", Path: "admin", Depth: 1},
+		{TargetURL: "http://a", Path: "login", Depth: 1},
+	} {
+		if cp.Done(task) {
+			t.Errorf("expected %+v not to be resumed", task)
+		}
+	}
+
+	data, _ := os.ReadFile(path)
+	if n := strings.Count(string(data), "\n"); n != 3 {
+		t.Errorf("expected a header and 2 entries, got %d lines:\n%s", n, data)
+	}
+}
+
+func TestCheckpoint_TruncatedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.checkpoint")
+	content := `{"checkpoint":1,"wordlist":"abc"}
+{"target":"http://a","path":"admin"}
+{"target":"http://a","pa`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cp, err := OpenCheckpoint(path, "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cp.Done(Task{TargetURL: "http://a", Path: "admin", Depth: 1}) {
+		t.Error("expected the complete entry to be kept")
+	}
+	cp.Close()
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), `"pa`+"\n") || !strings.HasSuffix(string(data), "\n") {
+		t.Errorf("expected the damaged line to be dropped, got:\n%s", data)
+	}
+}
+
+func TestCheckpoint_WordlistChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scan.checkpoint")
+	old := wordlistFingerprint([]string{"admin"}, nil, "", []string{"GET"}, false)
+
+	cp, err := OpenCheckpoint(path, old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp.Record(Task{TargetURL: "http://a", Path: "admin", Depth: 1})
+	cp.Close()
+
+	cp, err = OpenCheckpoint(path, wordlistFingerprint([]string{"admin", "backup"}, nil, "", []string{"GET"}, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cp.Close()
+	if !cp.WordlistChanged {
+		t.Error("expected a new wordlist to be flagged")
+	}
+	if !cp.Done(Task{TargetURL: "http://a", Path: "admin", Depth: 1}) {
+		t.Error("expected recorded tasks to be kept after a wordlist change")
+	}
+}
+
+func TestCheckpoint_RejectsOtherFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wordlist.txt")
+	if err := os.WriteFile(path, []byte("admin\nlogin\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckCheckpoint(path); err == nil {
+		t.Fatal("expected a wordlist to be rejected as a checkpoint")
+	}
+	if _, err := OpenCheckpoint(path, "abc"); err == nil {
+		t.Fatal("expected OpenCheckpoint to refuse a non-checkpoint file")
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "admin\nlogin\n" {
+		t.Errorf("expected the file to be left alone, got %q", data)
+	}
+
+	if err := CheckCheckpoint(filepath.Join(t.TempDir(), "missing")); err != nil {
+		t.Errorf("expected a missing checkpoint to be accepted, got %v", err)
+	}
+}
+
+func TestEngineResume(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+		if r.URL.Path == "/admin" {
+			w.Write([]byte("admin panel"))
+			return
+		}
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+
+	words := []string{"admin", "login", "backup"}
+	path := filepath.Join(t.TempDir(), "scan.checkpoint")
+	cp, err := OpenCheckpoint(path, wordlistFingerprint(words, nil, "", []string{"GET"}, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cp.Record(Task{TargetURL: server.URL, Path: "login", Depth: 1})
+	cp.Record(Task{TargetURL: server.URL, Path: "admin", Depth: 1})
+	cp.Close()
+
+	cfg := config.Config{
+		Wordlist:      createWordlist(t, words...),
+		Threads:       2,
+		Timeout:       5,
+		MaxResponseMB: 10,
+		Resume:        path,
+	}
+	engine := NewEngine(cfg)
+	plan, err := engine.Plan([]string{server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan) != 1 || plan[0] != server.URL+"/backup" {
+		t.Errorf("expected the plan to hold only the unfinished path, got %v", plan)
+	}
+
+	results, stats, err := engine.Run([]string{server.URL})
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if requested["/admin"] || requested["/login"] {
+		t.Errorf("expected checkpointed paths to be skipped, got %v", requested)
+	}
+	if !requested["/backup"] {
+		t.Error("expected the unfinished path to be requested")
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no findings from skipped paths, got %d", len(results))
+	}
+	if got := stats.GetTotal(); got != 1 {
+		t.Errorf("expected total 1 after resuming, got %d", got)
+	}
+	if skipped, changed := stats.Resumed(); skipped != 2 || changed {
+		t.Errorf("expected 2 skipped tasks and an unchanged wordlist, got %d, %v", skipped, changed)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected the checkpoint to be removed after a complete scan, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// ValidatorCacheVersion is the format version written to --conditional-cache
+// files; files with another version are refused.
+const ValidatorCacheVersion = 1
+
+// Validators are the cache validators a response carried. Under
+// --conditional-cache they are sent back on the next scan as
+// If-None-Match and If-Modified-Since.
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// Empty reports whether there is nothing to make a request conditional on.
+func (v Validators) Empty() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// merge returns v with any validators in header replacing the old ones, as
+// a 304 may carry a fresh ETag.
+func (v Validators) merge(header http.Header) Validators {
+	next := validatorsOf(header)
+	if next.ETag == "" {
+		next.ETag = v.ETag
+	}
+	if next.LastModified == "" {
+		next.LastModified = v.LastModified
+	}
+	return next
+}
+
+// validatorsOf returns the validators in a response's headers.
+func validatorsOf(header http.Header) Validators {
+	return Validators{ETag: header.Get("ETag"), LastModified: header.Get("Last-Modified")}
+}
+
+// setConditional adds the If-None-Match and If-Modified-Since headers for v.
+func setConditional(header http.Header, v Validators) {
+	if v.ETag != "" {
+		header.Set("If-None-Match", v.ETag)
+	}
+	if v.LastModified != "" {
+		header.Set("If-Modified-Since", v.LastModified)
+	}
+}
+
+type validatorCacheFile struct {
+	ValidatorCache int                   `json:"validator_cache"`
+	Entries        map[string]Validators `json:"entries"`
+}
+
+// ValidatorCache holds the ETag and Last-Modified of reported findings,
+// keyed by URL, from one scan to the next (--conditional-cache). It is
+// safe for concurrent use.
+type ValidatorCache struct {
+	path string
+
+	// previous is what the file held when opened. It never changes, so
+	// every request in a run sees the same validators.
+	previous map[string]Validators
+
+	mu        sync.Mutex
+	requested map[string]bool
+	current   map[string]Validators
+}
+
+// readValidatorCache loads the entries in path. A missing or empty file is
+// an empty cache; a file that is not a validator cache is an error so an
+// unrelated file is never overwritten.
+func readValidatorCache(path string) (map[string]Validators, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) || err == nil && len(data) == 0 {
+		return make(map[string]Validators), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file validatorCacheFile
+	if err := json.Unmarshal(data, &file); err != nil || file.ValidatorCache == 0 {
+		return nil, fmt.Errorf("%s is not a capsaicin conditional cache", path)
+	}
+	if file.ValidatorCache != ValidatorCacheVersion {
+		return nil, fmt.Errorf("conditional cache %s has unsupported version %d", path, file.ValidatorCache)
+	}
+	if file.Entries == nil {
+		file.Entries = make(map[string]Validators)
+	}
+	return file.Entries, nil
+}
+
+// CheckValidatorCache reports whether path can be used with
+// --conditional-cache: it must be missing, empty or a cache this version
+// writes.
+func CheckValidatorCache(path string) error {
+	_, err := readValidatorCache(path)
+	return err
+}
+
+// OpenValidatorCache loads the cache at path. Nothing is written until Save.
+func OpenValidatorCache(path string) (*ValidatorCache, error) {
+	previous, err := readValidatorCache(path)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorCache{
+		path:      path,
+		previous:  previous,
+		requested: make(map[string]bool),
+		current:   make(map[string]Validators),
+	}, nil
+}
+
+// Lookup returns the validators stored for url by an earlier scan and notes
+// that this scan requested it.
+func (c *ValidatorCache) Lookup(url string) Validators {
+	c.mu.Lock()
+	c.requested[url] = true
+	c.mu.Unlock()
+	return c.previous[url]
+}
+
+// Record stores the validators of a finding for the next scan. Empty
+// validators are ignored.
+func (c *ValidatorCache) Record(url string, v Validators) {
+	if v.Empty() {
+		return
+	}
+	c.mu.Lock()
+	c.current[url] = v
+	c.mu.Unlock()
+}
+
+// Save writes the validators recorded by this scan, plus those of URLs it
+// did not request, so a scan with a shorter wordlist keeps the rest. A URL
+// requested but not recorded, because it is gone or no longer reported, is
+// dropped. The file is written aside and swapped in.
+func (c *ValidatorCache) Save() error {
+	c.mu.Lock()
+	entries := make(map[string]Validators, len(c.current)+len(c.previous))
+	for url, v := range c.previous {
+		if !c.requested[url] {
+			entries[url] = v
+		}
+	}
+	for url, v := range c.current {
+		entries[url] = v
+	}
+	c.mu.Unlock()
+
+	data, err := json.Marshal(validatorCacheFile{ValidatorCache: ValidatorCacheVersion, Entries: entries})
+	if err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
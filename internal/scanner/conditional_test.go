@@ -0,0 +1,142 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/config"
+)
+
+func TestValidatorCache_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "validators.json")
+
+	cache, err := OpenValidatorCache(path)
+	if err != nil {
+		t.Fatalf("open missing cache: %v", err)
+	}
+	cache.Lookup("http://t/a")
+	cache.Record("http://t/a", Validators{ETag: `"a1"`})
+	cache.Record("http://t/b", Validators{LastModified: "Wed, 21 Oct 2015 07:28:00 GMT"})
+	cache.Record("http://t/empty", Validators{})
+	if err := cache.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	cache, err = OpenValidatorCache(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if v := cache.Lookup("http://t/a"); v.ETag != `"a1"` {
+		t.Errorf("expected the stored ETag, got %+v", v)
+	}
+	if v := cache.Lookup("http://t/empty"); !v.Empty() {
+		t.Errorf("expected no validators for a response without them, got %+v", v)
+	}
+	// /a was requested again and not recorded, so it is gone; /b was not
+	// requested and is kept.
+	if err := cache.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	cache, _ = OpenValidatorCache(path)
+	if v := cache.Lookup("http://t/a"); !v.Empty() {
+		t.Errorf("expected /a dropped, got %+v", v)
+	}
+	if v := cache.Lookup("http://t/b"); v.LastModified == "" {
+		t.Error("expected /b kept")
+	}
+}
+
+func TestValidatorCache_RejectsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	other := filepath.Join(dir, "report.json")
+	os.WriteFile(other, []byte(`{"results": []}`), 0644)
+	if err := CheckValidatorCache(other); err == nil || !strings.Contains(err.Error(), "not a capsaicin conditional cache") {
+		t.Errorf("expected an unrelated file to be refused, got %v", err)
+	}
+	future := filepath.Join(dir, "future.json")
+	os.WriteFile(future, []byte(`{"validator_cache": 99}`), 0644)
+	if err := CheckValidatorCache(future); err == nil || !strings.Contains(err.Error(), "unsupported version") {
+		t.Errorf("expected a newer format to be refused, got %v", err)
+	}
+	empty := filepath.Join(dir, "empty.json")
+	os.WriteFile(empty, nil, 0644)
+	if err := CheckValidatorCache(empty); err != nil {
+		t.Errorf("expected an empty file to be accepted, got %v", err)
+	}
+}
+
+func TestEngineConditionalCache(t *testing.T) {
+	var mu sync.Mutex
+	conditional := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		conditional[r.URL.Path] = r.Header.Get("If-None-Match") + "|" + r.Header.Get("If-Modified-Since")
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/report.pdf":
+			w.Header().Set("ETag", `"v1"`)
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Write([]byte(strings.Repeat("pdf data ", 100)))
+		case "/news":
+			w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+			w.Write([]byte("fresh news"))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "validators.json")
+	cfg := config.Config{
+		Wordlist:         createWordlist(t, "report.pdf", "news"),
+		Threads:          2,
+		Timeout:          10,
+		MaxResponseMB:    10,
+		SafeMode:         true,
+		ConditionalCache: cachePath,
+	}
+	run := func() map[string]Result {
+		engine := NewEngine(cfg)
+		results, _, err := engine.Run([]string{server.URL})
+		if err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		if err := engine.SaveConditionalCache(); err != nil {
+			t.Fatalf("save cache: %v", err)
+		}
+		byPath := map[string]Result{}
+		for _, r := range results {
+			byPath[extractPath(r.URL)] = r
+		}
+		return byPath
+	}
+
+	first := run()
+	if first["/report.pdf"].StatusCode != 200 || conditional["/report.pdf"] != "|" {
+		t.Fatalf("expected a plain first request, got %d with %q", first["/report.pdf"].StatusCode, conditional["/report.pdf"])
+	}
+
+	second := run()
+	pdf := second["/report.pdf"]
+	if pdf.StatusCode != http.StatusNotModified || pdf.Size != 0 || !containsTag(pdf.Tags, "not-modified") || !hasReason(pdf, ReasonNotModified) {
+		t.Errorf("expected /report.pdf reported as not modified, got %d %v %v", pdf.StatusCode, pdf.Tags, pdf.Reasons)
+	}
+	// /news ignores If-Modified-Since and is scanned as usual.
+	if conditional["/news"] != "|Wed, 21 Oct 2015 07:28:00 GMT" || second["/news"].StatusCode != 200 || containsTag(second["/news"].Tags, "not-modified") {
+		t.Errorf("expected /news requested conditionally and reported normally, got %q %+v", conditional["/news"], second["/news"])
+	}
+
+	// The 304 keeps the validators for the run after.
+	third := run()
+	if third["/report.pdf"].StatusCode != http.StatusNotModified {
+		t.Errorf("expected the ETag carried over a 304, got %d", third["/report.pdf"].StatusCode)
+	}
+}
@@ -0,0 +1,39 @@
+package scanner
+
+import (
+	"mime"
+	"path"
+	"strings"
+
+	"github.com/capsaicin/scanner/internal/detection"
+)
+
+// interestingType reports whether the result's media type matches one of
+// the --interesting-types patterns, such as application/json or
+// text/x-*. The sniffed type is used when the declared one is missing or
+// generic. Patterns are expected in lower case.
+func interestingType(result *Result, patterns []string) bool {
+	if len(patterns) == 0 {
+		return false
+	}
+	mediaType := mediaTypeOf(detection.EffectiveContentType(result.ContentType, result.SniffedContentType))
+	if mediaType == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, mediaType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaTypeOf strips parameters such as charset from a Content-Type and
+// lowercases it: "Application/JSON; charset=utf-8" -> "application/json".
+func mediaTypeOf(contentType string) string {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		return mediaType
+	}
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
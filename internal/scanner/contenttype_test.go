@@ -0,0 +1,30 @@
+package scanner
+
+import "testing"
+
+func TestInterestingType(t *testing.T) {
+	patterns := []string{"application/json", "text/x-*"}
+	tests := []struct {
+		contentType string
+		sniffed     string
+		want        bool
+	}{
+		{"application/json", "", true},
+		{"Application/JSON; charset=utf-8", "", true},
+		{"text/x-python", "", true},
+		{"text/html; charset=utf-8", "", false},
+		{"application/jsonp", "", false},
+		{"", "", false},
+		// A generic declared type falls back to the sniffed one.
+		{"application/octet-stream", "application/json", true},
+	}
+	for _, tt := range tests {
+		result := &Result{ContentType: tt.contentType, SniffedContentType: tt.sniffed}
+		if got := interestingType(result, patterns); got != tt.want {
+			t.Errorf("interestingType(%q, %q) = %v, want %v", tt.contentType, tt.sniffed, got, tt.want)
+		}
+	}
+	if interestingType(&Result{ContentType: "application/json"}, nil) {
+		t.Error("expected no match without patterns")
+	}
+}
@@ -3,6 +3,8 @@ package scanner
 import (
 	"bufio"
 	"context"
+	"fmt"
+	"io"
 	"math/rand"
 	"os"
 	"strings"
@@ -14,25 +16,130 @@ import (
 	"github.com/capsaicin/scanner/internal/transport"
 )
 
+// Adaptive timeout bounds: a request may take up to this multiple of the
+// host's p95 latency, never less than the floor nor more than --timeout.
+// Until the host has enough samples, the multiple of its calibration
+// baseline applies instead.
+const (
+	adaptiveTimeoutMultiplier         = 3
+	adaptiveTimeoutBaselineMultiplier = 4
+	adaptiveTimeoutFloor              = time.Second
+)
+
 type Engine struct {
 	config     config.Config
 	client     *transport.Client
+	tokens     *transport.TokenSource
+	hosts      *transport.HostResolver // nil unless --resolve
+	scope      *transport.Scope        // nil unless --allow or --deny
+	validators *ValidatorCache         // nil unless --conditional-cache; opened by each run
 	calCache   *detection.CalibrationCache
 	stats      *Stats
 	statsReady chan struct{}
+
+	resultsMu sync.Mutex
+	results   []Result
+	sinks     []ResultSink
 }
 
 func NewEngine(cfg config.Config) *Engine {
+	var opts []transport.Option
+	if cfg.UnixSocket != "" {
+		opts = append(opts, transport.WithUnixSocket(cfg.UnixSocket))
+	}
+	if cfg.BodyTimeout > 0 {
+		opts = append(opts, transport.WithBodyTimeout(time.Duration(cfg.BodyTimeout)*time.Second))
+	}
+	if tlsOpt, err := transport.TLSAllowList(cfg.TLSProfile, cfg.TLSCipherSuites, cfg.TLSCurves); err == nil && tlsOpt != nil {
+		opts = append(opts, tlsOpt)
+	}
+	if cfg.AdaptiveTimeout {
+		opts = append(opts, transport.WithAdaptiveTimeout(adaptiveTimeoutMultiplier, adaptiveTimeoutFloor, time.Duration(cfg.Timeout)*time.Second))
+	}
+	if cfg.MaxHeaderKB > 0 || cfg.MaxHeaders > 0 {
+		opts = append(opts, transport.WithHeaderLimits(int64(cfg.MaxHeaderKB)*1024, cfg.MaxHeaders))
+	}
+	if cfg.SNI != "" {
+		opts = append(opts, transport.WithSNI(cfg.SNI))
+	}
+	if cfg.Insecure {
+		opts = append(opts, transport.WithInsecureSkipVerify())
+	}
+	if version, err := config.TLSVersion(cfg.TLSMinVersion); err == nil && version != 0 {
+		opts = append(opts, transport.WithTLSMinVersion(version))
+	}
+	// The proxy dialer goes first so --connect-to rewrites the address the
+	// SOCKS proxy is asked for.
+	if proxyURL, err := config.ProxyURL(cfg.Proxy); err == nil && proxyURL != nil {
+		if proxyOpt, err := transport.WithProxy(proxyURL); err == nil {
+			opts = append(opts, proxyOpt)
+		}
+	}
+	if connectTo, err := config.ConnectToMap(cfg.ConnectTo); err == nil && len(connectTo) > 0 {
+		opts = append(opts, transport.WithConnectTo(connectTo))
+	}
+	// Validated at startup; see transport.NewScope.
+	scope, _ := transport.NewScope(cfg.AllowPatterns, cfg.DenyPatterns)
+	if scope != nil {
+		opts = append(opts, transport.WithScope(scope))
+	}
+	if cfg.HTTP2 {
+		opts = append(opts, transport.WithHTTP2())
+	}
+	if cfg.HTTP1 {
+		opts = append(opts, transport.WithHTTP1())
+	}
+	if cfg.ThreadsPerHost > 0 {
+		opts = append(opts, transport.WithHostConcurrency(cfg.ThreadsPerHost))
+	}
+	if cfg.RateLimitGlobal > 0 {
+		opts = append(opts, transport.WithGlobalRateLimit(cfg.RateLimitGlobal))
+	}
+	if cfg.FollowRedirects {
+		opts = append(opts, transport.WithFollowRedirects(cfg.MaxRedirects))
+	}
+	if cfg.UseCookieJar {
+		opts = append(opts, transport.WithCookieJar())
+	}
+	if cfg.Seed != 0 {
+		opts = append(opts, transport.WithSeed(cfg.Seed))
+		detection.SeedCalibration(cfg.Seed)
+	}
+	if cfg.ReadOnly {
+		// Workers skip everything --safe-mode skips; the transport refuses
+		// any non-GET that slips through.
+		cfg.SafeMode = true
+		opts = append(opts, transport.WithReadOnly())
+	}
+
+	var tokens *transport.TokenSource
+	if cfg.TokenCmd != "" {
+		// Validated at startup; see transport.NewTokenSource.
+		if src, err := transport.NewTokenSource(cfg.TokenCmd, time.Duration(cfg.TokenRefresh)*time.Second); err == nil {
+			tokens = src
+			opts = append(opts, transport.WithTokenSource(src))
+		}
+	}
+
 	client := transport.NewClient(
 		cfg.Timeout,
 		cfg.RateLimit,
 		cfg.RetryAttempts,
 		cfg.MaxResponseMB,
+		opts...,
 	)
 
+	var hosts *transport.HostResolver
+	if cfg.Resolve {
+		hosts = transport.NewHostResolver(nil)
+	}
+
 	return &Engine{
 		config:     cfg,
 		client:     client,
+		tokens:     tokens,
+		hosts:      hosts,
+		scope:      scope,
 		calCache:   detection.NewCalibrationCache(),
 		statsReady: make(chan struct{}),
 	}
@@ -45,6 +152,17 @@ func (e *Engine) WaitForStats() *Stats {
 	return e.stats
 }
 
+// Stats returns the scan's live Stats, or nil if the scan has not
+// initialized them yet. It never blocks.
+func (e *Engine) Stats() *Stats {
+	select {
+	case <-e.statsReady:
+		return e.stats
+	default:
+		return nil
+	}
+}
+
 // WaitForStatsCtx blocks until the scan engine has initialized its Stats,
 // or the context is cancelled. Returns nil if context is cancelled first.
 func (e *Engine) WaitForStatsCtx(ctx context.Context) *Stats {
@@ -56,6 +174,20 @@ func (e *Engine) WaitForStatsCtx(ctx context.Context) *Stats {
 	}
 }
 
+// AddSink registers a sink that receives every result as it is collected.
+// Sinks must be added before the scan starts.
+func (e *Engine) AddSink(sink ResultSink) {
+	e.sinks = append(e.sinks, sink)
+}
+
+// Results returns a copy of the results collected so far. Safe to call
+// while a scan is running, e.g. to render a live report.
+func (e *Engine) Results() []Result {
+	e.resultsMu.Lock()
+	defer e.resultsMu.Unlock()
+	return append([]Result(nil), e.results...)
+}
+
 func (e *Engine) Run(targets []string) ([]Result, *Stats, error) {
 	return e.RunContext(context.Background(), targets)
 }
@@ -69,29 +201,170 @@ func (e *Engine) RunWithEvents(ctx context.Context, targets []string, eventCh ch
 		defer close(eventCh)
 	}
 
-	words, err := loadWordlist(e.config.Wordlist)
+	// The collector cancels this context to stop early (--max-findings).
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	targets, outOfScope := e.scopeTargets(targets)
+
+	words, err := e.loadWords()
+	if err != nil {
+		return nil, nil, err
+	}
+	targetWords, err := e.targetWords(targets, words)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	initialTaskCount := int64(len(targets) * len(words) * (1 + len(e.config.Extensions)))
+	// Fetch the first token up front so a broken --token-cmd fails the
+	// scan instead of every request.
+	if e.tokens != nil {
+		if _, err := e.tokens.Token(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	paramMode := e.config.ParamsWordlist != ""
+	extensions := e.extensions()
+	extMode := e.config.ExtMode
+	methods := e.config.RequestMethods()
+
+	// Under --resume, tasks the checkpoint holds are neither counted nor
+	// sent. pending[i] is what is left for targets[i].
+	var checkpoint *Checkpoint
+	if e.config.Resume != "" {
+		checkpoint, err = OpenCheckpoint(e.config.Resume, wordlistFingerprint(words, extensions, extMode, methods, paramMode))
+		if err != nil {
+			return nil, nil, err
+		}
+		defer checkpoint.Close()
+	}
+	if e.config.ConditionalCache != "" {
+		if e.validators, err = OpenValidatorCache(e.config.ConditionalCache); err != nil {
+			return nil, nil, err
+		}
+	}
+	pending := make([]int64, len(targets))
+	initialTaskCount := int64(0)
+	skipped := int64(0)
+	for i, target := range targets {
+		pending[i] = wordTaskCount(targetWords[target], extensions, extMode, methods, paramMode)
+		if checkpoint != nil {
+			wordTasks(target, targetWords[target], extensions, extMode, methods, paramMode, func(task Task) bool {
+				if checkpoint.Done(task) {
+					pending[i]--
+					skipped++
+				}
+				return true
+			})
+		}
+		initialTaskCount += pending[i]
+	}
 	stats := NewStats(initialTaskCount)
+	if checkpoint != nil {
+		stats.resumed = skipped
+		stats.wordlistChanged = checkpoint.WordlistChanged
+	}
+
+	for _, target := range outOfScope {
+		stats.SetTargetError(target, "out of scope: excluded by --allow/--deny")
+	}
 
 	// Expose stats to callers waiting on WaitForStats().
 	e.stats = stats
 	close(e.statsReady)
 
+	// Validated at startup; a parse error here means the default strategy.
+	calStrategies, _ := detection.ParseCalibrationStrategies(e.config.CalStrategies)
+
 	for _, target := range targets {
 		select {
 		case <-ctx.Done():
 			return nil, stats, ctx.Err()
 		default:
 		}
-		detection.PerformCalibration(ctx, target, e.client.HTTPClient(), e.config.CustomHeaders, e.calCache)
+		calHeaders := calibrationHeaders(e.config.ForTarget(target))
+		if e.hosts != nil {
+			stats.SetTargetHost(target, e.hosts.Lookup(ctx, hostOf(target)))
+		}
+		if paramMode {
+			detection.PerformCalibrationStrategies(ctx, target, e.client.HTTPClient(), calHeaders, e.calCache, nil, []detection.CalibrationStrategy{paramCalibration(target)})
+			continue
+		}
+		detection.PerformCalibrationStrategies(ctx, target, e.client.HTTPClient(), calHeaders, e.calCache, func(path string) string {
+			return applyEvasion(path, e.config.Evasion)
+		}, calStrategies)
+	}
+
+	// Calibration timed each target, so slow and fast hosts get their own
+	// timeout from the first request instead of after the p95 window fills.
+	if e.config.AdaptiveTimeout {
+		for _, target := range targets {
+			if baseline, ok := e.calCache.Latency(target); ok {
+				e.client.SeedAdaptiveTimeout(hostOf(target), baseline*adaptiveTimeoutBaselineMultiplier)
+			}
+		}
+	}
+
+	// A target whose every calibration probe failed the TLS handshake would
+	// fail every request the same way; skip it and say why.
+	scanTargets := make([]string, 0, len(targets))
+	scanPending := make([]int64, 0, len(targets))
+	initialTaskCount = 0
+	for i, target := range targets {
+		if reason := e.calCache.TLSError(target); reason != "" {
+			stats.SetTargetError(target, reason)
+			stats.IncrementTotal(-pending[i])
+			continue
+		}
+		scanTargets = append(scanTargets, target)
+		scanPending = append(scanPending, pending[i])
+		initialTaskCount += pending[i]
 	}
 
-	var results []Result
-	var resultsMutex sync.Mutex
+	// --robots seeds are sent ahead of the wordlist for each target.
+	var seeds [][]string
+	if e.config.Robots {
+		seeds = make([][]string, len(scanTargets))
+		maxBytes := int64(e.config.MaxResponseMB) * 1024 * 1024
+		for i, target := range scanTargets {
+			wordPaths := make(map[string]bool)
+			wordTasks("", targetWords[target], extensions, extMode, methods[:1], false, func(task Task) bool {
+				wordPaths[task.Path] = true
+				return true
+			})
+			calHeaders := calibrationHeaders(e.config.ForTarget(target))
+			for _, path := range harvestSeeds(ctx, target, e.client.HTTPClient(), calHeaders, maxBytes, wordPaths) {
+				seeds[i] = append(seeds[i], path)
+				for _, method := range methods {
+					if checkpoint != nil && checkpoint.Done(Task{TargetURL: target, Path: path, Method: method, Depth: 1}) {
+						continue
+					}
+					scanPending[i]++
+					initialTaskCount++
+					stats.IncrementTotal(1)
+				}
+			}
+		}
+	}
+
+	// --git-check probes are sent ahead of the seeds and the wordlist.
+	if e.config.GitCheck {
+		for i, target := range scanTargets {
+			for _, task := range vcsTasks(target) {
+				if checkpoint != nil && checkpoint.Done(task) {
+					continue
+				}
+				scanPending[i]++
+				initialTaskCount++
+				stats.IncrementTotal(1)
+			}
+		}
+	}
+
+	e.resultsMu.Lock()
+	e.results = nil
+	e.resultsMu.Unlock()
 	dedup := NewDeduplicator()
 
 	scannedDirs := make(map[string]map[string]bool)
@@ -109,10 +382,30 @@ func (e *Engine) RunWithEvents(ctx context.Context, targets []string, eventCh ch
 		defer wg.Done()
 		for result := range resultChan {
 			r := result // copy for pointer
-			if dedup.Add(&r) {
-				resultsMutex.Lock()
-				results = append(results, r)
-				resultsMutex.Unlock()
+			if e.hosts != nil {
+				if info, ok := e.hosts.Cached(hostOf(r.URL)); ok && len(info.IPs) > 0 {
+					r.ResolvedIP = info.IPs[0]
+				}
+			}
+			added := dedup.Add(&r)
+			if added && e.config.ActionableOnly && r.Class != ClassActionable {
+				stats.IncrementSuppressed()
+				added = false
+			}
+			if added {
+				stats.AddSecretTypes(r.SecretTypes)
+				if r.Class == ClassActionable {
+					stats.IncrementActionable()
+				}
+
+				e.resultsMu.Lock()
+				e.results = append(e.results, r)
+				e.resultsMu.Unlock()
+
+				// Sinks surface their own write failures from Close.
+				for _, sink := range e.sinks {
+					sink.Write(r)
+				}
 
 				// Emit live result event to UI.
 				if eventCh != nil {
@@ -126,20 +419,57 @@ func (e *Engine) RunWithEvents(ctx context.Context, targets []string, eventCh ch
 			if result.WAFDetected != "" {
 				stats.IncrementWAFHits()
 			}
+
+			if limit := e.config.MaxFindings; limit > 0 && stats.GetFound() >= int64(limit) && stats.GetStopReason() == "" {
+				stats.SetStopReason(fmt.Sprintf("max findings reached (%d)", limit))
+				cancel()
+			}
 		}
 	}()
 
-	if e.config.MaxDepth > 0 {
+	// Recursion and --backup-probe both feed follow-up tasks back
+	// through newTaskChan.
+	followUps := e.config.MaxDepth > 0 || e.config.BackupProbe
+	if followUps {
+		patterns := backupPatterns(e.config)
+		probed := make(map[string]map[string]bool)
+		// sendBackups queues the backup permutations of a discovered
+		// file, each at most once per target.
+		sendBackups := func(file Task) {
+			defer file.done(&taskWg)
+			if probed[file.TargetURL] == nil {
+				probed[file.TargetURL] = make(map[string]bool)
+			}
+			for _, path := range backupPaths(file.Path, patterns) {
+				if probed[file.TargetURL][path] {
+					continue
+				}
+				probed[file.TargetURL][path] = true
+				file.spawn(&taskWg)
+				select {
+				case taskChan <- Task{TargetURL: file.TargetURL, Path: path, Depth: file.Depth, Backup: true, active: file.active}:
+					stats.IncrementTotal(1)
+				case <-ctx.Done():
+					file.done(&taskWg)
+					return
+				}
+			}
+		}
+
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			for newTask := range newTaskChan {
 				select {
 				case <-ctx.Done():
-					taskWg.Done()
+					newTask.done(&taskWg)
 					continue
 				default:
 				}
+				if newTask.Backup {
+					sendBackups(newTask)
+					continue
+				}
 
 				dirMutex.Lock()
 				if scannedDirs[newTask.TargetURL] == nil {
@@ -149,42 +479,25 @@ func (e *Engine) RunWithEvents(ctx context.Context, targets []string, eventCh ch
 					scannedDirs[newTask.TargetURL][newTask.Path] = true
 					dirMutex.Unlock()
 
-					for _, word := range words {
-						taskWg.Add(1)
-						task := Task{
-							TargetURL: newTask.TargetURL,
-							Path:      strings.TrimSuffix(newTask.Path, "/") + "/" + word,
-							Depth:     newTask.Depth,
-						}
+					dir := strings.TrimSuffix(newTask.Path, "/") + "/"
+					wordTasks(newTask.TargetURL, targetWords[newTask.TargetURL], e.config.Extensions, extMode, methods, false, func(task Task) bool {
+						task.Path = dir + task.Path
+						task.Depth = newTask.Depth
+						task.active = newTask.active
+						newTask.spawn(&taskWg)
 						select {
 						case taskChan <- task:
-						case <-ctx.Done():
-							taskWg.Done()
-							goto skipExtensions
-						}
-						stats.IncrementTotal(1)
-
-						for _, ext := range e.config.Extensions {
-							taskWg.Add(1)
-							taskWithExt := Task{
-								TargetURL: newTask.TargetURL,
-								Path:      strings.TrimSuffix(newTask.Path, "/") + "/" + word + ext,
-								Depth:     newTask.Depth,
-							}
-							select {
-							case taskChan <- taskWithExt:
-							case <-ctx.Done():
-								taskWg.Done()
-								goto skipExtensions
-							}
 							stats.IncrementTotal(1)
+							return true
+						case <-ctx.Done():
+							newTask.done(&taskWg)
+							return false
 						}
-					}
-				skipExtensions:
-					taskWg.Done()
+					})
+					newTask.done(&taskWg)
 				} else {
 					dirMutex.Unlock()
-					taskWg.Done()
+					newTask.done(&taskWg)
 				}
 			}
 		}()
@@ -192,7 +505,11 @@ func (e *Engine) RunWithEvents(ctx context.Context, targets []string, eventCh ch
 
 	workerDone := make(chan struct{}, e.config.Threads)
 	for i := 0; i < e.config.Threads; i++ {
-		workerRng := rand.New(rand.NewSource(time.Now().UnixNano() + int64(i)))
+		seed := time.Now().UnixNano()
+		if e.config.Seed != 0 {
+			seed = e.config.Seed
+		}
+		workerRng := rand.New(rand.NewSource(seed + int64(i)))
 		go worker(
 			ctx,
 			taskChan,
@@ -202,6 +519,8 @@ func (e *Engine) RunWithEvents(ctx context.Context, targets []string, eventCh ch
 			e.client,
 			stats,
 			e.calCache,
+			checkpoint,
+			e.validators,
 			workerDone,
 			&taskWg,
 			workerRng,
@@ -212,35 +531,77 @@ func (e *Engine) RunWithEvents(ctx context.Context, targets []string, eventCh ch
 	taskWg.Add(int(initialTaskCount))
 
 	go func() {
+		// With --target-concurrency, a target holds an admission slot
+		// until all of its tasks, recursion included, have finished.
+		var admit chan struct{}
+		if e.config.TargetConcurrency > 0 {
+			admit = make(chan struct{}, e.config.TargetConcurrency)
+		}
 		sentCount := int64(0)
-		for _, target := range targets {
-			for _, word := range words {
-				task := Task{TargetURL: target, Path: word, Depth: 1}
+		for i, target := range scanTargets {
+			count := scanPending[i]
+			var active *sync.WaitGroup
+			if admit != nil {
+				select {
+				case admit <- struct{}{}:
+				case <-ctx.Done():
+					taskWg.Add(int(-(initialTaskCount - sentCount)))
+					return
+				}
+				active = &sync.WaitGroup{}
+				active.Add(int(count))
+				go func() {
+					active.Wait()
+					<-admit
+				}()
+			}
+
+			targetSent := int64(0)
+			send := func(task Task) bool {
+				task.active = active
 				select {
 				case taskChan <- task:
 					sentCount++
+					targetSent++
+					return true
 				case <-ctx.Done():
 					remaining := initialTaskCount - sentCount
 					if remaining > 0 {
 						taskWg.Add(int(-remaining))
 					}
-					return
+					if active != nil {
+						active.Add(int(targetSent - count))
+					}
+					return false
 				}
+			}
 
-				for _, ext := range e.config.Extensions {
-					taskWithExt := Task{TargetURL: target, Path: word + ext, Depth: 1}
-					select {
-					case taskChan <- taskWithExt:
-						sentCount++
-					case <-ctx.Done():
-						remaining := initialTaskCount - sentCount
-						if remaining > 0 {
-							taskWg.Add(int(-remaining))
-						}
+			sendUnlessDone := func(task Task) bool {
+				if checkpoint != nil && checkpoint.Done(task) {
+					return true
+				}
+				return send(task)
+			}
+			if e.config.GitCheck {
+				for _, task := range vcsTasks(target) {
+					if !sendUnlessDone(task) {
 						return
 					}
 				}
 			}
+			if seeds != nil {
+				for _, path := range seeds[i] {
+					for _, method := range methods {
+						if !sendUnlessDone(Task{TargetURL: target, Path: path, Method: method, Depth: 1, Seeded: true}) {
+							return
+						}
+					}
+				}
+			}
+			sent := wordTasks(target, targetWords[target], extensions, extMode, methods, paramMode, sendUnlessDone)
+			if !sent {
+				return
+			}
 		}
 	}()
 
@@ -254,17 +615,155 @@ func (e *Engine) RunWithEvents(ctx context.Context, targets []string, eventCh ch
 			<-workerDone
 		}
 		close(resultChan)
-		if e.config.MaxDepth > 0 {
+		if followUps {
 			close(newTaskChan)
 		}
 	}()
 
 	wg.Wait()
 
-	return results, stats, nil
+	if e.config.ExcludeLengthAuto > 0 {
+		e.resultsMu.Lock()
+		e.results = dropAutoFiltered(e.results, stats, e.config.Verbose, true)
+		e.resultsMu.Unlock()
+	}
+
+	// A scan that ran to completion leaves nothing to resume.
+	if checkpoint != nil && ctx.Err() == nil {
+		checkpoint.Remove()
+	}
+
+	return e.Results(), stats, nil
+}
+
+// SaveConditionalCache writes the validators the last run collected to the
+// --conditional-cache file. It does nothing without --conditional-cache or
+// before a run.
+func (e *Engine) SaveConditionalCache() error {
+	if e.validators == nil {
+		return nil
+	}
+	return e.validators.Save()
+}
+
+// scopeTargets splits targets into those --allow/--deny let the scan
+// contact and those it excludes.
+func (e *Engine) scopeTargets(targets []string) (in, out []string) {
+	if e.scope == nil {
+		return targets, nil
+	}
+	in = make([]string, 0, len(targets))
+	for _, target := range targets {
+		if e.scope.Allows(hostOf(target)) {
+			in = append(in, target)
+		} else {
+			out = append(out, target)
+		}
+	}
+	return in, out
+}
+
+// DropAutoFiltered removes results whose status and size
+// --exclude-length-auto recognized as noise after they were reported; with
+// -v they are kept and tagged "auto-filtered" instead. It is meant as a
+// report filter once the scan has finished.
+func (e *Engine) DropAutoFiltered(results []Result) []Result {
+	if e.config.ExcludeLengthAuto <= 0 || e.stats == nil {
+		return results
+	}
+	return dropAutoFiltered(results, e.stats, e.config.Verbose, false)
+}
+
+// dropAutoFiltered is DropAutoFiltered; with retract, each dropped result
+// is also taken back out of the stats.
+func dropAutoFiltered(results []Result, stats *Stats, verbose, retract bool) []Result {
+	kept := make([]Result, 0, len(results))
+	for _, r := range results {
+		if !stats.NoisySize(r.StatusCode, r.Size) || hasReason(r, ReasonContentType) {
+			kept = append(kept, r)
+			continue
+		}
+		if verbose {
+			r.Tags = appendUnique(append([]string(nil), r.Tags...), "auto-filtered")
+			kept = append(kept, r)
+			continue
+		}
+		if retract {
+			stats.retractAutoFiltered(r)
+		}
+	}
+	return kept
+}
+
+// wordTasks calls fn with the task for every word, path variant (see
+// wordPaths) and method under target, in the order they are sent to
+// workers, and stops early when fn returns false. The tasks are the initial
+// pass (Depth 1); recursion rewrites Path and Depth. It reports whether
+// every task was visited.
+func wordTasks(target string, words, extensions []string, extMode string, methods []string, paramMode bool, fn func(Task) bool) bool {
+	emit := func(task Task) bool {
+		for _, method := range methods {
+			task.Method = method
+			if !fn(task) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, word := range words {
+		if paramMode {
+			if !emit(Task{TargetURL: target, Param: word, Depth: 1}) {
+				return false
+			}
+			continue
+		}
+		for _, path := range wordPaths(word, extensions, extMode) {
+			if !emit(Task{TargetURL: target, Path: path, Depth: 1}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// wordTaskCount is the number of tasks wordTasks visits for words.
+func wordTaskCount(words, extensions []string, extMode string, methods []string, paramMode bool) int64 {
+	if paramMode {
+		return int64(len(words) * len(methods))
+	}
+	var n int64
+	for _, word := range words {
+		n += int64(len(wordPaths(word, extensions, extMode)))
+	}
+	return n * int64(len(methods))
 }
 
+// SmallWordlistThreshold is the entry count below which a wordlist is
+// suspiciously small and worth a warning.
+const SmallWordlistThreshold = 5
+
+// wordlistStdin is where -w - reads from. STDIN can only be read once, but
+// the wordlist is loaded several times (counting, planning, scanning), so
+// the first load is kept for the rest of the process.
+var (
+	wordlistStdin      io.Reader = os.Stdin
+	wordlistStdinOnce  sync.Once
+	wordlistStdinWords []string
+	wordlistStdinErr   error
+)
+
+// loadWordlist reads one entry per line, skipping blanks and # comments. A
+// wordlist with no usable entries is an error: it usually means a wrong path
+// or encoding, and scanning with it would silently do nothing. The path
+// config.StdinWordlist reads STDIN.
 func loadWordlist(path string) ([]string, error) {
+	if path == config.StdinWordlist {
+		wordlistStdinOnce.Do(func() {
+			wordlistStdinWords, wordlistStdinErr = readWordlist(wordlistStdin, "from STDIN", 1024)
+		})
+		return wordlistStdinWords, wordlistStdinErr
+	}
+
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -275,9 +774,14 @@ func loadWordlist(path string) ([]string, error) {
 	if info, err := file.Stat(); err == nil && info.Size() > 0 {
 		estimatedLines = int(info.Size() / 8)
 	}
+	return readWordlist(file, path, estimatedLines)
+}
 
+// readWordlist reads the entries of one wordlist; name identifies it in
+// errors.
+func readWordlist(r io.Reader, name string, estimatedLines int) ([]string, error) {
 	words := make([]string, 0, estimatedLines)
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		word := strings.TrimSpace(scanner.Text())
 		if word != "" && !strings.HasPrefix(word, "#") {
@@ -285,13 +789,140 @@ func loadWordlist(path string) ([]string, error) {
 		}
 	}
 
-	return words, scanner.Err()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("wordlist %s contains 0 usable entries after removing comments/blanks", name)
+	}
+	return words, nil
+}
+
+// loadWordlists reads every wordlist in order and merges them, dropping
+// entries already seen so each word is requested once.
+func loadWordlists(paths []string) ([]string, error) {
+	var merged []string
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		words, err := loadWordlist(path)
+		if err != nil {
+			return nil, err
+		}
+		if merged == nil {
+			merged = make([]string, 0, len(words))
+		}
+		for _, w := range words {
+			if !seen[w] {
+				seen[w] = true
+				merged = append(merged, w)
+			}
+		}
+	}
+	return merged, nil
+}
+
+// loadWords loads the scan wordlist with --wordlist-diff and --mutations
+// applied.
+func (e *Engine) loadWords() ([]string, error) {
+	words, err := loadScanWordlist(e.config.WordlistPaths(), e.config.WordlistDiff)
+	if err != nil {
+		return nil, err
+	}
+	if modes, err := ParseMutationModes(e.config.Mutations); err == nil {
+		words = mutateWordlist(words, modes)
+	}
+	return words, nil
+}
+
+// targetWords returns the wordlist for each target: words, or the words of
+// the wordlist --targets-json gives it, prepared the same way. A file
+// shared by several targets is loaded once.
+func (e *Engine) targetWords(targets []string, words []string) (map[string][]string, error) {
+	byTarget := make(map[string][]string, len(targets))
+	byFile := make(map[string][]string)
+	for _, target := range targets {
+		path := e.config.TargetSpecs[target].Wordlist
+		if path == "" {
+			byTarget[target] = words
+			continue
+		}
+		if _, ok := byFile[path]; !ok {
+			loaded, err := loadScanWordlist([]string{path}, e.config.WordlistDiff)
+			if err != nil {
+				return nil, err
+			}
+			if modes, err := ParseMutationModes(e.config.Mutations); err == nil {
+				loaded = mutateWordlist(loaded, modes)
+			}
+			byFile[path] = loaded
+		}
+		byTarget[target] = byFile[path]
+	}
+	return byTarget, nil
+}
+
+// extensions returns the extensions tried for each word. Parameter mining
+// (--params) tries each word as a query parameter name, so none apply.
+func (e *Engine) extensions() []string {
+	if e.config.ParamsWordlist != "" {
+		return nil
+	}
+	return e.config.Extensions
 }
 
-func CountWordlist(path string) (int, error) {
-	words, err := loadWordlist(path)
+// loadScanWordlist loads and merges the wordlists for a scan. With a
+// non-empty diffPath (--wordlist-diff) only entries absent from that
+// previous wordlist are kept, in their original order; mutations are
+// applied afterwards, so they only expand the new entries.
+func loadScanWordlist(paths []string, diffPath string) ([]string, error) {
+	words, err := loadWordlists(paths)
+	if err != nil || diffPath == "" {
+		return words, err
+	}
+
+	old, err := loadWordlist(diffPath)
+	if err != nil {
+		return nil, err
+	}
+	words = diffWords(words, old)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("wordlist %s has no entries missing from %s; nothing new to scan", strings.Join(paths, ", "), diffPath)
+	}
+	return words, nil
+}
+
+// diffWords returns the entries of words that are not in old.
+func diffWords(words, old []string) []string {
+	seen := make(map[string]bool, len(old))
+	for _, w := range old {
+		seen[w] = true
+	}
+	added := make([]string, 0, len(words))
+	for _, w := range words {
+		if !seen[w] {
+			added = append(added, w)
+		}
+	}
+	return added
+}
+
+// CountWordlist returns the number of entries a scan will use: the merged,
+// de-duplicated wordlists after the --wordlist-diff filter when diffPath
+// is set.
+func CountWordlist(paths []string, diffPath string) (int, error) {
+	words, err := loadScanWordlist(paths, diffPath)
 	if err != nil {
 		return 0, err
 	}
 	return len(words), nil
 }
+
+// CountMutatedWordlist returns the number of words after applying the
+// mutation modes, i.e. the effective wordlist size for a scan.
+func CountMutatedWordlist(paths []string, diffPath string, modes []MutationMode) (int, error) {
+	words, err := loadScanWordlist(paths, diffPath)
+	if err != nil {
+		return 0, err
+	}
+	return len(mutateWordlist(words, modes)), nil
+}
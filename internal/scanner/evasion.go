@@ -0,0 +1,87 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Evasion modes for the primary request path. They apply the same path
+// tricks the bypass phase uses, but up front, for WAFs that match on the
+// literal request path.
+const (
+	EvasionNone   = "none"
+	EvasionCase   = "case"
+	EvasionEncode = "encode"
+)
+
+// applyEvasion rewrites a request path according to the evasion mode.
+// Unknown modes and EvasionNone leave the path untouched.
+func applyEvasion(path, mode string) string {
+	switch mode {
+	case EvasionCase:
+		return manipulateCase(path)
+	case EvasionEncode:
+		return encodePathSegment(path)
+	default:
+		return path
+	}
+}
+
+// applyEvasionURL applies the evasion mode to the path portion of a full URL.
+func applyEvasionURL(rawURL, mode string) string {
+	if mode == "" || mode == EvasionNone {
+		return rawURL
+	}
+	path, suffix := splitWordSuffix(extractPath(rawURL))
+	return extractBaseURL(rawURL) + applyEvasion(path, mode) + suffix
+}
+
+// encodePathSegment percent-encodes the last segment of the path.
+// /api/admin -> /api/%61%64%6d%69%6e
+func encodePathSegment(path string) string {
+	lastSlash := strings.LastIndex(path, "/")
+	if lastSlash < 0 {
+		return path
+	}
+
+	prefix := path[:lastSlash+1]
+	segment := path[lastSlash+1:]
+
+	if segment == "" {
+		return path
+	}
+
+	var encoded strings.Builder
+	encoded.WriteString(prefix)
+	for _, ch := range segment {
+		if (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') {
+			encoded.WriteString(fmt.Sprintf("%%%02x", ch))
+		} else {
+			encoded.WriteRune(ch)
+		}
+	}
+
+	return encoded.String()
+}
+
+// manipulateCase toggles the case of the first character of the last path segment.
+// /admin -> /Admin, /Admin -> /admin
+func manipulateCase(path string) string {
+	lastSlash := strings.LastIndex(path, "/")
+	if lastSlash < 0 || lastSlash >= len(path)-1 {
+		return path
+	}
+
+	prefix := path[:lastSlash+1]
+	segment := path[lastSlash+1:]
+	first := rune(segment[0])
+
+	if first >= 'a' && first <= 'z' {
+		return prefix + strings.ToUpper(string(first)) + segment[1:]
+	}
+	if first >= 'A' && first <= 'Z' {
+		return prefix + strings.ToLower(string(first)) + segment[1:]
+	}
+
+	return path
+}
@@ -0,0 +1,79 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/config"
+)
+
+func TestApplyEvasionURL(t *testing.T) {
+	tests := []struct {
+		mode     string
+		input    string
+		expected string
+	}{
+		{"", "http://example.com/admin", "http://example.com/admin"},
+		{EvasionNone, "http://example.com/admin", "http://example.com/admin"},
+		{EvasionCase, "http://example.com/admin", "http://example.com/Admin"},
+		{EvasionEncode, "http://example.com/api/admin", "http://example.com/api/%61%64%6d%69%6e"},
+		{EvasionEncode, "http://example.com/admin?debug=1", "http://example.com/%61%64%6d%69%6e?debug=1"},
+		{"bogus", "http://example.com/admin", "http://example.com/admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode+"_"+tt.input, func(t *testing.T) {
+			if got := applyEvasionURL(tt.input, tt.mode); got != tt.expected {
+				t.Errorf("applyEvasionURL(%q, %q) = %q, want %q", tt.input, tt.mode, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEngineEvasionEncode(t *testing.T) {
+	var mu sync.Mutex
+	var requestURIs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requestURIs = append(requestURIs, r.RequestURI)
+		mu.Unlock()
+		if r.URL.Path == "/admin" {
+			w.WriteHeader(200)
+			w.Write([]byte("admin"))
+			return
+		}
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+
+	cfg := config.Config{
+		Wordlist:      createWordlist(t, "admin"),
+		Threads:       1,
+		Timeout:       10,
+		MaxResponseMB: 10,
+		Evasion:       EvasionEncode,
+	}
+
+	results, _, err := NewEngine(cfg).Run([]string{server.URL})
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].URL != server.URL+"/admin" {
+		t.Errorf("expected logical URL in result, got %q", results[0].URL)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, uri := range requestURIs {
+		if !strings.Contains(uri, "%") {
+			t.Errorf("expected every request (including calibration) to be encoded, got %q", uri)
+		}
+	}
+}
@@ -8,6 +8,14 @@ const (
 	EventResultFound
 
 	EventScanComplete
+
+	// EventHostAbandoned reports a host (in URL) that exhausted
+	// --host-error-budget; its remaining tasks are skipped.
+	EventHostAbandoned
+
+	// EventWAFThrottled reports a host (in URL) that --waf-adaptive
+	// slowed down; Result is the response the WAF was detected on.
+	EventWAFThrottled
 )
 
 type ScanEvent struct {
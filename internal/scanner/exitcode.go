@@ -5,6 +5,7 @@ const (
 	ExitOK              = 0
 	ExitScanError       = 1
 	ExitThresholdFailed = 2
+	ExitChangesFound    = 3 // -monitor found added, removed or changed findings
 )
 
 func DetermineExitCode(results []Result, threshold string) int {
@@ -0,0 +1,132 @@
+package scanner
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MutationMode selects a family of wordlist mutations.
+type MutationMode string
+
+const (
+	MutationCase   MutationMode = "case"   // UPPER, lower, Capitalized
+	MutationLeet   MutationMode = "leet"   // a→4, e→3, i→1, o→0, s→5, t→7
+	MutationSlash  MutationMode = "slash"  // toggle trailing slash
+	MutationAffix  MutationMode = "affix"  // old-word, word-dev, ...
+	MutationBackup MutationMode = "backup" // word.bak, word~, ...
+)
+
+// allMutationModes lists every mode in the order variants are generated.
+var allMutationModes = []MutationMode{MutationCase, MutationLeet, MutationSlash, MutationAffix, MutationBackup}
+
+var (
+	mutationPrefixes   = []string{"old-", "new-", "dev-", "test-", "_"}
+	mutationSuffixes   = []string{"-old", "-new", "-dev", "-test", "-backup", "2"}
+	mutationBackupExts = []string{".bak", ".old", ".orig", ".save", "~"}
+	leetReplacer       = strings.NewReplacer("a", "4", "e", "3", "i", "1", "o", "0", "s", "5", "t", "7")
+)
+
+// ParseMutationModes converts mode names (as given to -mutations) into
+// MutationModes, rejecting unknown names.
+func ParseMutationModes(names []string) ([]MutationMode, error) {
+	modes := make([]MutationMode, 0, len(names))
+	for _, name := range names {
+		valid := false
+		for _, m := range allMutationModes {
+			if string(m) == name {
+				modes = append(modes, m)
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown mutation %q. Valid values: case, leet, slash, affix, backup", name)
+		}
+	}
+	return modes, nil
+}
+
+// MutateWord returns word followed by its variants for the given modes,
+// without duplicates. Modes are applied independently to the base word,
+// not chained, so the output grows linearly with the number of modes. A
+// query string or fragment on the word is kept as-is on every variant.
+func MutateWord(entry string, modes []MutationMode) []string {
+	if entry == "" {
+		return nil
+	}
+
+	variants := []string{entry}
+	seen := map[string]bool{entry: true}
+	word, suffix := splitWordSuffix(entry)
+	add := func(v string) {
+		if v != "" && !seen[v+suffix] {
+			seen[v+suffix] = true
+			variants = append(variants, v+suffix)
+		}
+	}
+	if word == "" {
+		return variants
+	}
+
+	for _, mode := range modes {
+		switch mode {
+		case MutationCase:
+			add(strings.ToUpper(word))
+			add(strings.ToLower(word))
+			add(capitalize(word))
+		case MutationLeet:
+			add(leetReplacer.Replace(strings.ToLower(word)))
+		case MutationSlash:
+			if strings.HasSuffix(word, "/") {
+				add(strings.TrimSuffix(word, "/"))
+			} else {
+				add(word + "/")
+			}
+		case MutationAffix:
+			for _, p := range mutationPrefixes {
+				add(p + word)
+			}
+			for _, s := range mutationSuffixes {
+				add(word + s)
+			}
+		case MutationBackup:
+			for _, ext := range mutationBackupExts {
+				add(word + ext)
+			}
+		}
+	}
+	return variants
+}
+
+// mutateWordlist expands every word with MutateWord, de-duplicating across
+// the whole list while preserving first-seen order.
+func mutateWordlist(words []string, modes []MutationMode) []string {
+	if len(modes) == 0 {
+		return words
+	}
+
+	seen := make(map[string]bool, len(words))
+	expanded := make([]string, 0, len(words)*2)
+	for _, word := range words {
+		for _, v := range MutateWord(word, modes) {
+			if !seen[v] {
+				seen[v] = true
+				expanded = append(expanded, v)
+			}
+		}
+	}
+	return expanded
+}
+
+// capitalize upper-cases the first character of word, which may span
+// several bytes ("über" -> "Über"). A word starting with invalid UTF-8 is
+// returned unchanged.
+func capitalize(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	if r == utf8.RuneError {
+		return word
+	}
+	return string(unicode.ToUpper(r)) + word[size:]
+}
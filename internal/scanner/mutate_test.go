@@ -0,0 +1,114 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/config"
+)
+
+func TestMutateWord(t *testing.T) {
+	tests := []struct {
+		name     string
+		word     string
+		modes    []MutationMode
+		contains []string
+	}{
+		{"no modes", "admin", nil, []string{"admin"}},
+		{"case", "admin", []MutationMode{MutationCase}, []string{"admin", "ADMIN", "Admin"}},
+		{"case multibyte", "über", []MutationMode{MutationCase}, []string{"über", "ÜBER", "Über"}},
+		{"leet", "test", []MutationMode{MutationLeet}, []string{"test", "7357"}},
+		{"slash add", "admin", []MutationMode{MutationSlash}, []string{"admin", "admin/"}},
+		{"slash remove", "admin/", []MutationMode{MutationSlash}, []string{"admin/", "admin"}},
+		{"affix", "api", []MutationMode{MutationAffix}, []string{"old-api", "api-dev", "api2"}},
+		{"backup", "config", []MutationMode{MutationBackup}, []string{"config.bak", "config~", "config.old"}},
+		{"query kept", "admin?debug=1", []MutationMode{MutationCase, MutationBackup}, []string{"ADMIN?debug=1", "admin.bak?debug=1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MutateWord(tt.word, tt.modes)
+			if got[0] != tt.word {
+				t.Errorf("expected original word first, got %v", got)
+			}
+			set := make(map[string]bool)
+			for _, v := range got {
+				if set[v] {
+					t.Errorf("duplicate variant %q in %v", v, got)
+				}
+				set[v] = true
+			}
+			for _, want := range tt.contains {
+				if !set[want] {
+					t.Errorf("expected variant %q in %v", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestMutateWord_Empty(t *testing.T) {
+	if got := MutateWord("", []MutationMode{MutationCase}); len(got) != 0 {
+		t.Errorf("expected no variants for empty word, got %v", got)
+	}
+}
+
+func TestMutateWordlist_Dedup(t *testing.T) {
+	// "ADMIN" is both a base word and a case variant of "admin".
+	words := mutateWordlist([]string{"admin", "ADMIN"}, []MutationMode{MutationCase})
+	want := []string{"admin", "ADMIN", "Admin"}
+	if len(words) != len(want) {
+		t.Fatalf("expected %v, got %v", want, words)
+	}
+	for i := range want {
+		if words[i] != want[i] {
+			t.Errorf("index %d: expected %q, got %q", i, want[i], words[i])
+		}
+	}
+}
+
+func TestParseMutationModes(t *testing.T) {
+	modes, err := ParseMutationModes([]string{"case", "backup"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(modes) != 2 || modes[0] != MutationCase || modes[1] != MutationBackup {
+		t.Errorf("unexpected modes: %v", modes)
+	}
+
+	if _, err := ParseMutationModes([]string{"reverse"}); err == nil {
+		t.Error("expected error for unknown mutation")
+	}
+}
+
+func TestEngineWithMutations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/config.bak" {
+			w.WriteHeader(200)
+			w.Write([]byte("backup"))
+			return
+		}
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+
+	cfg := config.Config{
+		Wordlist:      createWordlist(t, "config"),
+		Threads:       2,
+		Timeout:       10,
+		MaxResponseMB: 10,
+		Mutations:     []string{"backup"},
+	}
+
+	results, stats, err := NewEngine(cfg).Run([]string{server.URL})
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if len(results) != 1 || results[0].URL != server.URL+"/config.bak" {
+		t.Errorf("expected only /config.bak to be found, got %v", results)
+	}
+	if stats.GetTotal() != int64(1+len(mutationBackupExts)) {
+		t.Errorf("expected %d tasks, got %d", 1+len(mutationBackupExts), stats.GetTotal())
+	}
+}
@@ -0,0 +1,43 @@
+package scanner
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/capsaicin/scanner/internal/detection"
+)
+
+// ParamPlaceholder marks where --params substitutes each parameter name in
+// a target URL, e.g. https://t.com/page?FUZZ=test.
+const ParamPlaceholder = "FUZZ"
+
+// paramURL returns target with name added as a query parameter (name=test),
+// or substituted for ParamPlaceholder when the target contains it.
+func paramURL(target, name string) string {
+	if strings.Contains(target, ParamPlaceholder) {
+		return strings.ReplaceAll(target, ParamPlaceholder, url.QueryEscape(name))
+	}
+	sep := "?"
+	if strings.Contains(target, "?") {
+		sep = "&"
+	}
+	return target + sep + url.QueryEscape(name) + "=test"
+}
+
+// paramCalibration baselines a target for parameter mining: the URL with
+// no extra parameter (unless it is a FUZZ template) and with random
+// parameter names the application cannot know. A parameter whose response
+// differs from these is one the endpoint reads.
+func paramCalibration(target string) detection.CalibrationStrategy {
+	return func() []detection.CalibrationProbe {
+		probes := []detection.CalibrationProbe{
+			{Method: "GET", Path: paramURL(target, fmt.Sprintf("capsaicin_cal_%d", detection.ProbeID()))},
+			{Method: "GET", Path: paramURL(target, fmt.Sprintf("cal%d", detection.ProbeID()))},
+		}
+		if !strings.Contains(target, ParamPlaceholder) {
+			probes = append(probes, detection.CalibrationProbe{Method: "GET", Path: target})
+		}
+		return probes
+	}
+}
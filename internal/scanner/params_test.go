@@ -0,0 +1,80 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/config"
+)
+
+func TestParamURL(t *testing.T) {
+	tests := []struct {
+		target string
+		name   string
+		want   string
+	}{
+		{"http://t.com/page", "debug", "http://t.com/page?debug=test"},
+		{"http://t.com/page?id=1", "debug", "http://t.com/page?id=1&debug=test"},
+		{"http://t.com/page?FUZZ=1", "debug", "http://t.com/page?debug=1"},
+		{"http://t.com/page?q=FUZZ", "a b", "http://t.com/page?q=a+b"},
+		{"http://t.com/page", "a&b", "http://t.com/page?a%26b=test"},
+	}
+
+	for _, tt := range tests {
+		if got := paramURL(tt.target, tt.name); got != tt.want {
+			t.Errorf("paramURL(%q, %q): expected %q, got %q", tt.target, tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestEngineParams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/page" {
+			w.WriteHeader(404)
+			return
+		}
+		w.WriteHeader(200)
+		if r.URL.Query().Has("debug") {
+			w.Write([]byte("debug mode enabled: stack traces and internal state follow"))
+			return
+		}
+		w.Write([]byte("welcome"))
+	}))
+	defer server.Close()
+
+	wordlist := createWordlist(t, "id", "debug", "lang")
+	cfg := config.Config{
+		Wordlist:       wordlist,
+		ParamsWordlist: wordlist,
+		Extensions:     []string{"php"},
+		Threads:        2,
+		Timeout:        10,
+		MaxResponseMB:  10,
+	}
+
+	results, stats, err := NewEngine(cfg).Run([]string{server.URL + "/page"})
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if got := stats.GetProcessed(); got != 3 {
+		t.Errorf("expected 3 requests (extensions ignored), got %d", got)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 accepted parameter, got %d: %+v", len(results), results)
+	}
+
+	r := results[0]
+	if r.Param != "debug" {
+		t.Errorf("expected param debug, got %q", r.Param)
+	}
+	if r.URL != server.URL+"/page?debug=test" {
+		t.Errorf("expected URL with ?debug=test, got %s", r.URL)
+	}
+	if !containsTag(r.Tags, "param") {
+		t.Errorf("expected param tag, got %v", r.Tags)
+	}
+	if !hasReason(r, ReasonParamAccepted) {
+		t.Errorf("expected %s reason, got %v", ReasonParamAccepted, r.Reasons)
+	}
+}
@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"net/url"
+	"strings"
+)
+
+// splitWordSuffix splits a wordlist entry into its path and any query
+// string or fragment: "admin?debug=1" -> ("admin", "?debug=1").
+func splitWordSuffix(word string) (path, suffix string) {
+	if i := strings.IndexAny(word, "?#"); i >= 0 {
+		return word[:i], word[i:]
+	}
+	return word, ""
+}
+
+// withExtension appends ext to the path part of a wordlist entry, keeping
+// its query string or fragment last: ("admin?debug=1", ".php") ->
+// "admin.php?debug=1".
+func withExtension(word, ext string) string {
+	path, suffix := splitWordSuffix(word)
+	return path + ext + suffix
+}
+
+// Extension modes (--ext-mode) decide which paths a wordlist entry expands
+// to. Every mode requests the entry itself.
+const (
+	ExtModeSuffix = "suffix" // word, word.php, ... (-x)
+	ExtModePrefix = "prefix" // word, .word
+	ExtModeBoth   = "both"   // word, word.php, ..., .word
+)
+
+// wordPaths returns the paths requested for one wordlist entry, in order.
+// The suffix mode, also used for an empty mode, appends each extension; the
+// prefix mode adds a dotfile variant (see dotVariant); both does both.
+func wordPaths(word string, extensions []string, mode string) []string {
+	paths := []string{word}
+	if mode != ExtModePrefix {
+		for _, ext := range extensions {
+			paths = append(paths, withExtension(word, ext))
+		}
+	}
+	if mode == ExtModePrefix || mode == ExtModeBoth {
+		if dotted, ok := dotVariant(word); ok {
+			paths = append(paths, dotted)
+		}
+	}
+	return paths
+}
+
+// dotVariant puts a "." before the last path segment of a wordlist entry,
+// keeping a trailing slash, query string or fragment: "env" -> ".env",
+// "config/app?x=1" -> "config/.app?x=1". Entries whose last segment is
+// empty or already starts with "." have no variant.
+func dotVariant(word string) (string, bool) {
+	path, suffix := splitWordSuffix(word)
+	trimmed := strings.TrimSuffix(path, "/")
+	dir, segment := "", trimmed
+	if i := strings.LastIndex(trimmed, "/"); i >= 0 {
+		dir, segment = trimmed[:i+1], trimmed[i+1:]
+	}
+	if segment == "" || strings.HasPrefix(segment, ".") {
+		return "", false
+	}
+	return dir + "." + segment + path[len(trimmed):] + suffix, true
+}
+
+// joinURL appends a wordlist path to a target URL. Entries may carry their
+// own query string or fragment ("admin?debug=1", "app#/login"); these are
+// joined with net/url so they stay out of the path and merge with any
+// query on the target. Entries net/url cannot parse, or that are absolute
+// URLs, fall back to plain concatenation.
+func joinURL(target, path string) string {
+	naive := strings.TrimSuffix(target, "/") + "/" + strings.TrimPrefix(path, "/")
+	if !strings.ContainsAny(path, "?#") && !strings.ContainsAny(target, "?#") {
+		return naive
+	}
+
+	base, err := url.Parse(target)
+	if err != nil {
+		return naive
+	}
+	ref, err := url.Parse(path)
+	if err != nil || ref.IsAbs() || ref.Host != "" {
+		return naive
+	}
+
+	joined := *base
+	rawPath := strings.TrimSuffix(base.EscapedPath(), "/") + "/" + strings.TrimPrefix(ref.EscapedPath(), "/")
+	joined.Path, err = url.PathUnescape(rawPath)
+	if err != nil {
+		return naive
+	}
+	joined.RawPath = rawPath
+
+	switch {
+	case base.RawQuery == "":
+		joined.RawQuery = ref.RawQuery
+	case ref.RawQuery != "":
+		joined.RawQuery = base.RawQuery + "&" + ref.RawQuery
+	}
+	joined.ForceQuery = ref.ForceQuery && joined.RawQuery == ""
+	joined.Fragment = ref.Fragment
+	joined.RawFragment = ref.RawFragment
+	return joined.String()
+}
@@ -0,0 +1,152 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/config"
+)
+
+func TestJoinURL(t *testing.T) {
+	tests := []struct {
+		target   string
+		path     string
+		expected string
+	}{
+		{"http://t.com", "admin", "http://t.com/admin"},
+		{"http://t.com/", "/admin", "http://t.com/admin"},
+		{"http://t.com", "admin?debug=1", "http://t.com/admin?debug=1"},
+		{"http://t.com/app/", "admin?debug=1&x=a%20b", "http://t.com/app/admin?debug=1&x=a%20b"},
+		{"http://t.com", "app#/login", "http://t.com/app#/login"},
+		{"http://t.com", "search?q=1#top", "http://t.com/search?q=1#top"},
+		{"http://t.com/?token=abc", "admin", "http://t.com/admin?token=abc"},
+		{"http://t.com/?token=abc", "admin?debug=1", "http://t.com/admin?token=abc&debug=1"},
+		{"http://t.com", "%2e%2e/admin?x=1", "http://t.com/%2e%2e/admin?x=1"},
+		{"http://t.com", "bad%zz?x=1", "http://t.com/bad%zz?x=1"},
+	}
+
+	for _, tt := range tests {
+		if got := joinURL(tt.target, tt.path); got != tt.expected {
+			t.Errorf("joinURL(%q, %q): expected %q, got %q", tt.target, tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestWithExtension(t *testing.T) {
+	tests := []struct {
+		word     string
+		expected string
+	}{
+		{"admin", "admin.php"},
+		{"admin?debug=1", "admin.php?debug=1"},
+		{"app#top", "app.php#top"},
+	}
+
+	for _, tt := range tests {
+		if got := withExtension(tt.word, ".php"); got != tt.expected {
+			t.Errorf("withExtension(%q): expected %q, got %q", tt.word, tt.expected, got)
+		}
+	}
+}
+
+func TestWordPaths(t *testing.T) {
+	exts := []string{".php", ".bak"}
+	tests := []struct {
+		word     string
+		mode     string
+		expected string
+	}{
+		{"env", "", "env,env.php,env.bak"},
+		{"env", ExtModeSuffix, "env,env.php,env.bak"},
+		{"env", ExtModePrefix, "env,.env"},
+		{"env", ExtModeBoth, "env,env.php,env.bak,.env"},
+		{".git", ExtModePrefix, ".git"},
+		{"config/app", ExtModePrefix, "config/app,config/.app"},
+		{"admin/", ExtModePrefix, "admin/,.admin/"},
+		{"app?x=1", ExtModeBoth, "app?x=1,app.php?x=1,app.bak?x=1,.app?x=1"},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(wordPaths(tt.word, exts, tt.mode), ","); got != tt.expected {
+			t.Errorf("wordPaths(%q, %q): expected %s, got %s", tt.word, tt.mode, tt.expected, got)
+		}
+	}
+}
+
+func TestEngineExtModeBoth(t *testing.T) {
+	var mu sync.Mutex
+	requested := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[r.URL.Path] = true
+		mu.Unlock()
+		if r.URL.Path == "/.env" {
+			w.WriteHeader(200)
+			w.Write([]byte("DB_PASSWORD=x"))
+			return
+		}
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+
+	cfg := config.Config{
+		Wordlist:      createWordlist(t, "env", ".git", "config/app"),
+		Extensions:    []string{".php"},
+		ExtMode:       ExtModeBoth,
+		Threads:       2,
+		Timeout:       10,
+		MaxResponseMB: 10,
+		SafeMode:      true,
+	}
+	results, stats, err := NewEngine(cfg).Run([]string{server.URL})
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+
+	expected := []string{"/env", "/env.php", "/.env", "/.git", "/.git.php", "/config/app", "/config/app.php", "/config/.app"}
+	for _, path := range expected {
+		if !requested[path] {
+			t.Errorf("expected %s to be requested", path)
+		}
+	}
+	if stats.GetTotal() != int64(len(expected)) || stats.GetProcessed() != int64(len(expected)) {
+		t.Errorf("expected %d tasks counted and processed, got %d/%d", len(expected), stats.GetProcessed(), stats.GetTotal())
+	}
+	if len(results) != 1 || extractPath(results[0].URL) != "/.env" {
+		t.Errorf("expected /.env to be found, got %v", results)
+	}
+}
+
+func TestEngineWordlistQueryStrings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/admin.php" && r.URL.Query().Get("debug") == "1" {
+			w.WriteHeader(200)
+			w.Write([]byte("debug console"))
+			return
+		}
+		w.WriteHeader(404)
+	}))
+	defer server.Close()
+
+	cfg := config.Config{
+		Wordlist:      createWordlist(t, "admin?debug=1"),
+		Extensions:    []string{".php"},
+		Threads:       1,
+		Timeout:       10,
+		MaxResponseMB: 10,
+	}
+
+	results, _, err := NewEngine(cfg).Run([]string{server.URL})
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d: %+v", len(results), results)
+	}
+	if want := server.URL + "/admin.php?debug=1"; results[0].URL != want {
+		t.Errorf("expected %s, got %s", want, results[0].URL)
+	}
+}
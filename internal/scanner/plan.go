@@ -0,0 +1,59 @@
+package scanner
+
+// Plan returns every URL the initial pass of a scan over targets requests,
+// in the order workers receive them and as sent on the wire (after
+// --evasion). Requests with a method other than GET (--methods) are
+// prefixed with it, e.g. "POST https://host/api". --git-check probes come
+// first for each target. Calibration probes, the recursion that --depth adds for
+// discovered directories and the --backup-probe permutations of discovered
+// files are not included; none is known up front.
+// Under --resume, tasks the checkpoint already holds are left out; the
+// checkpoint is only read. Targets --allow/--deny exclude are left out too.
+func (e *Engine) Plan(targets []string) ([]string, error) {
+	targets, _ = e.scopeTargets(targets)
+	words, err := e.loadWords()
+	if err != nil {
+		return nil, err
+	}
+	targetWords, err := e.targetWords(targets, words)
+	if err != nil {
+		return nil, err
+	}
+	paramMode := e.config.ParamsWordlist != ""
+	extensions := e.extensions()
+
+	var resumed map[string]checkpointEntry
+	if e.config.Resume != "" {
+		if _, resumed, err = readCheckpoint(e.config.Resume); err != nil {
+			return nil, err
+		}
+	}
+
+	methods := e.config.RequestMethods()
+	plan := make([]string, 0, int64(len(targets))*wordTaskCount(words, extensions, e.config.ExtMode, methods, paramMode))
+	for _, target := range targets {
+		if e.config.GitCheck {
+			for _, task := range vcsTasks(target) {
+				if _, done := resumed[taskEntry(task).key()]; !done {
+					plan = append(plan, applyEvasionURL(joinURL(target, task.Path), e.config.Evasion))
+				}
+			}
+		}
+		wordTasks(target, targetWords[target], extensions, e.config.ExtMode, methods, paramMode, func(task Task) bool {
+			if _, done := resumed[taskEntry(task).key()]; done {
+				return true
+			}
+			url := joinURL(target, task.Path)
+			if paramMode {
+				url = paramURL(target, task.Param)
+			}
+			url = applyEvasionURL(url, e.config.Evasion)
+			if task.Method != "GET" {
+				url = task.Method + " " + url
+			}
+			plan = append(plan, url)
+			return true
+		})
+	}
+	return plan, nil
+}
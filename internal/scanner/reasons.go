@@ -0,0 +1,40 @@
+package scanner
+
+// Reason codes explain why a result was reported. They are recorded on
+// Result.Reasons at the decision points in the worker so audits can tell
+// which check surfaced each finding.
+const (
+	ReasonStatusInteresting = "status-interesting" // status code passed isInteresting
+	ReasonHeaderTrigger     = "header-trigger"     // response headers matched a WAF signature
+	ReasonBodyMatch         = "body-match"         // body matched a login panel signature
+	ReasonBypassSuccess     = "bypass-success"     // a 401/403 bypass strategy succeeded
+	ReasonSecretFound       = "secret-found"       // body contained a secret pattern
+	ReasonMethodFuzz        = "method-fuzz"        // an alternative method succeeded after a 405
+	ReasonManifestExposed   = "manifest-exposed"   // body parsed as a package/dependency manifest
+	ReasonParamAccepted     = "param-accepted"     // a --params query parameter changed the response
+	ReasonCachePoisoning    = "cache-poisoning"    // --cache-probe saw an injected host reflected
+	ReasonContentType       = "content-type"       // Content-Type matched --interesting-types
+	ReasonVCSExposed        = "vcs-exposed"        // a --git-check probe returned .git or .svn metadata
+	ReasonNotModified       = "not-modified"       // a --conditional-cache request got 304 Not Modified
+	ReasonWebSocket         = "websocket"          // the response came from a WebSocket handshake endpoint
+)
+
+// hasReason reports whether the result carries a reason code.
+func hasReason(r Result, reason string) bool {
+	for _, got := range r.Reasons {
+		if got == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// addReason appends a reason code to the result unless it is already present.
+func addReason(result *Result, reason string) {
+	for _, r := range result.Reasons {
+		if r == reason {
+			return
+		}
+	}
+	result.Reasons = append(result.Reasons, reason)
+}
@@ -0,0 +1,42 @@
+package scanner
+
+import (
+	"path"
+	"strings"
+
+	"github.com/capsaicin/scanner/internal/config"
+)
+
+// recursionRules holds the --recurse-on and --recurse-exclude filters.
+// Without them every directory result is recursed into.
+type recursionRules struct {
+	codes   map[int]bool
+	exclude []string
+}
+
+// newRecursionRules builds the filters from cfg. Validated at startup; a
+// parse error here means no status filter.
+func newRecursionRules(cfg config.Config) recursionRules {
+	codes, _ := config.StatusCodeSet("--recurse-on", cfg.RecurseOn)
+	exclude := make([]string, len(cfg.RecurseExclude))
+	for i, name := range cfg.RecurseExclude {
+		exclude[i] = strings.ToLower(name)
+	}
+	return recursionRules{codes: codes, exclude: exclude}
+}
+
+// allows reports whether the directory at dir, found with result's
+// status, may be recursed into. Names are compared case-insensitively
+// against the last path segment.
+func (r recursionRules) allows(result *Result, dir string) bool {
+	if len(r.codes) > 0 && !r.codes[result.StatusCode] {
+		return false
+	}
+	name := strings.ToLower(path.Base(strings.TrimSuffix(dir, "/")))
+	for _, pattern := range r.exclude {
+		if ok, _ := path.Match(pattern, name); ok {
+			return false
+		}
+	}
+	return true
+}
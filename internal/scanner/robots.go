@@ -0,0 +1,169 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// MaxSeedPaths caps how many robots.txt and sitemap paths --robots adds per
+// target, so a site with a huge sitemap does not swamp the wordlist.
+const MaxSeedPaths = 1000
+
+// maxSitemaps caps how many sitemap files are fetched per target, counting
+// /sitemap.xml, those listed in robots.txt and those in sitemap indexes.
+const maxSitemaps = 10
+
+// parseRobots extracts the Allow and Disallow paths and the Sitemap URLs
+// from a robots.txt. Lines it does not understand are skipped. Wildcard
+// rules are cut at the first * and a trailing $ is dropped, so
+// "/admin/*.php" seeds "/admin/"; rules that reduce to "/" are dropped.
+func parseRobots(body string) (paths, sitemaps []string) {
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		field, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(field)) {
+		case "allow", "disallow":
+			if i := strings.IndexByte(value, '*'); i >= 0 {
+				value = value[:i]
+			}
+			value = strings.TrimSuffix(value, "$")
+			if strings.HasPrefix(value, "/") && value != "/" {
+				paths = append(paths, value)
+			}
+		case "sitemap":
+			if value != "" {
+				sitemaps = append(sitemaps, value)
+			}
+		}
+	}
+	return paths, sitemaps
+}
+
+var sitemapLocPattern = regexp.MustCompile(`(?is)<loc>\s*(.*?)\s*</loc>`)
+
+// parseSitemap returns the <loc> URLs of a sitemap or sitemap index and
+// whether it is an index. It matches tags rather than decoding XML, so a
+// truncated or otherwise malformed file still yields what it has.
+func parseSitemap(body string) (locs []string, index bool) {
+	for _, m := range sitemapLocPattern.FindAllStringSubmatch(body, -1) {
+		loc := strings.TrimSpace(m[1])
+		loc = strings.TrimSuffix(strings.TrimPrefix(loc, "<![CDATA["), "]]>")
+		loc = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'").Replace(loc)
+		if loc != "" {
+			locs = append(locs, loc)
+		}
+	}
+	return locs, strings.Contains(strings.ToLower(body), "<sitemapindex")
+}
+
+// targetRelative returns rawURL as a path relative to target, or false
+// when it is on another host or outside the target's base path. rawURL may
+// also be a path from the host root.
+func targetRelative(target, rawURL string) (string, bool) {
+	base, err := url.Parse(target)
+	if err != nil {
+		return "", false
+	}
+	u, err := base.Parse(rawURL)
+	if err != nil || !strings.EqualFold(u.Host, base.Host) {
+		return "", false
+	}
+	prefix := strings.TrimSuffix(base.EscapedPath(), "/") + "/"
+	path := u.EscapedPath()
+	if !strings.HasPrefix(path, prefix) {
+		return "", false
+	}
+	rel := strings.TrimPrefix(path, prefix)
+	if u.RawQuery != "" {
+		rel += "?" + u.RawQuery
+	}
+	return rel, rel != ""
+}
+
+// harvestSeeds fetches /robots.txt and the target's sitemaps and returns
+// the paths they advertise relative to target, robots.txt first, without
+// duplicates or entries already in skip. Fetch and parse errors only mean
+// fewer seeds.
+func harvestSeeds(ctx context.Context, target string, client *http.Client, headers map[string]string, maxBytes int64, skip map[string]bool) []string {
+	root := extractBaseURL(target)
+	seen := make(map[string]bool)
+	var seeds []string
+	add := func(rawURL string) {
+		rel, ok := targetRelative(target, rawURL)
+		if !ok || seen[rel] || skip[rel] || len(seeds) >= MaxSeedPaths {
+			return
+		}
+		seen[rel] = true
+		seeds = append(seeds, rel)
+	}
+
+	robots, _ := fetchSeedFile(ctx, root+"/robots.txt", client, headers, maxBytes)
+	paths, sitemaps := parseRobots(robots)
+	for _, path := range paths {
+		add(path)
+	}
+
+	queue := append([]string{root + "/sitemap.xml"}, sitemaps...)
+	fetched := make(map[string]bool)
+	for len(queue) > 0 && len(fetched) < maxSitemaps && len(seeds) < MaxSeedPaths {
+		sitemap := queue[0]
+		queue = queue[1:]
+		if fetched[sitemap] {
+			continue
+		}
+		if _, ok := targetRelative(root, sitemap); !ok {
+			continue
+		}
+		fetched[sitemap] = true
+		body, err := fetchSeedFile(ctx, sitemap, client, headers, maxBytes)
+		if err != nil {
+			continue
+		}
+		locs, index := parseSitemap(body)
+		if index {
+			queue = append(queue, locs...)
+			continue
+		}
+		for _, loc := range locs {
+			add(loc)
+		}
+	}
+	return seeds
+}
+
+// fetchSeedFile GETs rawURL and returns its body, read up to maxBytes. Any
+// status other than 200 is an error.
+func fetchSeedFile(ctx context.Context, rawURL string, client *http.Client, headers map[string]string, maxBytes int64) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: status %d", rawURL, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes))
+	return string(body), err
+}
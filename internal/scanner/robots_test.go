@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRobots(t *testing.T) {
+	body := "User-agent: *\n" +
+		"Disallow: /admin/   # staff only\n" +
+		"disallow:/private/*.pdf\n" +
+		"Allow: /public$\n" +
+		"Disallow: /\n" +
+		"Disallow:\n" +
+		"Disallow: relative/path\n" +
+		"this line is not a rule\n" +
+		"Crawl-delay: 10\n" +
+		"SITEMAP: https://example.com/sitemap-pages.xml\n" +
+		"\x00\xff garbage: \n"
+	paths, sitemaps := parseRobots(body)
+	if want := []string{"/admin/", "/private/", "/public"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("paths = %q, want %q", paths, want)
+	}
+	if want := []string{"https://example.com/sitemap-pages.xml"}; !reflect.DeepEqual(sitemaps, want) {
+		t.Errorf("sitemaps = %q, want %q", sitemaps, want)
+	}
+
+	if paths, sitemaps := parseRobots("<html><body>Not Found</body></html>"); paths != nil || sitemaps != nil {
+		t.Errorf("expected nothing from an HTML page, got %q %q", paths, sitemaps)
+	}
+}
+
+func TestParseSitemap(t *testing.T) {
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc> https://example.com/about </loc></url>
+  <url><LOC><![CDATA[https://example.com/search?q=a&b=c]]></LOC></url>
+  <url><loc>https://example.com/a?x=1&amp;y=2</loc></url>
+  <url><loc>https://example.com/trunc`
+	locs, index := parseSitemap(body)
+	want := []string{"https://example.com/about", "https://example.com/search?q=a&b=c", "https://example.com/a?x=1&y=2"}
+	if index || !reflect.DeepEqual(locs, want) {
+		t.Errorf("parseSitemap = %q, %v; want %q, false", locs, index, want)
+	}
+
+	locs, index = parseSitemap(`<sitemapindex><sitemap><loc>https://example.com/s1.xml</loc></sitemap></sitemapindex>`)
+	if !index || len(locs) != 1 {
+		t.Errorf("expected a sitemap index with one entry, got %q, %v", locs, index)
+	}
+}
+
+func TestTargetRelative(t *testing.T) {
+	tests := []struct {
+		target, raw string
+		want        string
+		ok          bool
+	}{
+		{"http://example.com", "/admin/", "admin/", true},
+		{"http://example.com/", "http://EXAMPLE.com/docs?page=2", "docs?page=2", true},
+		{"http://example.com/app", "/app/login", "login", true},
+		{"http://example.com/app/", "/application", "", false},
+		{"http://example.com", "http://other.example.com/admin", "", false},
+		{"http://example.com", "http://example.com:8080/admin", "", false},
+		{"http://example.com", "https://example.com/", "", false},
+		{"http://example.com", "//evil.test/x", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := targetRelative(tt.target, tt.raw)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("targetRelative(%q, %q) = %q, %v; want %q, %v", tt.target, tt.raw, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package scanner
 
-import "strings"
+import (
+	"net/url"
+	"strings"
+)
 
 // Severity constants for risk classification.
 const (
@@ -18,6 +21,18 @@ const (
 	ConfidenceTentative = "tentative"
 )
 
+// Finding classes separate what to investigate first from background
+// inventory.
+const (
+	ClassActionable    = "actionable"
+	ClassInformational = "informational"
+)
+
+// firmCalibrationDistance is how far, in units of the soft-404 filtering
+// boundary, a response must be from its calibration baseline before a
+// status-only finding counts as firm.
+const firmCalibrationDistance = 2.0
+
 // severityRank maps severity strings to numeric rank for comparison.
 // Higher rank = more severe.
 var severityRank = map[string]int{
@@ -43,6 +58,14 @@ func CompareSeverity(a, b string) int {
 // based on the finding characteristics. This is a pure function that does not
 // mutate any shared state.
 func AssignSeverityAndConfidence(r *Result) {
+	AssignSeverityAndConfidenceWith(r, nil)
+}
+
+// AssignSeverityAndConfidenceWith is AssignSeverityAndConfidence with a
+// status code to severity override (--severity-map). A mapped status replaces
+// the status-derived severity; findings backed by stronger evidence (secrets,
+// bypasses, method fuzzing) are only ever raised by the mapping, never lowered.
+func AssignSeverityAndConfidenceWith(r *Result, severityMap map[int]string) {
 	// Default baseline
 	r.Severity = SeverityInfo
 	r.Confidence = ConfidenceTentative
@@ -82,6 +105,33 @@ func AssignSeverityAndConfidence(r *Result) {
 		r.Confidence = ConfidenceFirm
 	}
 
+	// Readable repository metadata usually means the whole history,
+	// and whatever secrets it ever held, can be downloaded.
+	if hasReason(*r, ReasonVCSExposed) {
+		r.Severity = SeverityCritical
+		r.Confidence = ConfidenceConfirmed
+		r.Tags = appendUnique(r.Tags, "vcs-exposure")
+	}
+
+	// A parsed dependency manifest is direct evidence of what is deployed.
+	if len(r.Dependencies) > 0 {
+		r.Confidence = ConfidenceConfirmed
+		r.Tags = appendUnique(r.Tags, "dependency-manifest")
+	}
+
+	if r.Param != "" {
+		r.Tags = appendUnique(r.Tags, "param")
+	}
+
+	// A 200 on an admin panel, backup, or VCS/environment file is worth
+	// checking before anything else the wordlist turned up.
+	if r.StatusCode == 200 && sensitivePath(r.URL) {
+		if CompareSeverity(SeverityHigh, r.Severity) > 0 {
+			r.Severity = SeverityHigh
+		}
+		r.Tags = appendUnique(r.Tags, "sensitive-path")
+	}
+
 	// 401/403 are interesting but lower in isolation.
 	if (r.StatusCode == 401 || r.StatusCode == 403) && r.Severity == SeverityInfo {
 		r.Severity = SeverityLow
@@ -96,10 +146,86 @@ func AssignSeverityAndConfidence(r *Result) {
 		r.Tags = appendUnique(r.Tags, "directory")
 	}
 
+	// Login panels are worth a look; known default credentials make them actionable.
+	if r.LoginPanel != "" {
+		if r.DefaultCreds != "" && CompareSeverity(SeverityMedium, r.Severity) > 0 {
+			r.Severity = SeverityMedium
+		} else if r.Severity == SeverityInfo {
+			r.Severity = SeverityLow
+		}
+		r.Tags = appendUnique(r.Tags, "login-panel")
+		if r.DefaultCreds != "" {
+			r.Tags = appendUnique(r.Tags, "default-creds")
+		}
+	}
+
+	// Real-time endpoints carry their own auth and message handling.
+	if hasReason(*r, ReasonWebSocket) {
+		if r.Severity == SeverityInfo {
+			r.Severity = SeverityLow
+		}
+		r.Tags = appendUnique(r.Tags, "websocket")
+	}
+
+	// A JSON, XML or source response (--interesting-types) is worth
+	// reading even when the status alone says little.
+	if hasReason(*r, ReasonContentType) {
+		if r.Severity == SeverityInfo {
+			r.Severity = SeverityLow
+		}
+		r.Tags = appendUnique(r.Tags, "interesting-type")
+	}
+
+	// An injected host reflected into a cacheable page may poison the cache.
+	if r.CachePoisoningHint != "" {
+		if CompareSeverity(SeverityMedium, r.Severity) > 0 {
+			r.Severity = SeverityMedium
+		}
+		r.Tags = appendUnique(r.Tags, "cache-poisoning")
+	}
+
+	// A response far from every soft-404 baseline for its status is
+	// unlikely to be a catch-all page. Closer ones, and statuses
+	// calibration has no baseline for, stay tentative.
+	if r.Confidence == ConfidenceTentative && r.baselineDistance >= firmCalibrationDistance {
+		r.Confidence = ConfidenceFirm
+	}
+
+	// A finding that failed its confirmation re-request is never firm evidence.
+	if r.Flaky {
+		r.Confidence = ConfidenceTentative
+		r.Tags = appendUnique(r.Tags, "flaky")
+	}
+
+	// Unchanged since the last scan (--conditional-cache); the body was
+	// not downloaded, so nothing above could look at it.
+	if hasReason(*r, ReasonNotModified) {
+		r.Tags = appendUnique(r.Tags, "not-modified")
+	}
+
 	// WAF detection is informational.
 	if r.WAFDetected != "" {
 		r.Tags = appendUnique(r.Tags, "waf")
 	}
+
+	if mapped, ok := severityMap[r.StatusCode]; ok {
+		if !(r.SecretFound || r.Critical) || CompareSeverity(mapped, r.Severity) > 0 {
+			r.Severity = mapped
+		}
+	}
+
+	r.Class = Classify(r)
+}
+
+// Classify reports whether a scored finding is actionable: a secret, a
+// bypass or other critical evidence, known default credentials, or
+// anything rated medium or above. Everything else is informational.
+func Classify(r *Result) string {
+	if r.SecretFound || r.Critical || r.DefaultCreds != "" || r.BypassStrategy != "" ||
+		SeverityAtOrAbove(r.Severity, SeverityMedium) {
+		return ClassActionable
+	}
+	return ClassInformational
 }
 
 // secretTypesToSeverity maps detected secret types to the highest applicable severity.
@@ -126,6 +252,27 @@ func secretTypesToSeverity(secretTypes []string) string {
 	return highest
 }
 
+// sensitivePath reports whether a URL path has a segment naming an admin
+// area or backup (matched anywhere in the segment, e.g. wp-admin or
+// backup.zip), or is a .git or .env entry such as .env.local. A bypass
+// result's " [BYPASS:<strategy>]" marker is ignored.
+func sensitivePath(rawURL string) bool {
+	rawURL, _, _ = strings.Cut(rawURL, " [BYPASS")
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	for _, segment := range strings.Split(strings.ToLower(u.Path), "/") {
+		switch {
+		case segment == ".git", segment == ".env", strings.HasPrefix(segment, ".env."):
+			return true
+		case strings.Contains(segment, "admin"), strings.Contains(segment, "backup"):
+			return true
+		}
+	}
+	return false
+}
+
 func appendUnique(slice []string, val string) []string {
 	for _, v := range slice {
 		if v == val {
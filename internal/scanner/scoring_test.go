@@ -43,6 +43,27 @@ func TestAssignSeverityAndConfidence_BypassResult(t *testing.T) {
 	}
 }
 
+func TestAssignSeverityAndConfidence_DependencyManifest(t *testing.T) {
+	r := &Result{
+		URL:          "http://example.com/package.json",
+		StatusCode:   200,
+		Method:       "GET",
+		Critical:     true,
+		Dependencies: []string{"express@^4.17.1"},
+	}
+	AssignSeverityAndConfidence(r)
+
+	if r.Severity != SeverityHigh {
+		t.Errorf("expected high severity for dependency manifest, got %q", r.Severity)
+	}
+	if r.Confidence != ConfidenceConfirmed {
+		t.Errorf("expected confirmed confidence for dependency manifest, got %q", r.Confidence)
+	}
+	if !containsTag(r.Tags, "dependency-manifest") {
+		t.Error("expected 'dependency-manifest' tag")
+	}
+}
+
 func TestAssignSeverityAndConfidence_MethodFuzz(t *testing.T) {
 	r := &Result{
 		URL:        "http://example.com/api",
@@ -145,6 +166,30 @@ func TestAssignSeverityAndConfidence_SecretWithBypass(t *testing.T) {
 	}
 }
 
+func TestAssignSeverityAndConfidence_Class(t *testing.T) {
+	tests := []struct {
+		name   string
+		result Result
+		want   string
+	}{
+		{"plain 200", Result{URL: "http://example.com/about", StatusCode: 200, Method: "GET"}, ClassInformational},
+		{"access control", Result{URL: "http://example.com/admin", StatusCode: 403, Method: "GET"}, ClassInformational},
+		{"login panel", Result{URL: "http://example.com/login", StatusCode: 200, Method: "GET", LoginPanel: "WordPress"}, ClassInformational},
+		{"secret", Result{URL: "http://example.com/.env", StatusCode: 200, Method: "GET", SecretFound: true, SecretTypes: []string{"AWS Access Key"}}, ClassActionable},
+		{"bypass", Result{URL: "http://example.com/admin", StatusCode: 200, Method: "GET", BypassStrategy: "headers", Critical: true}, ClassActionable},
+		{"default creds", Result{URL: "http://example.com/login", StatusCode: 200, Method: "GET", LoginPanel: "Tomcat", DefaultCreds: "admin:admin"}, ClassActionable},
+		{"method fuzz", Result{URL: "http://example.com/api", StatusCode: 200, Method: "PUT"}, ClassActionable},
+	}
+
+	for _, tt := range tests {
+		r := tt.result
+		AssignSeverityAndConfidence(&r)
+		if r.Class != tt.want {
+			t.Errorf("%s: expected class %q, got %q (severity %s)", tt.name, tt.want, r.Class, r.Severity)
+		}
+	}
+}
+
 func TestSeverityAtOrAbove(t *testing.T) {
 	tests := []struct {
 		result    string
@@ -219,3 +264,118 @@ func containsTag(tags []string, target string) bool {
 	}
 	return false
 }
+
+func TestAssignSeverityAndConfidence_LoginPanel(t *testing.T) {
+	r := &Result{URL: "http://example.com/manager/html", StatusCode: 200, Method: "GET", LoginPanel: "Tomcat Manager"}
+	AssignSeverityAndConfidence(r)
+
+	if r.Severity != SeverityLow {
+		t.Errorf("expected low severity, got %s", r.Severity)
+	}
+	if !containsTag(r.Tags, "login-panel") {
+		t.Errorf("expected 'login-panel' tag, got %v", r.Tags)
+	}
+
+	r = &Result{URL: "http://example.com/manager/html", StatusCode: 200, Method: "GET", LoginPanel: "Tomcat Manager", DefaultCreds: "tomcat:tomcat"}
+	AssignSeverityAndConfidence(r)
+
+	if r.Severity != SeverityMedium {
+		t.Errorf("expected medium severity with default creds, got %s", r.Severity)
+	}
+	if !containsTag(r.Tags, "default-creds") {
+		t.Errorf("expected 'default-creds' tag, got %v", r.Tags)
+	}
+}
+
+func TestAssignSeverityAndConfidenceWith_SeverityMap(t *testing.T) {
+	severityMap := map[int]string{403: SeverityHigh, 200: SeverityInfo, 500: SeverityMedium}
+
+	tests := []struct {
+		name     string
+		result   *Result
+		expected string
+	}{
+		{"raise access control", &Result{URL: "http://example.com/admin", StatusCode: 403, Method: "GET"}, SeverityHigh},
+		{"lower directory", &Result{URL: "http://example.com/static/", StatusCode: 200, Method: "GET"}, SeverityInfo},
+		{"unscored status", &Result{URL: "http://example.com/crash", StatusCode: 500, Method: "GET"}, SeverityMedium},
+		{"unmapped status", &Result{URL: "http://example.com/old", StatusCode: 301, Method: "GET"}, SeverityLow},
+		{"secret not lowered", &Result{URL: "http://example.com/.env", StatusCode: 200, Method: "GET", SecretFound: true, SecretTypes: []string{"AWS Access Key"}}, SeverityCritical},
+	}
+
+	for _, tt := range tests {
+		AssignSeverityAndConfidenceWith(tt.result, severityMap)
+		if tt.result.Severity != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, tt.result.Severity)
+		}
+	}
+}
+
+func TestAssignSeverityAndConfidence_Rules(t *testing.T) {
+	tests := []struct {
+		name           string
+		result         Result
+		wantSeverity   string
+		wantConfidence string
+	}{
+		{"critical secret", Result{URL: "http://t/config", StatusCode: 200, Method: "GET", SecretFound: true, SecretTypes: []string{"Private Key"}}, SeverityCritical, ConfidenceConfirmed},
+		{"high secret", Result{URL: "http://t/app.js", StatusCode: 200, Method: "GET", SecretFound: true, SecretTypes: []string{"JWT Token"}}, SeverityHigh, ConfidenceConfirmed},
+		{"secret on sensitive path", Result{URL: "http://t/.env", StatusCode: 200, Method: "GET", SecretFound: true, SecretTypes: []string{"AWS Access Key"}}, SeverityCritical, ConfidenceConfirmed},
+		{"admin panel", Result{URL: "http://t/admin", StatusCode: 200, Method: "GET"}, SeverityHigh, ConfidenceTentative},
+		{"backup archive", Result{URL: "http://t/site-backup.zip", StatusCode: 200, Method: "GET"}, SeverityHigh, ConfidenceTentative},
+		{"git metadata", Result{URL: "http://t/.git/HEAD", StatusCode: 200, Method: "GET"}, SeverityHigh, ConfidenceTentative},
+		{"environment file", Result{URL: "http://t/.env.local", StatusCode: 200, Method: "GET"}, SeverityHigh, ConfidenceTentative},
+		{"forbidden admin panel", Result{URL: "http://t/admin", StatusCode: 403, Method: "GET"}, SeverityLow, ConfidenceTentative},
+		{"bypass", Result{URL: "http://t/admin [BYPASS]", StatusCode: 200, Method: "GET+BYPASS"}, SeverityHigh, ConfidenceFirm},
+		{"bypassed environment file", Result{URL: "http://t/.env [BYPASS:headers]", StatusCode: 200, Method: "GET+BYPASS"}, SeverityHigh, ConfidenceFirm},
+		{"plain 200", Result{URL: "http://t/page", StatusCode: 200, Method: "GET"}, SeverityInfo, ConfidenceTentative},
+		{"401", Result{URL: "http://t/api", StatusCode: 401, Method: "GET"}, SeverityLow, ConfidenceTentative},
+		{"403", Result{URL: "http://t/private", StatusCode: 403, Method: "GET"}, SeverityLow, ConfidenceTentative},
+		{"near soft-404 baseline", Result{URL: "http://t/page", StatusCode: 200, Method: "GET", baselineDistance: 1.2}, SeverityInfo, ConfidenceTentative},
+		{"far from soft-404 baseline", Result{URL: "http://t/page", StatusCode: 200, Method: "GET", baselineDistance: 5}, SeverityInfo, ConfidenceFirm},
+		{"far 403", Result{URL: "http://t/private", StatusCode: 403, Method: "GET", baselineDistance: 2}, SeverityLow, ConfidenceFirm},
+		{"far but flaky", Result{URL: "http://t/admin", StatusCode: 200, Method: "GET", baselineDistance: 5, Flaky: true}, SeverityHigh, ConfidenceTentative},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.result
+			AssignSeverityAndConfidence(&r)
+			if r.Severity != tt.wantSeverity || r.Confidence != tt.wantConfidence {
+				t.Errorf("got %s/%s, want %s/%s", r.Severity, r.Confidence, tt.wantSeverity, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestAssignSeverityAndConfidence_BypassSensitivePath(t *testing.T) {
+	r := Result{URL: "http://t/.git/config [BYPASS:headers]", StatusCode: 200, Method: "GET+BYPASS"}
+	AssignSeverityAndConfidence(&r)
+	if !containsTag(r.Tags, "sensitive-path") || !containsTag(r.Tags, "bypass") {
+		t.Errorf("expected sensitive-path and bypass tags, got %v", r.Tags)
+	}
+}
+
+func TestSensitivePath(t *testing.T) {
+	tests := map[string]bool{
+		"http://t/admin":                    true,
+		"http://t/wp-admin/":                true,
+		"http://t/Administrator":            true,
+		"http://t/db/backup.sql":            true,
+		"http://t/.git/config":              true,
+		"http://t/.env":                     true,
+		"http://t/.env.production":          true,
+		"http://t/admin [BYPASS]":           true,
+		"http://t/.env [BYPASS:headers]":    true,
+		"http://t/.git [BYPASS:url-encode]": true,
+		"http://t/page [BYPASS:headers]":    false,
+		"http://t/page?next=/admin":         false,
+		"http://t/.environment":             false,
+		"http://t/.github/workflows.yml":    false,
+		"http://t/":                         false,
+	}
+	for u, want := range tests {
+		if got := sensitivePath(u); got != want {
+			t.Errorf("sensitivePath(%q) = %v, want %v", u, got, want)
+		}
+	}
+}
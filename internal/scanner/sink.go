@@ -0,0 +1,7 @@
+package scanner
+
+// ResultSink receives each deduplicated result as soon as the engine
+// collects it. reporting.Sink implementations satisfy this interface.
+type ResultSink interface {
+	Write(result Result) error
+}
@@ -1,24 +1,88 @@
 package scanner
 
 import (
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/capsaicin/scanner/internal/detection"
+	"github.com/capsaicin/scanner/internal/transport"
 )
 
 type Stats struct {
-	Total     int64
-	Processed int64
-	Found     int64
-	Errors    int64
-	Secrets   int64
-	WAFHits   int64
-	StartTime time.Time
+	Total        int64
+	Processed    int64
+	Found        int64
+	Errors       int64
+	Secrets      int64
+	WAFHits      int64
+	BodyTimeouts int64
+	Flaky        int64
+	Actionable   int64
+	Suppressed   int64
+	StartTime    time.Time
 
 	currentURL string
+	recentURLs [RecentURLCapacity]string
+	recentNext int
 	urlMu      sync.RWMutex
+
+	bypassByStrategy map[string]int64
+	bypassMu         sync.Mutex
+
+	stopReason string
+	stopMu     sync.Mutex
+
+	targetErrors   map[string]string
+	targetErrorsMu sync.Mutex
+
+	secretTypes   map[string]int64
+	secretTypesMu sync.Mutex
+
+	hostErrors   map[string]int64
+	abandoned    map[string]bool
+	hostErrorsMu sync.RWMutex
+
+	wafHosts   map[string]string
+	wafHostsMu sync.RWMutex
+
+	targetHosts   map[string]transport.HostInfo
+	targetHostsMu sync.Mutex
+
+	sizeURLs     map[sizeKey]map[string]bool
+	noisySizes   map[sizeKey]int64
+	noisySizesMu sync.RWMutex
+
+	// Set once before the stats are published under --resume.
+	resumed         int64
+	wordlistChanged bool
+}
+
+// SecretTypeCount is how many findings exposed one kind of secret.
+type SecretTypeCount struct {
+	Type     string `json:"type"`
+	Severity string `json:"severity"`
+	Count    int64  `json:"count"`
+}
+
+// AutoFilteredSize is a status code and body size that
+// --exclude-length-auto found on too many paths, with the number of
+// responses it dropped.
+type AutoFilteredSize struct {
+	StatusCode int   `json:"status_code"`
+	Size       int   `json:"size"`
+	Dropped    int64 `json:"dropped"`
 }
 
+type sizeKey struct {
+	status, size int
+}
+
+// RecentURLCapacity is how many of the most recently tried URLs Stats keeps
+// for the live display.
+const RecentURLCapacity = 16
+
 func NewStats(initialTotal int64) *Stats {
 	return &Stats{
 		Total:     initialTotal,
@@ -46,6 +110,31 @@ func (s *Stats) IncrementWAFHits() {
 	atomic.AddInt64(&s.WAFHits, 1)
 }
 
+// IncrementBodyTimeouts records a request aborted by the body-read timeout.
+// Body timeouts are also counted in Errors.
+func (s *Stats) IncrementBodyTimeouts() {
+	atomic.AddInt64(&s.BodyTimeouts, 1)
+}
+
+// IncrementFlaky records a finding whose confirmation re-request disagreed.
+func (s *Stats) IncrementFlaky() {
+	atomic.AddInt64(&s.Flaky, 1)
+}
+
+// IncrementActionable counts a reported finding classed as actionable.
+func (s *Stats) IncrementActionable() {
+	atomic.AddInt64(&s.Actionable, 1)
+}
+
+// IncrementSuppressed counts an informational finding dropped by
+// --actionable-only and takes it back out of Found, which the worker
+// already counted, so --max-findings and the live counters only see
+// reported findings.
+func (s *Stats) IncrementSuppressed() {
+	atomic.AddInt64(&s.Suppressed, 1)
+	atomic.AddInt64(&s.Found, -1)
+}
+
 func (s *Stats) IncrementTotal(delta int64) {
 	atomic.AddInt64(&s.Total, delta)
 }
@@ -70,6 +159,22 @@ func (s *Stats) GetWAFHits() int64 {
 	return atomic.LoadInt64(&s.WAFHits)
 }
 
+func (s *Stats) GetBodyTimeouts() int64 {
+	return atomic.LoadInt64(&s.BodyTimeouts)
+}
+
+func (s *Stats) GetFlaky() int64 {
+	return atomic.LoadInt64(&s.Flaky)
+}
+
+func (s *Stats) GetActionable() int64 {
+	return atomic.LoadInt64(&s.Actionable)
+}
+
+func (s *Stats) GetSuppressed() int64 {
+	return atomic.LoadInt64(&s.Suppressed)
+}
+
 func (s *Stats) GetTotal() int64 {
 	return atomic.LoadInt64(&s.Total)
 }
@@ -77,6 +182,8 @@ func (s *Stats) GetTotal() int64 {
 func (s *Stats) SetCurrentURL(url string) {
 	s.urlMu.Lock()
 	s.currentURL = url
+	s.recentURLs[s.recentNext] = url
+	s.recentNext = (s.recentNext + 1) % RecentURLCapacity
 	s.urlMu.Unlock()
 }
 
@@ -85,3 +192,291 @@ func (s *Stats) GetCurrentURL() string {
 	defer s.urlMu.RUnlock()
 	return s.currentURL
 }
+
+// RecentURLs returns up to n of the most recently tried URLs, newest first.
+// n is capped at RecentURLCapacity.
+func (s *Stats) RecentURLs(n int) []string {
+	if n > RecentURLCapacity {
+		n = RecentURLCapacity
+	}
+	s.urlMu.RLock()
+	defer s.urlMu.RUnlock()
+	urls := make([]string, 0, n)
+	for i := 1; i <= n; i++ {
+		url := s.recentURLs[(s.recentNext-i+RecentURLCapacity)%RecentURLCapacity]
+		if url == "" {
+			break
+		}
+		urls = append(urls, url)
+	}
+	return urls
+}
+
+// IncrementBypass records a successful bypass for the named strategy.
+func (s *Stats) IncrementBypass(strategy string) {
+	s.bypassMu.Lock()
+	if s.bypassByStrategy == nil {
+		s.bypassByStrategy = make(map[string]int64)
+	}
+	s.bypassByStrategy[strategy]++
+	s.bypassMu.Unlock()
+}
+
+// GetBypassCounts returns a copy of the per-strategy bypass success counts.
+func (s *Stats) GetBypassCounts() map[string]int64 {
+	s.bypassMu.Lock()
+	defer s.bypassMu.Unlock()
+	counts := make(map[string]int64, len(s.bypassByStrategy))
+	for name, n := range s.bypassByStrategy {
+		counts[name] = n
+	}
+	return counts
+}
+
+// SetStopReason records why the scan stopped before exhausting its tasks.
+func (s *Stats) SetStopReason(reason string) {
+	s.stopMu.Lock()
+	s.stopReason = reason
+	s.stopMu.Unlock()
+}
+
+// GetStopReason returns why the scan stopped early, or "" if it ran to
+// completion.
+func (s *Stats) GetStopReason() string {
+	s.stopMu.Lock()
+	defer s.stopMu.Unlock()
+	return s.stopReason
+}
+
+// Resumed returns how many tasks --resume skipped because the checkpoint
+// recorded them as completed, and whether the wordlist changed since the
+// checkpoint was written.
+func (s *Stats) Resumed() (skipped int64, wordlistChanged bool) {
+	return s.resumed, s.wordlistChanged
+}
+
+// SetTargetError records why a target was skipped, e.g. a TLS certificate
+// problem found during calibration.
+func (s *Stats) SetTargetError(target, reason string) {
+	s.targetErrorsMu.Lock()
+	if s.targetErrors == nil {
+		s.targetErrors = make(map[string]string)
+	}
+	s.targetErrors[target] = reason
+	s.targetErrorsMu.Unlock()
+}
+
+// RecordHostError counts a failed request against host for
+// --host-error-budget. It reports true exactly once, when the host's
+// errors reach budget and the host is abandoned.
+func (s *Stats) RecordHostError(host string, budget int) bool {
+	s.hostErrorsMu.Lock()
+	defer s.hostErrorsMu.Unlock()
+	if s.hostErrors == nil {
+		s.hostErrors = make(map[string]int64)
+		s.abandoned = make(map[string]bool)
+	}
+	s.hostErrors[host]++
+	if s.abandoned[host] || s.hostErrors[host] < int64(budget) {
+		return false
+	}
+	s.abandoned[host] = true
+	return true
+}
+
+// HostAbandoned reports whether host exhausted its error budget.
+func (s *Stats) HostAbandoned(host string) bool {
+	s.hostErrorsMu.RLock()
+	defer s.hostErrorsMu.RUnlock()
+	return s.abandoned[host]
+}
+
+// GetAbandonedHosts returns the abandoned hosts with their error counts.
+func (s *Stats) GetAbandonedHosts() map[string]int64 {
+	s.hostErrorsMu.RLock()
+	defer s.hostErrorsMu.RUnlock()
+	hosts := make(map[string]int64, len(s.abandoned))
+	for host := range s.abandoned {
+		hosts[host] = s.hostErrors[host]
+	}
+	return hosts
+}
+
+// MarkWAFHost records that a WAF was seen on host under --waf-adaptive. It
+// reports true only the first time, so the host is throttled once.
+func (s *Stats) MarkWAFHost(host, waf string) bool {
+	s.wafHostsMu.Lock()
+	defer s.wafHostsMu.Unlock()
+	if _, ok := s.wafHosts[host]; ok {
+		return false
+	}
+	if s.wafHosts == nil {
+		s.wafHosts = make(map[string]string)
+	}
+	s.wafHosts[host] = waf
+	return true
+}
+
+// WAFHost reports whether host was throttled after a WAF was detected.
+func (s *Stats) WAFHost(host string) bool {
+	s.wafHostsMu.RLock()
+	defer s.wafHostsMu.RUnlock()
+	_, ok := s.wafHosts[host]
+	return ok
+}
+
+// GetWAFHosts returns the throttled hosts with the WAF that triggered it.
+func (s *Stats) GetWAFHosts() map[string]string {
+	s.wafHostsMu.RLock()
+	defer s.wafHostsMu.RUnlock()
+	hosts := make(map[string]string, len(s.wafHosts))
+	for host, waf := range s.wafHosts {
+		hosts[host] = waf
+	}
+	return hosts
+}
+
+// SetTargetHost records the DNS answer for a target under --resolve.
+func (s *Stats) SetTargetHost(target string, info transport.HostInfo) {
+	s.targetHostsMu.Lock()
+	if s.targetHosts == nil {
+		s.targetHosts = make(map[string]transport.HostInfo)
+	}
+	s.targetHosts[target] = info
+	s.targetHostsMu.Unlock()
+}
+
+// GetTargetHosts returns a copy of the per-target DNS answers.
+func (s *Stats) GetTargetHosts() map[string]transport.HostInfo {
+	s.targetHostsMu.Lock()
+	defer s.targetHostsMu.Unlock()
+	hosts := make(map[string]transport.HostInfo, len(s.targetHosts))
+	for target, info := range s.targetHosts {
+		hosts[target] = info
+	}
+	return hosts
+}
+
+// GetTargetErrors returns a copy of the per-target errors.
+func (s *Stats) GetTargetErrors() map[string]string {
+	s.targetErrorsMu.Lock()
+	defer s.targetErrorsMu.Unlock()
+	errs := make(map[string]string, len(s.targetErrors))
+	for target, reason := range s.targetErrors {
+		errs[target] = reason
+	}
+	return errs
+}
+
+// AddSecretTypes counts each secret type found on one finding.
+func (s *Stats) AddSecretTypes(types []string) {
+	if len(types) == 0 {
+		return
+	}
+	s.secretTypesMu.Lock()
+	if s.secretTypes == nil {
+		s.secretTypes = make(map[string]int64)
+	}
+	for _, t := range types {
+		s.secretTypes[t]++
+	}
+	s.secretTypesMu.Unlock()
+}
+
+// GetSecretTypeCounts returns the per-type secret counts with each type's
+// severity, most severe first, then by count.
+func (s *Stats) GetSecretTypeCounts() []SecretTypeCount {
+	s.secretTypesMu.Lock()
+	counts := make([]SecretTypeCount, 0, len(s.secretTypes))
+	for name, n := range s.secretTypes {
+		counts = append(counts, SecretTypeCount{
+			Type:     name,
+			Severity: string(detection.SecretSeverity(name)),
+			Count:    n,
+		})
+	}
+	s.secretTypesMu.Unlock()
+
+	sort.Slice(counts, func(i, j int) bool {
+		if d := CompareSeverity(counts[i].Severity, counts[j].Severity); d != 0 {
+			return d > 0
+		}
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Type < counts[j].Type
+	})
+	return counts
+}
+
+// RecordSize counts url under its status and body size for
+// --exclude-length-auto. It reports true once the pair has been seen on
+// more than threshold distinct URLs, from the response that crosses it on,
+// and counts each such response as dropped.
+func (s *Stats) RecordSize(status, size int, url string, threshold int) bool {
+	key := sizeKey{status, size}
+	s.noisySizesMu.Lock()
+	defer s.noisySizesMu.Unlock()
+	if _, ok := s.noisySizes[key]; ok {
+		s.noisySizes[key]++
+		return true
+	}
+	if s.sizeURLs == nil {
+		s.sizeURLs = make(map[sizeKey]map[string]bool)
+		s.noisySizes = make(map[sizeKey]int64)
+	}
+	urls := s.sizeURLs[key]
+	if urls == nil {
+		urls = make(map[string]bool)
+		s.sizeURLs[key] = urls
+	}
+	urls[url] = true
+	if len(urls) <= threshold {
+		return false
+	}
+	delete(s.sizeURLs, key)
+	s.noisySizes[key] = 1
+	return true
+}
+
+// NoisySize reports whether RecordSize found status and size to be noise.
+func (s *Stats) NoisySize(status, size int) bool {
+	s.noisySizesMu.RLock()
+	defer s.noisySizesMu.RUnlock()
+	_, ok := s.noisySizes[sizeKey{status, size}]
+	return ok
+}
+
+// retractAutoFiltered takes back a finding reported before its size was
+// recognized as noise.
+func (s *Stats) retractAutoFiltered(r Result) {
+	s.noisySizesMu.Lock()
+	s.noisySizes[sizeKey{r.StatusCode, r.Size}]++
+	s.noisySizesMu.Unlock()
+	atomic.AddInt64(&s.Found, -1)
+	if r.Class == ClassActionable {
+		atomic.AddInt64(&s.Actionable, -1)
+	}
+}
+
+// GetAutoFilteredSizes returns the sizes --exclude-length-auto filtered,
+// most dropped first.
+func (s *Stats) GetAutoFilteredSizes() []AutoFilteredSize {
+	s.noisySizesMu.RLock()
+	sizes := make([]AutoFilteredSize, 0, len(s.noisySizes))
+	for key, dropped := range s.noisySizes {
+		sizes = append(sizes, AutoFilteredSize{StatusCode: key.status, Size: key.size, Dropped: dropped})
+	}
+	s.noisySizesMu.RUnlock()
+
+	sort.Slice(sizes, func(i, j int) bool {
+		if sizes[i].Dropped != sizes[j].Dropped {
+			return sizes[i].Dropped > sizes[j].Dropped
+		}
+		if sizes[i].StatusCode != sizes[j].StatusCode {
+			return sizes[i].StatusCode < sizes[j].StatusCode
+		}
+		return sizes[i].Size < sizes[j].Size
+	})
+	return sizes
+}
@@ -1,28 +1,91 @@
 package scanner
 
+import (
+	"sync"
+
+	"github.com/capsaicin/scanner/internal/detection"
+)
+
 type Task struct {
 	TargetURL string
 	Path      string
+	Param     string // query parameter name under --params; Path is unused
+	Method    string // primary request method; empty means GET
 	Depth     int
+	Seeded    bool // path came from robots.txt or a sitemap (--robots)
+	// Backup marks a --backup-probe permutation; on the recursion
+	// channel it marks a discovered file to permute.
+	Backup bool
+	// VCS marks a --git-check probe for repository metadata.
+	VCS bool
+
+	// active counts the target's outstanding tasks under
+	// --target-concurrency; nil when targets are not gated.
+	active *sync.WaitGroup
+}
+
+// done marks the task finished in wg and in its target's active group.
+func (t Task) done(wg *sync.WaitGroup) {
+	if t.active != nil {
+		t.active.Done()
+	}
+	wg.Done()
+}
+
+// spawn registers a follow-up task for the same target. It must be called
+// before t is marked done so the target is not released early.
+func (t Task) spawn(wg *sync.WaitGroup) {
+	if t.active != nil {
+		t.active.Add(1)
+	}
+	wg.Add(1)
 }
 
 type Result struct {
-	URL         string   `json:"url"`
-	StatusCode  int      `json:"status_code"`
-	Size        int      `json:"size"`
-	WordCount   int      `json:"word_count"`
-	LineCount   int      `json:"line_count"`
-	Critical    bool     `json:"critical"`
-	Severity    string   `json:"severity"`
-	Confidence  string   `json:"confidence"`
-	Tags        []string `json:"tags,omitempty"`
-	Method      string   `json:"method"`
-	Timestamp   string   `json:"timestamp"`
-	Server      string   `json:"server,omitempty"`
-	PoweredBy   string   `json:"powered_by,omitempty"`
-	UserAgent   string   `json:"user_agent"`
-	SecretFound  bool     `json:"secret_found"`
-	SecretTypes  []string `json:"secret_types,omitempty"`
-	WAFDetected  string   `json:"waf_detected,omitempty"`
-	Technologies []string `json:"technologies,omitempty"`
+	URL                 string                  `json:"url"`
+	RequestedURL        string                  `json:"requested_url,omitempty"`
+	StatusCode          int                     `json:"status_code"`
+	Size                int                     `json:"size"`
+	WordCount           int                     `json:"word_count"`
+	LineCount           int                     `json:"line_count"`
+	Critical            bool                    `json:"critical"`
+	Severity            string                  `json:"severity"`
+	Class               string                  `json:"class,omitempty"`
+	Confidence          string                  `json:"confidence"`
+	Tags                []string                `json:"tags,omitempty"`
+	Method              string                  `json:"method"`
+	Timestamp           string                  `json:"timestamp"`
+	Server              string                  `json:"server,omitempty"`
+	PoweredBy           string                  `json:"powered_by,omitempty"`
+	UserAgent           string                  `json:"user_agent"`
+	SecretFound         bool                    `json:"secret_found"`
+	SecretTypes         []string                `json:"secret_types,omitempty"`
+	SecretValues        []string                `json:"secret_values,omitempty"`
+	SecretDetails       []detection.SecretMatch `json:"secret_details,omitempty"`
+	WAFDetected         string                  `json:"waf_detected,omitempty"`
+	Technologies        []string                `json:"technologies,omitempty"`
+	LoginPanel          string                  `json:"login_panel,omitempty"`
+	DefaultCreds        string                  `json:"default_creds,omitempty"`
+	BypassStrategy      string                  `json:"bypass_strategy,omitempty"`
+	Flaky               bool                    `json:"flaky,omitempty"`
+	Reasons             []string                `json:"reasons,omitempty"`
+	CookieIssues        []string                `json:"cookie_issues,omitempty"`
+	MixedContent        []string                `json:"mixed_content,omitempty"`
+	BodyHash            string                  `json:"body_hash,omitempty"`
+	Dependencies        []string                `json:"dependencies,omitempty"`
+	CalibrationDistance float64                 `json:"calibration_distance,omitempty"`
+	Param               string                  `json:"param,omitempty"`
+	ContentType         string                  `json:"content_type,omitempty"`
+	SniffedContentType  string                  `json:"sniffed_content_type,omitempty"`
+	Location            string                  `json:"location,omitempty"`
+	Proto               string                  `json:"proto,omitempty"` // negotiated protocol, e.g. "HTTP/2.0"
+	LatencyMs           int64                   `json:"latency_ms"`
+	FinalURL            string                  `json:"final_url,omitempty"`
+	RedirectChain       []string                `json:"redirect_chain,omitempty"`
+	CachePoisoningHint  string                  `json:"cache_poisoning_hint,omitempty"`
+	ResolvedIP          string                  `json:"resolved_ip,omitempty"`
+
+	// baselineDistance is the calibration distance used for scoring. It
+	// is kept even when CalibrationDistance is only reported under -v.
+	baselineDistance float64
 }
@@ -0,0 +1,68 @@
+package scanner
+
+import (
+	"strings"
+)
+
+// vcsProbePaths are the version-control metadata files --git-check
+// requests under each target, ahead of the wordlist.
+var vcsProbePaths = []string{".git/HEAD", ".git/config", ".svn/entries"}
+
+// vcsTasks returns the --git-check probes for target.
+func vcsTasks(target string) []Task {
+	tasks := make([]Task, len(vcsProbePaths))
+	for i, path := range vcsProbePaths {
+		tasks[i] = Task{TargetURL: target, Path: path, Method: "GET", Depth: 1, VCS: true}
+	}
+	return tasks
+}
+
+// vcsExposure reports which repository a --git-check probe exposed, "git"
+// or "svn", or "" when the body is not what the file would hold. HEAD
+// names a ref ("ref: refs/heads/main") or, when detached, a commit hash;
+// config has a [core] section; entries starts with its format number
+// (12 since Subversion 1.7) or, in pre-1.4 working copies, is XML.
+func vcsExposure(path, body string) string {
+	body = strings.TrimSpace(body)
+	switch {
+	case strings.HasSuffix(path, ".git/HEAD"):
+		if strings.HasPrefix(body, "ref:") || isCommitHash(body) {
+			return "git"
+		}
+	case strings.HasSuffix(path, ".git/config"):
+		if strings.Contains(body, "[core]") {
+			return "git"
+		}
+	case strings.HasSuffix(path, ".svn/entries"):
+		first, _, _ := strings.Cut(body, "\n")
+		if isDigits(strings.TrimSpace(first)) || strings.Contains(body, "<wc-entries") {
+			return "svn"
+		}
+	}
+	return ""
+}
+
+// isCommitHash reports whether s is a full SHA-1 or SHA-256 object name.
+func isCommitHash(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,85 @@
+package scanner
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/capsaicin/scanner/internal/config"
+)
+
+func TestVCSExposure(t *testing.T) {
+	tests := []struct {
+		path string
+		body string
+		want string
+	}{
+		{".git/HEAD", "ref: refs/heads/main\n", "git"},
+		{".git/HEAD", "0123456789abcdef0123456789abcdef01234567\n", "git"},
+		{".git/HEAD", "<html>Page not found</html>", ""},
+		{".git/config", "[core]\n\trepositoryformatversion = 0\n\tbare = false\n", "git"},
+		{".git/config", "{\"core\": true}", ""},
+		{".svn/entries", "12\n", "svn"},
+		{".svn/entries", "<?xml version=\"1.0\"?>\n<wc-entries xmlns=\"svn:\">", "svn"},
+		{".svn/entries", "<!DOCTYPE html>", ""},
+		{"app/.git/HEAD", "ref: refs/heads/dev", "git"},
+		{"admin", "ref: refs/heads/main", ""},
+	}
+	for _, tt := range tests {
+		if got := vcsExposure(tt.path, tt.body); got != tt.want {
+			t.Errorf("vcsExposure(%q, %q) = %q, want %q", tt.path, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestEngineGitCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.git/HEAD":
+			w.Write([]byte("ref: refs/heads/main\n"))
+		case "/.git/config":
+			w.Write([]byte("[core]\n\trepositoryformatversion = 0\n"))
+		case "/.svn/entries":
+			// A catch-all page that is not Subversion metadata.
+			w.Write([]byte("<html><body>Welcome</body></html>"))
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+
+	run := func(gitCheck bool) ([]Result, *Stats) {
+		cfg := config.Config{
+			Wordlist:      createWordlist(t, "admin"),
+			Threads:       2,
+			Timeout:       10,
+			MaxResponseMB: 10,
+			SafeMode:      true,
+			GitCheck:      gitCheck,
+		}
+		results, stats, err := NewEngine(cfg).Run([]string{server.URL})
+		if err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		return results, stats
+	}
+
+	if results, _ := run(false); len(results) != 0 {
+		t.Fatalf("expected no probes without --git-check, got %d results", len(results))
+	}
+
+	results, stats := run(true)
+	if stats.GetTotal() != 4 || stats.GetProcessed() != 4 {
+		t.Errorf("expected 3 probes and 1 word counted and processed, got %d/%d", stats.GetProcessed(), stats.GetTotal())
+	}
+	found := map[string]bool{}
+	for _, r := range results {
+		found[extractPath(r.URL)] = true
+		if r.Severity != SeverityCritical || r.Confidence != ConfidenceConfirmed || !containsTag(r.Tags, "vcs-exposure") || !hasReason(r, ReasonVCSExposed) {
+			t.Errorf("%s: expected a confirmed critical vcs-exposure, got %s/%s %v %v", r.URL, r.Severity, r.Confidence, r.Tags, r.Reasons)
+		}
+	}
+	if len(results) != 2 || !found["/.git/HEAD"] || !found["/.git/config"] {
+		t.Errorf("expected /.git/HEAD and /.git/config only, got %v", found)
+	}
+}
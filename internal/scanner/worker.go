@@ -1,9 +1,15 @@
 package scanner
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
 	"math/rand"
 	"net/http"
+	neturl "net/url"
 	"strings"
 	"sync"
 	"time"
@@ -13,6 +19,8 @@ import (
 	"github.com/capsaicin/scanner/internal/transport"
 )
 
+// userAgents is the rotation pool used unless --user-agent or
+// --user-agents-file is given.
 var userAgents = []string{
 	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
 	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
@@ -20,8 +28,50 @@ var userAgents = []string{
 	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
 }
 
-func getRandomUserAgent(rng *rand.Rand) string {
-	return userAgents[rng.Intn(len(userAgents))]
+// userAgentPool returns the User-Agents a scan rotates through: the fixed
+// --user-agent alone, the --user-agents-file entries, or the built-in list.
+func userAgentPool(cfg config.Config) []string {
+	if cfg.UserAgent != "" {
+		return []string{cfg.UserAgent}
+	}
+	if len(cfg.UserAgents) > 0 {
+		return cfg.UserAgents
+	}
+	return userAgents
+}
+
+// getRandomUserAgent picks a User-Agent from pool. A single-entry pool, as
+// with --user-agent, always yields that entry.
+func getRandomUserAgent(rng *rand.Rand, pool []string) string {
+	if len(pool) == 1 {
+		return pool[0]
+	}
+	return pool[rng.Intn(len(pool))]
+}
+
+// calibrationHeaders adds the scan's User-Agent to the custom headers sent
+// with calibration probes, so baselines see the same client as the scan. A
+// rotating pool contributes its first entry; a -H User-Agent still wins.
+func calibrationHeaders(cfg config.Config) map[string]string {
+	headers := make(map[string]string, len(cfg.CustomHeaders)+1)
+	headers["User-Agent"] = userAgentPool(cfg)[0]
+	for key, value := range cfg.CustomHeaders {
+		if strings.EqualFold(key, "User-Agent") {
+			delete(headers, "User-Agent")
+		}
+		headers[key] = value
+	}
+	return headers
+}
+
+// requestDelay returns a random duration between minMs and maxMs
+// milliseconds, inclusive.
+func requestDelay(rng *rand.Rand, minMs, maxMs int) time.Duration {
+	ms := minMs
+	if maxMs > minMs {
+		ms += rng.Intn(maxMs - minMs + 1)
+	}
+	return time.Duration(ms) * time.Millisecond
 }
 
 func worker(
@@ -29,10 +79,12 @@ func worker(
 	tasks <-chan Task,
 	results chan<- Result,
 	newTasks chan<- Task,
-	cfg config.Config,
+	scanCfg config.Config,
 	client *transport.Client,
 	stats *Stats,
 	calCache *detection.CalibrationCache,
+	checkpoint *Checkpoint,
+	validators *ValidatorCache,
 	done chan<- struct{},
 	taskWg *sync.WaitGroup,
 	rng *rand.Rand,
@@ -44,16 +96,47 @@ func worker(
 
 	consecutiveErrors := 0
 	maxConsecutiveErrors := 5
+	agents := userAgentPool(scanCfg)
+
+	// Validated at startup; a parse error here means no overrides.
+	severityMap, _ := config.SeverityMapping(scanCfg.SeverityMap)
+	matchCodes, _ := config.StatusCodeSet("-mc", scanCfg.MatchCodes)
+	filterCodes, _ := config.StatusCodeSet("-fc", scanCfg.FilterCodes)
+	filterSizes, _ := config.ParseRanges("-fs", scanCfg.FilterSizes)
+	filterWords, _ := config.ParseRanges("-fw", scanCfg.FilterWords)
+	recursion := newRecursionRules(scanCfg)
+	interestingTypes := make([]string, len(scanCfg.InterestingTypes))
+	for i, pattern := range scanCfg.InterestingTypes {
+		interestingTypes[i] = strings.ToLower(pattern)
+	}
 
 	for task := range tasks {
 		select {
 		case <-ctx.Done():
-			taskWg.Done()
+			task.done(taskWg)
 			continue
 		default:
 		}
 
-		url := strings.TrimSuffix(task.TargetURL, "/") + "/" + strings.TrimPrefix(task.Path, "/")
+		// --targets-json headers apply to every request for the target,
+		// bypass attempts and confirmations included.
+		cfg := scanCfg.ForTarget(task.TargetURL)
+
+		// Hosts that used up --host-error-budget are skipped outright.
+		host := ""
+		if cfg.HostErrorBudget > 0 {
+			host = hostOf(task.TargetURL)
+			if stats.HostAbandoned(host) {
+				stats.IncrementTotal(-1)
+				task.done(taskWg)
+				continue
+			}
+		}
+
+		url := joinURL(task.TargetURL, task.Path)
+		if task.Param != "" {
+			url = paramURL(task.TargetURL, task.Param)
+		}
 
 		// Track the current URL for live display.
 		stats.SetCurrentURL(url)
@@ -66,12 +149,40 @@ func worker(
 			}
 		}
 
-		userAgent := getRandomUserAgent(rng)
-		result, bodyContent, resp, err := makeRequest(ctx, url, "GET", userAgent, cfg, client)
+		// --delay-min/--delay-max break up evenly spaced requests. The
+		// rate limiter in the client still applies after the delay.
+		if cfg.DelayMax > 0 {
+			select {
+			case <-ctx.Done():
+				task.done(taskWg)
+				continue
+			case <-time.After(requestDelay(rng, cfg.DelayMin, cfg.DelayMax)):
+			}
+		}
+
+		method := task.Method
+		if method == "" {
+			method = "GET"
+		}
+		userAgent := getRandomUserAgent(rng, agents)
+		var conditional Validators
+		if validators != nil && method == "GET" && !task.VCS {
+			conditional = validators.Lookup(url)
+		}
+		result, bodyContent, resp, err := makeRequest(ctx, url, method, userAgent, requestBody(cfg), conditional, cfg, client)
 		stats.IncrementProcessed()
 
 		if err != nil {
 			stats.IncrementErrors()
+			if errors.Is(err, transport.ErrBodyTimeout) {
+				stats.IncrementBodyTimeouts()
+			}
+			if host != "" && stats.RecordHostError(host, cfg.HostErrorBudget) && eventCh != nil {
+				select {
+				case eventCh <- ScanEvent{Type: EventHostAbandoned, URL: host}:
+				case <-ctx.Done():
+				}
+			}
 			consecutiveErrors++
 
 			if consecutiveErrors >= maxConsecutiveErrors {
@@ -81,34 +192,142 @@ func worker(
 				}
 				consecutiveErrors = 0
 			}
-			taskWg.Done()
+			task.done(taskWg)
 			continue
 		}
 
 		consecutiveErrors = 0
+		if task.Seeded {
+			result.Tags = appendUnique(result.Tags, "from-robots")
+		}
+		if task.Backup {
+			result.Tags = appendUnique(result.Tags, "backup-probe")
+		}
+
+		// The path answered; --resume need not request it again. Failed
+		// requests stay unrecorded so a resumed scan retries them.
+		if checkpoint != nil {
+			checkpoint.Record(task)
+		}
+
+		// A 304 to a conditional request (--conditional-cache) means the
+		// finding from the last scan is unchanged; there is no body to check.
+		if result.StatusCode == http.StatusNotModified && !conditional.Empty() {
+			validators.Record(url, conditional.merge(resp.Header))
+			addReason(result, ReasonNotModified)
+			stats.IncrementFound()
+			AssignSeverityAndConfidenceWith(result, severityMap)
+			results <- *result
+			task.done(taskWg)
+			continue
+		}
+
+		// --git-check probes are reported only when the body is the
+		// metadata file itself, so catch-all pages need no calibration.
+		if task.VCS {
+			if result.StatusCode == 200 && vcsExposure(task.Path, bodyContent) != "" {
+				result.Critical = true
+				addReason(result, ReasonVCSExposed)
+				stats.IncrementFound()
+				AssignSeverityAndConfidenceWith(result, severityMap)
+				results <- *result
+			}
+			task.done(taskWg)
+			continue
+		}
+
+		if cfg.WAFAdaptive {
+			throttleOnWAF(ctx, task.TargetURL, result, bodyContent, cfg.WAFRate, client, stats, eventCh)
+		}
+
+		// --interesting-types responses skip the noise filters below that
+		// calibration and size statistics drive; -fs and -fw still apply.
+		wantedType := interestingType(result, interestingTypes)
+		if wantedType {
+			addReason(result, ReasonContentType)
+		}
 
 		signatures, _ := calCache.Get(task.TargetURL)
-		if detection.MatchesSignature(result.StatusCode, result.Size, result.WordCount, result.LineCount, signatures) {
-			taskWg.Done()
+		if !wantedType && detection.MatchesSignature(result.StatusCode, result.Size, result.WordCount, result.LineCount, signatures) {
+			task.done(taskWg)
+			continue
+		}
+		// Templated "not found" pages can differ in size and shape from
+		// the baseline yet share nearly all of its text.
+		if !wantedType && cfg.Soft404Similarity > 0 && detection.MatchesBodySimilarity(result.StatusCode, detection.SimHash([]byte(bodyContent)), cfg.Soft404Similarity, signatures) {
+			task.done(taskWg)
+			continue
+		}
+		if filteredOut(result, filterSizes, filterWords) {
+			task.done(taskWg)
+			continue
+		}
+		// A body served on too many paths is noise calibration missed,
+		// such as a WAF challenge page; earlier matches are dropped
+		// once the scan ends.
+		if !wantedType && cfg.ExcludeLengthAuto > 0 && result.Size > 0 && stats.RecordSize(result.StatusCode, result.Size, url, cfg.ExcludeLengthAuto) {
+			task.done(taskWg)
+			continue
+		}
+		if d, ok := detection.CalibrationDistance(result.StatusCode, result.Size, result.WordCount, result.LineCount, signatures); ok {
+			result.baselineDistance = d
+			if cfg.Verbose {
+				result.CalibrationDistance = d
+			}
+		}
+
+		// A WebSocket handshake handler refuses a plain GET, often with a
+		// 400 or 426 that isInteresting would otherwise drop.
+		if detection.WebSocketUpgrade(result.StatusCode, resp.Header, bodyContent) {
+			addReason(result, ReasonWebSocket)
+		}
+
+		if task.Param != "" {
+			// Parameter mining: any response that differs from the
+			// baseline means the endpoint reads this parameter.
+			result.Param = task.Param
+			addReason(result, ReasonParamAccepted)
+			stats.IncrementFound()
+			AssignSeverityAndConfidenceWith(result, severityMap)
+			results <- *result
+			task.done(taskWg)
+			continue
+		}
+
+		if cfg.OnlySecrets {
+			// Credential hunting: skip method fuzzing, bypasses, and
+			// fingerprinting, and drop anything without a secret.
+			if isInteresting(result, matchCodes, filterCodes) && len(bodyContent) > 0 {
+				if detectSecrets(result, bodyContent, cfg) {
+					addReason(result, ReasonStatusInteresting)
+					stats.IncrementSecrets()
+					stats.IncrementFound()
+					AssignSeverityAndConfidenceWith(result, severityMap)
+					results <- *result
+				}
+			}
+			if isInteresting(result, matchCodes, filterCodes) {
+				enqueueRecursion(ctx, task, url, result, cfg, recursion, newTasks, taskWg)
+				enqueueBackupProbe(ctx, task, result, cfg, newTasks, taskWg)
+			}
+			task.done(taskWg)
 			continue
 		}
 
 		if result.StatusCode == 405 && !cfg.SafeMode {
-			alternativeMethods := []string{"POST", "PUT", "DELETE", "PATCH"}
-			for _, method := range alternativeMethods {
+			for _, method := range alternativeMethods(cfg.RequestMethods()) {
 				select {
 				case <-ctx.Done():
 					goto done405
 				default:
 				}
-				methodResult, methodBody, methodResp, err := makeRequest(ctx, url, method, userAgent, cfg, client)
+				methodResult, methodBody, methodResp, err := makeRequest(ctx, url, method, userAgent, nil, Validators{}, cfg, client)
 				if err == nil && (methodResult.StatusCode == 200 || methodResult.StatusCode == 201 || methodResult.StatusCode == 204) {
 					methodResult.Method = method
 					methodResult.Critical = true
+					addReason(methodResult, ReasonMethodFuzz)
 
-					if secrets := detection.DetectSecrets(methodBody); len(secrets) > 0 {
-						methodResult.SecretFound = true
-						methodResult.SecretTypes = secrets
+					if detectSecrets(methodResult, methodBody, cfg) {
 						stats.IncrementSecrets()
 					}
 
@@ -117,7 +336,7 @@ func worker(
 					}
 
 					stats.IncrementFound()
-					AssignSeverityAndConfidence(methodResult)
+					AssignSeverityAndConfidenceWith(methodResult, severityMap)
 					results <- *methodResult
 					break
 				}
@@ -125,15 +344,44 @@ func worker(
 		}
 	done405:
 
-		if isInteresting(result) {
+		if cfg.ConfirmFindings && isInteresting(result, matchCodes, filterCodes) && !confirmFinding(ctx, url, userAgent, result, cfg, client) {
+			stats.IncrementFlaky()
+			if !cfg.Verbose {
+				task.done(taskWg)
+				continue
+			}
+			result.Flaky = true
+		}
+
+		if isInteresting(result, matchCodes, filterCodes) {
 			stats.IncrementFound()
+			addReason(result, ReasonStatusInteresting)
+			if result.WAFDetected != "" {
+				addReason(result, ReasonHeaderTrigger)
+			}
 
 			if result.StatusCode == 200 && len(bodyContent) > 0 {
-				if secrets := detection.DetectSecrets(bodyContent); len(secrets) > 0 {
-					result.SecretFound = true
-					result.SecretTypes = secrets
+				if detectSecrets(result, bodyContent, cfg) {
 					stats.IncrementSecrets()
 				}
+
+				if deps := detection.ParseManifest(url, bodyContent); len(deps) > 0 {
+					result.Dependencies = deps
+					result.Critical = true
+					addReason(result, ReasonManifestExposed)
+				}
+
+				if panel := detection.IdentifyLoginPanel(bodyContent, url); panel != "" {
+					result.LoginPanel = panel
+					addReason(result, ReasonBodyMatch)
+					if cfg.DefaultCreds {
+						result.DefaultCreds = detection.DefaultCredentials(panel)
+					}
+				}
+
+				if cfg.InsecureDowngrade && strings.Contains(detection.EffectiveContentType(result.ContentType, result.SniffedContentType), "text/html") {
+					result.MixedContent = detection.MixedContent(url, bodyContent)
+				}
 			}
 
 			// Detect technologies from response headers, cookies, and body.
@@ -141,87 +389,261 @@ func worker(
 				if techs := detection.DetectTechNames(resp, bodyContent); len(techs) > 0 {
 					result.Technologies = techs
 				}
+				result.CookieIssues = detection.CookieIssues(resp)
+			}
+
+			if cfg.CacheProbe && !cfg.SafeMode && result.StatusCode == 200 && resp != nil && detection.Cacheable(resp.Header) {
+				if hint := probeCachePoisoning(ctx, url, userAgent, cfg, client, rng); hint != "" {
+					result.CachePoisoningHint = hint
+					addReason(result, ReasonCachePoisoning)
+				}
 			}
 
-			if !cfg.SafeMode && (result.StatusCode == 403 || result.StatusCode == 401) {
+			wafThrottled := cfg.WAFAdaptive && stats.WAFHost(hostOf(task.TargetURL))
+			if !cfg.SafeMode && !wafThrottled && (result.StatusCode == 403 || result.StatusCode == 401) {
 				bypassResult := attemptBypassStrategies(ctx, url, userAgent, cfg, client)
 				if bypassResult != nil && bypassResult.Result != nil {
 					bypassResult.Result.Critical = true
+					addReason(bypassResult.Result, ReasonBypassSuccess)
+					stats.IncrementBypass(bypassResult.Strategy)
 
-					if secrets := detection.DetectSecrets(bypassResult.Body); len(secrets) > 0 {
-						bypassResult.Result.SecretFound = true
-						bypassResult.Result.SecretTypes = secrets
+					if detectSecrets(bypassResult.Result, bypassResult.Body, cfg) {
 						stats.IncrementSecrets()
 					}
 
-					AssignSeverityAndConfidence(bypassResult.Result)
+					AssignSeverityAndConfidenceWith(bypassResult.Result, severityMap)
 					results <- *bypassResult.Result
 				}
 			}
 
-			if cfg.MaxDepth > 0 && task.Depth < cfg.MaxDepth && isDirectory(result) {
-				dirPath := extractPath(url)
-				taskWg.Add(1)
-				select {
-				case newTasks <- Task{
-					TargetURL: task.TargetURL,
-					Path:      dirPath,
-					Depth:     task.Depth + 1,
-				}:
-				case <-ctx.Done():
-					taskWg.Done()
-				}
+			enqueueRecursion(ctx, task, url, result, cfg, recursion, newTasks, taskWg)
+			enqueueBackupProbe(ctx, task, result, cfg, newTasks, taskWg)
+
+			if validators != nil && method == "GET" && resp != nil {
+				validators.Record(url, validatorsOf(resp.Header))
 			}
 
-			AssignSeverityAndConfidence(result)
+			AssignSeverityAndConfidenceWith(result, severityMap)
 			results <- *result
 		}
 
-		taskWg.Done()
+		task.done(taskWg)
+	}
+}
+
+// fuzzMethods are tried, in order, on a path that answered 405.
+var fuzzMethods = []string{"POST", "PUT", "DELETE", "PATCH"}
+
+// alternativeMethods returns the fuzzMethods not already requested as
+// primary methods; those get their own task.
+func alternativeMethods(primary []string) []string {
+	var alternatives []string
+	for _, method := range fuzzMethods {
+		requested := false
+		for _, p := range primary {
+			if p == method {
+				requested = true
+				break
+			}
+		}
+		if !requested {
+			alternatives = append(alternatives, method)
+		}
+	}
+	return alternatives
+}
+
+// throttleOnWAF slows the target's host down to --waf-rate the first time a
+// WAF shows up in one of its responses, and reports the adjustment.
+func throttleOnWAF(ctx context.Context, target string, result *Result, body string, rps int, client *transport.Client, stats *Stats, eventCh chan<- ScanEvent) {
+	waf := detectedWAF(result, body)
+	host := hostOf(target)
+	if waf == "" || !stats.MarkWAFHost(host, waf) {
+		return
+	}
+	client.ThrottleHost(host, rps)
+	if eventCh != nil {
+		select {
+		case eventCh <- ScanEvent{Type: EventWAFThrottled, URL: host, Result: &Result{URL: result.URL, WAFDetected: waf}}:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// detectedWAF names the WAF behind a response: a header or cookie signature,
+// or a vendor block page on a typical block status. Generic block pages
+// ("Access Denied") are ignored since ordinary error pages match them too.
+func detectedWAF(result *Result, body string) string {
+	if result.WAFDetected != "" {
+		return result.WAFDetected
+	}
+	switch result.StatusCode {
+	case 403, 406, 429, 503:
+		if waf := detection.DetectWAFFromBody(body, result.StatusCode); waf != "Generic WAF" {
+			return waf
+		}
+	}
+	return ""
+}
+
+// detectSecrets scans body for secrets and records them on result. Raw
+// matched values are only copied when --show-secrets is set. Reports
+// whether any secret was found.
+func detectSecrets(result *Result, body string, cfg config.Config) bool {
+	matches := detection.DetectSecretsDetailed(body)
+	if len(matches) == 0 {
+		return false
+	}
+
+	result.SecretFound = true
+	result.SecretTypes = make([]string, 0, len(matches))
+	result.SecretDetails = matches
+	for _, m := range matches {
+		result.SecretTypes = append(result.SecretTypes, m.Name)
+		if cfg.ShowSecrets {
+			result.SecretValues = append(result.SecretValues, m.Name+": "+m.Raw)
+		}
+	}
+	addReason(result, ReasonSecretFound)
+	return true
+}
+
+// enqueueRecursion queues a directory result for scanning one level deeper
+// when recursion is enabled, the depth limit allows it and the recursion
+// rules accept the directory. Backup permutations are files, so they
+// never recurse.
+func enqueueRecursion(ctx context.Context, task Task, url string, result *Result, cfg config.Config, rules recursionRules, newTasks chan<- Task, taskWg *sync.WaitGroup) {
+	if cfg.MaxDepth <= 0 || task.Depth >= cfg.MaxDepth || task.Backup || !isDirectory(result) {
+		return
+	}
+	dir := directoryPath(url)
+	if !rules.allows(result, dir) {
+		return
+	}
+	task.spawn(taskWg)
+	select {
+	case newTasks <- Task{
+		TargetURL: task.TargetURL,
+		Path:      dir,
+		Depth:     task.Depth + 1,
+		active:    task.active,
+	}:
+	case <-ctx.Done():
+		task.done(taskWg)
+	}
+}
+
+// requestBody is the --data/--data-file body sent with primary requests, or
+// nil when none was given.
+func requestBody(cfg config.Config) []byte {
+	if cfg.Data == "" {
+		return nil
 	}
+	return []byte(cfg.Data)
 }
 
-func makeRequest(ctx context.Context, url, method, userAgent string, cfg config.Config, client *transport.Client) (*Result, string, *http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+// makeRequest sends one request and builds its Result. A non-nil reqBody
+// is sent with --content-type and replayed on retries.
+// conditional, when not empty, makes the request conditional on the
+// resource having changed since an earlier scan (--conditional-cache).
+func makeRequest(ctx context.Context, url, method, userAgent string, reqBody []byte, conditional Validators, cfg config.Config, client *transport.Client) (*Result, string, *http.Response, error) {
+	// Evasion only changes what goes on the wire; results keep the logical URL.
+	wireURL := applyEvasionURL(url, cfg.Evasion)
+	var bodyReader io.Reader
+	if reqBody != nil {
+		bodyReader = bytes.NewReader(reqBody)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, wireURL, bodyReader)
 	if err != nil {
 		return nil, "", nil, err
 	}
 
 	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept-Encoding", transport.AcceptEncoding)
+	if reqBody != nil && cfg.ContentType != "" {
+		req.Header.Set("Content-Type", cfg.ContentType)
+	}
 
 	for key, value := range cfg.CustomHeaders {
 		req.Header.Set(key, value)
 	}
+	setConditional(req.Header, conditional)
 
+	// Latency covers everything the client does for the request,
+	// including rate-limit waits and retries.
+	start := time.Now()
 	resp, body, err := client.DoContext(ctx, req, cfg.RateLimit)
 	if err != nil {
 		return nil, "", nil, err
 	}
+	latency := time.Since(start)
 
 	bodyContent := string(body)
 	server := resp.Header.Get("Server")
 	poweredBy := resp.Header.Get("X-Powered-By")
+	contentType := resp.Header.Get("Content-Type")
 
 	result := &Result{
-		URL:        url,
-		StatusCode: resp.StatusCode,
-		Size:       len(body),
-		WordCount:  len(strings.Fields(bodyContent)),
-		LineCount:  strings.Count(bodyContent, "\n") + 1,
-		Method:     method,
-		Timestamp:  time.Now().Format(time.RFC3339),
-		Server:     server,
-		PoweredBy:  poweredBy,
-		UserAgent:  userAgent,
-	}
-
-	if wafName := detection.DetectWAF(resp); wafName != "" {
+		URL:                url,
+		StatusCode:         resp.StatusCode,
+		Size:               len(body),
+		BodyHash:           bodyHash(body),
+		WordCount:          len(strings.Fields(bodyContent)),
+		LineCount:          strings.Count(bodyContent, "\n") + 1,
+		Method:             method,
+		Timestamp:          time.Now().Format(time.RFC3339),
+		Server:             server,
+		PoweredBy:          poweredBy,
+		UserAgent:          userAgent,
+		ContentType:        contentType,
+		SniffedContentType: detection.SniffContentType(contentType, body),
+		Location:           resp.Header.Get("Location"),
+		Proto:              resp.Proto,
+		LatencyMs:          latency.Milliseconds(),
+	}
+
+	if wireURL != url {
+		result.RequestedURL = wireURL
+	}
+
+	// Only set under --follow-redirects; otherwise the chain is the
+	// request alone.
+	if chain := transport.RedirectChain(resp); len(chain) > 1 {
+		result.RedirectChain = chain
+		result.FinalURL = chain[len(chain)-1]
+	}
+
+	if wafName := detection.DetectWAF(resp, resp.StatusCode, bodyContent); wafName != "" {
 		result.WAFDetected = wafName
 	}
 
 	return result, bodyContent, resp, nil
 }
 
+// confirmFinding re-requests an interesting URL once and reports whether the
+// status code matched the original response. Transport errors count as a
+// mismatch. The re-request goes through the same client, so rate limits apply.
+func confirmFinding(ctx context.Context, url, userAgent string, original *Result, cfg config.Config, client *transport.Client) bool {
+	confirm, _, _, err := makeRequest(ctx, url, original.Method, userAgent, requestBody(cfg), Validators{}, cfg, client)
+	if err != nil {
+		return false
+	}
+	return confirm.StatusCode == original.StatusCode
+}
+
+// bodyHash returns a short SHA-256 fingerprint of a response body, used to
+// spot the same page served under many URLs.
+func bodyHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:8])
+}
+
+// filteredOut reports whether -fs or -fw drops result: its body size or
+// word count falls in one of the given ranges.
+func filteredOut(result *Result, sizes, words config.Ranges) bool {
+	return sizes.Contains(result.Size) || words.Contains(result.WordCount)
+}
+
 func isDirectory(result *Result) bool {
 	if result.StatusCode == 301 || result.StatusCode == 302 || result.StatusCode == 403 {
 		return true
@@ -232,14 +654,41 @@ func isDirectory(result *Result) bool {
 	return false
 }
 
-func isInteresting(result *Result) bool {
+// isInteresting decides whether a status code is reported. A code in
+// matchCodes (-mc) always is; with matchCodes set nothing else is. Without
+// it, filterCodes (-fc) reports every code but those. With neither, 2xx,
+// 3xx, 401 and 403 are interesting, as is any WebSocket endpoint.
+func isInteresting(result *Result, matchCodes, filterCodes map[int]bool) bool {
+	switch {
+	case matchCodes[result.StatusCode]:
+		return true
+	case len(matchCodes) > 0, filterCodes[result.StatusCode]:
+		return false
+	case len(filterCodes) > 0:
+		return true
+	}
 	if result.StatusCode >= 200 && result.StatusCode < 400 {
 		return true
 	}
 	if result.StatusCode == 401 || result.StatusCode == 403 {
 		return true
 	}
-	return false
+	return hasReason(*result, ReasonWebSocket)
+}
+
+// directoryPath is extractPath without the query string or fragment, for
+// recursing into a result whose wordlist entry carried parameters.
+func directoryPath(url string) string {
+	path, _ := splitWordSuffix(extractPath(url))
+	return path
+}
+
+// hostOf returns the host[:port] of a target URL.
+func hostOf(target string) string {
+	if u, err := neturl.Parse(target); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return target
 }
 
 func extractPath(url string) string {
@@ -2,6 +2,7 @@ package transport
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math"
@@ -10,17 +11,35 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/time/rate"
 )
 
+// ErrBodyTimeout is returned when a response body is not fully read within
+// the configured body timeout.
+var ErrBodyTimeout = errors.New("response body read timed out")
+
+// ErrOutOfScope is returned for a request to a host --allow/--deny
+// excludes.
+var ErrOutOfScope = errors.New("host is out of scope")
+
 type Client struct {
 	httpClient     *http.Client
+	transport      *http.Transport
+	bodyTimeout    time.Duration
+	latency        *latencyTracker
+	adaptive       *adaptiveTimeout
 	limiters       map[string]*rate.Limiter
+	throttled      map[string]*rate.Limiter
+	global         *rate.Limiter
+	hostSlots      *hostSlots
+	scope          *Scope
 	limitersMu     sync.RWMutex
 	retryAttempts  int
 	maxBodyBytes   int64
+	maxHeaders     int
 	circuitBreaker *CircuitBreaker
 	rng            *rand.Rand
 	rngMu          sync.Mutex
@@ -34,29 +53,93 @@ type CircuitBreaker struct {
 	resetTimeout  time.Duration
 }
 
-func NewClient(timeout int, rateLimit int, retryAttempts int, maxBodyMB int) *Client {
-	return &Client{
+// Option customizes a Client at construction time.
+type Option func(*Client)
+
+// WithUnixSocket routes every connection to the unix domain socket at path,
+// regardless of the request URL's host. The URL host is still sent as the
+// Host header.
+func WithUnixSocket(path string) Option {
+	return func(c *Client) {
+		dialer := &net.Dialer{Timeout: 5 * time.Second}
+		c.transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", path)
+		}
+	}
+}
+
+// WithConnectTo redirects connections for "host:port" keys to the mapped
+// "addr:port" values while leaving the request URL (and therefore the Host
+// header and TLS SNI) untouched. Unmapped addresses are dialed normally.
+func WithConnectTo(mappings map[string]string) Option {
+	return func(c *Client) {
+		if len(mappings) == 0 {
+			return
+		}
+		next := c.transport.DialContext
+		c.transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if target, ok := mappings[addr]; ok {
+				addr = target
+			}
+			return next(ctx, network, addr)
+		}
+	}
+}
+
+// WithBodyTimeout aborts body reads that take longer than d after the
+// response headers arrive, independently of the overall request timeout.
+// Zero disables the limit.
+func WithBodyTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.bodyTimeout = d
+	}
+}
+
+// WithSeed seeds the client's retry jitter so backoff delays repeat from
+// run to run (--seed).
+func WithSeed(seed int64) Option {
+	return func(c *Client) {
+		c.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// WithGlobalRateLimit caps the requests the client sends across all hosts
+// at rps per second, on top of any per-host limit passed to Do.
+func WithGlobalRateLimit(rps int) Option {
+	return func(c *Client) {
+		c.global = rate.NewLimiter(rate.Limit(rps), 1)
+	}
+}
+
+func NewClient(timeout int, rateLimit int, retryAttempts int, maxBodyMB int, opts ...Option) *Client {
+	transport := &http.Transport{
+		MaxIdleConns:           100,
+		MaxIdleConnsPerHost:    50,
+		IdleConnTimeout:        30 * time.Second,
+		TLSHandshakeTimeout:    5 * time.Second,
+		ResponseHeaderTimeout:  time.Duration(timeout) * time.Second,
+		MaxResponseHeaderBytes: DefaultMaxHeaderBytes,
+		ExpectContinueTimeout:  1 * time.Second,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+	}
+
+	c := &Client{
 		httpClient: &http.Client{
-			Timeout: time.Duration(timeout) * time.Second,
-			Transport: &http.Transport{
-				MaxIdleConns:          100,
-				MaxIdleConnsPerHost:   50,
-				IdleConnTimeout:       30 * time.Second,
-				TLSHandshakeTimeout:   5 * time.Second,
-				ResponseHeaderTimeout: time.Duration(timeout) * time.Second,
-				ExpectContinueTimeout: 1 * time.Second,
-				DialContext: (&net.Dialer{
-					Timeout:   5 * time.Second,
-					KeepAlive: 30 * time.Second,
-				}).DialContext,
-			},
+			Timeout:   time.Duration(timeout) * time.Second,
+			Transport: transport,
 			CheckRedirect: func(req *http.Request, via []*http.Request) error {
 				return http.ErrUseLastResponse
 			},
 		},
+		transport:     transport,
 		limiters:      make(map[string]*rate.Limiter),
+		throttled:     make(map[string]*rate.Limiter),
 		retryAttempts: retryAttempts,
 		maxBodyBytes:  int64(maxBodyMB) * 1024 * 1024,
+		maxHeaders:    DefaultMaxHeaders,
 		circuitBreaker: &CircuitBreaker{
 			failureCounts: make(map[string]int),
 			lastFailure:   make(map[string]time.Time),
@@ -65,17 +148,36 @@ func NewClient(timeout int, rateLimit int, retryAttempts int, maxBodyMB int) *Cl
 		},
 		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
-}
 
-func (c *Client) getRateLimiter(host string, rateLimit int) *rate.Limiter {
-	if rateLimit <= 0 {
-		return nil
+	for _, opt := range opts {
+		opt(c)
 	}
 
+	return c
+}
+
+// ThrottleHost caps requests to host at rps per second from now on,
+// regardless of the rate limit passed to Do. A cap looser than the
+// caller's own rate limit has no effect.
+func (c *Client) ThrottleHost(host string, rps int) {
+	c.limitersMu.Lock()
+	c.throttled[host] = rate.NewLimiter(rate.Limit(rps), 1)
+	c.limitersMu.Unlock()
+}
+
+func (c *Client) getRateLimiter(host string, rateLimit int) *rate.Limiter {
 	c.limitersMu.RLock()
+	throttled := c.throttled[host]
 	limiter, exists := c.limiters[host]
 	c.limitersMu.RUnlock()
 
+	if throttled != nil && (rateLimit <= 0 || throttled.Limit() < rate.Limit(rateLimit)) {
+		return throttled
+	}
+	if rateLimit <= 0 {
+		return nil
+	}
+
 	if exists {
 		return limiter
 	}
@@ -104,6 +206,41 @@ func (c *Client) jitter(attempt int) time.Duration {
 	return d
 }
 
+// dnsBackoffBase is the first retry delay after a temporary DNS failure.
+// Resolver hiccups clear quickly, so DNS retries back off from a much
+// shorter base than target failures.
+const dnsBackoffBase = 100 * time.Millisecond
+
+// dnsBackoff returns a jittered delay of up to dnsBackoffBase * 2^attempt,
+// capped at 5s.
+func (c *Client) dnsBackoff(attempt int) time.Duration {
+	base := dnsBackoffBase << uint(attempt)
+	if base <= 0 || base > 5*time.Second {
+		base = 5 * time.Second
+	}
+	c.rngMu.Lock()
+	d := base/2 + time.Duration(c.rng.Int63n(int64(base/2)))
+	c.rngMu.Unlock()
+	return d
+}
+
+// temporaryDNSError reports whether err is a transient resolver failure
+// (timeout or SERVFAIL) as opposed to a definitive answer such as NXDOMAIN.
+func temporaryDNSError(err error) bool {
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) || dnsErr.IsNotFound {
+		return false
+	}
+	return dnsErr.IsTemporary || dnsErr.IsTimeout
+}
+
+// permanentDNSError reports whether the resolver answered that the host
+// does not exist.
+func permanentDNSError(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
 func (c *Client) Do(req *http.Request, rateLimit int) (*http.Response, []byte, error) {
 	return c.DoContext(req.Context(), req, rateLimit)
 }
@@ -116,6 +253,10 @@ func (c *Client) DoContext(ctx context.Context, req *http.Request, rateLimit int
 
 	host := parsedURL.Host
 
+	if !c.scope.Allows(host) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrOutOfScope, host)
+	}
+
 	if c.circuitBreaker.isOpen(host) {
 		return nil, nil, fmt.Errorf("circuit breaker open for host: %s", host)
 	}
@@ -126,6 +267,18 @@ func (c *Client) DoContext(ctx context.Context, req *http.Request, rateLimit int
 			return nil, nil, fmt.Errorf("rate limiter cancelled: %w", err)
 		}
 	}
+	if c.global != nil {
+		if err := c.global.Wait(ctx); err != nil {
+			return nil, nil, fmt.Errorf("global rate limiter cancelled: %w", err)
+		}
+	}
+	if c.hostSlots != nil {
+		release, err := c.hostSlots.acquire(ctx, host)
+		if err != nil {
+			return nil, nil, fmt.Errorf("host concurrency wait cancelled: %w", err)
+		}
+		defer release()
+	}
 
 	req = req.WithContext(ctx)
 
@@ -135,6 +288,9 @@ func (c *Client) DoContext(ctx context.Context, req *http.Request, rateLimit int
 	for attempt := 0; attempt <= c.retryAttempts; attempt++ {
 		if attempt > 0 {
 			backoff := c.jitter(attempt - 1)
+			if temporaryDNSError(err) {
+				backoff = c.dnsBackoff(attempt - 1)
+			}
 			select {
 			case <-ctx.Done():
 				return nil, nil, ctx.Err()
@@ -148,18 +304,39 @@ func (c *Client) DoContext(ctx context.Context, req *http.Request, rateLimit int
 		default:
 		}
 
-		resp, err = c.httpClient.Do(req)
-		if err != nil {
+		// The previous attempt consumed the request body; rewind it.
+		if attempt > 0 && req.GetBody != nil {
+			rewound, gerr := req.GetBody()
+			if gerr != nil {
+				return nil, nil, gerr
+			}
+			req.Body = rewound
+		}
+
+		resp, body, err = c.roundTrip(req, host)
+
+		if errors.Is(err, ErrBodyTimeout) {
+			// A trickling body is unlikely to speed up on retry.
+			c.circuitBreaker.recordFailure(host)
+			return nil, nil, err
+		}
+		if errors.Is(err, ErrReadOnly) {
+			// Refused before anything was sent; the host is not at fault.
+			return nil, nil, err
+		}
+		if permanentDNSError(err) {
+			// NXDOMAIN will not change on retry.
+			c.circuitBreaker.recordFailure(host)
+			return nil, nil, err
+		}
+		if temporaryDNSError(err) {
+			// A flaky resolver says nothing about the target, so it
+			// does not count towards the circuit breaker.
 			if attempt == c.retryAttempts {
-				c.circuitBreaker.recordFailure(host)
 				return nil, nil, err
 			}
 			continue
 		}
-
-		body, err = c.readBody(resp.Body)
-		resp.Body.Close()
-
 		if err != nil {
 			if attempt == c.retryAttempts {
 				c.circuitBreaker.recordFailure(host)
@@ -183,9 +360,55 @@ func (c *Client) DoContext(ctx context.Context, req *http.Request, rateLimit int
 	return nil, nil, fmt.Errorf("request failed after %d attempts", c.retryAttempts+1)
 }
 
+// roundTrip sends one attempt and reads the body, applying the host's
+// adaptive timeout and recording the latency when enabled.
+func (c *Client) roundTrip(req *http.Request, host string) (*http.Response, []byte, error) {
+	if timeout := c.requestTimeout(host); timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp.Header = limitHeaders(resp.Header, c.maxHeaders)
+
+	body, err := c.readBody(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	body = decodeBody(resp, body, c.maxBodyBytes)
+
+	if c.latency != nil {
+		c.latency.record(host, time.Since(start))
+	}
+	return resp, body, nil
+}
+
 func (c *Client) readBody(body io.ReadCloser) ([]byte, error) {
 	limitedReader := io.LimitReader(body, c.maxBodyBytes)
-	return io.ReadAll(limitedReader)
+	if c.bodyTimeout <= 0 {
+		return io.ReadAll(limitedReader)
+	}
+
+	// Reads aren't context-aware, so closing the body is what unblocks a
+	// stalled read once the deadline passes.
+	var timedOut int32
+	timer := time.AfterFunc(c.bodyTimeout, func() {
+		atomic.StoreInt32(&timedOut, 1)
+		body.Close()
+	})
+	data, err := io.ReadAll(limitedReader)
+	timer.Stop()
+
+	if err != nil && atomic.LoadInt32(&timedOut) == 1 {
+		return nil, fmt.Errorf("%w after %s", ErrBodyTimeout, c.bodyTimeout)
+	}
+	return data, err
 }
 
 func (cb *CircuitBreaker) isOpen(host string) bool {
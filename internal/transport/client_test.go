@@ -2,9 +2,14 @@ package transport
 
 import (
 	"context"
+	"errors"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -46,6 +51,46 @@ func TestClientRetry(t *testing.T) {
 	}
 }
 
+func TestClientRetry_ReplaysBody(t *testing.T) {
+	const payload = `{"user":"admin","pass":"x"}`
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(data))
+		n := len(bodies)
+		mu.Unlock()
+		if n < 3 {
+			w.WriteHeader(500)
+			return
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client := NewClient(10, 0, 3, 10)
+	req, _ := http.NewRequest("POST", server.URL, strings.NewReader(payload))
+	resp, _, err := client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(bodies))
+	}
+	for i, body := range bodies {
+		if body != payload {
+			t.Errorf("attempt %d: expected body %q, got %q", i+1, payload, body)
+		}
+	}
+}
+
 func TestClientRetry_AllFail(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(500)
@@ -131,6 +176,66 @@ func TestRateLimiting_Disabled(t *testing.T) {
 	}
 }
 
+func TestRateLimiting_Global(t *testing.T) {
+	var requestCount int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requestCount, 1)
+		w.WriteHeader(200)
+	})
+	server1 := httptest.NewServer(handler)
+	defer server1.Close()
+	server2 := httptest.NewServer(handler)
+	defer server2.Close()
+
+	client := NewClient(10, 0, 0, 10, WithGlobalRateLimit(10))
+
+	const perHost = 10
+	var wg sync.WaitGroup
+	start := time.Now()
+	for _, target := range []string{server1.URL, server2.URL} {
+		for i := 0; i < perHost; i++ {
+			wg.Add(1)
+			go func(target string) {
+				defer wg.Done()
+				req, _ := http.NewRequest("GET", target, nil)
+				client.Do(req, 0)
+			}(target)
+		}
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	total := atomic.LoadInt32(&requestCount)
+	if total != 2*perHost {
+		t.Fatalf("expected %d requests, got %d", 2*perHost, total)
+	}
+	// 20 requests at 10 req/s with a burst of 1 need at least 1.9s,
+	// however they are split across hosts.
+	if rps := float64(total) / elapsed.Seconds(); rps > 11 {
+		t.Errorf("global limit of 10 req/s exceeded: %d requests in %v (%.1f req/s)", total, elapsed, rps)
+	}
+}
+
+func TestRateLimiting_GlobalCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client := NewClient(10, 0, 0, 10, WithGlobalRateLimit(1))
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, _, err := client.Do(req, 0); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ = http.NewRequest("GET", server.URL, nil)
+	if _, _, err := client.DoContext(ctx, req, 0); err == nil {
+		t.Error("expected a cancelled context to abort the wait for the global limiter")
+	}
+}
+
 func TestCircuitBreaker(t *testing.T) {
 	client := NewClient(10, 0, 1, 10)
 
@@ -336,6 +441,29 @@ func TestRateLimiter_MultipleHosts(t *testing.T) {
 	}
 }
 
+func TestRateLimiter_ThrottleHost(t *testing.T) {
+	client := NewClient(10, 5, 0, 10)
+
+	if client.getRateLimiter("waf.com", 0) != nil {
+		t.Fatal("expected no limiter before throttling")
+	}
+
+	client.ThrottleHost("waf.com", 2)
+
+	if l := client.getRateLimiter("waf.com", 0); l == nil || l.Limit() != 2 {
+		t.Errorf("expected a 2 req/s limiter for the throttled host, got %v", l)
+	}
+	if l := client.getRateLimiter("waf.com", 10); l == nil || l.Limit() != 2 {
+		t.Errorf("expected the throttle to override a looser rate limit, got %v", l)
+	}
+	if l := client.getRateLimiter("waf.com", 1); l == nil || l.Limit() != 1 {
+		t.Errorf("expected a stricter rate limit to win, got %v", l)
+	}
+	if client.getRateLimiter("other.com", 0) != nil {
+		t.Error("expected other hosts to stay unthrottled")
+	}
+}
+
 func TestRateLimiter_Concurrent(t *testing.T) {
 	client := NewClient(10, 10, 0, 10)
 
@@ -411,3 +539,256 @@ func TestCircuitBreaker_Concurrent(t *testing.T) {
 
 	_ = context.Background()
 }
+
+func TestClient_WithConnectTo(t *testing.T) {
+	var gotHost string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	backend := strings.TrimPrefix(server.URL, "http://")
+	client := NewClient(10, 0, 0, 10, WithConnectTo(map[string]string{
+		"app.internal:80": backend,
+	}))
+
+	req, _ := http.NewRequest("GET", "http://app.internal/", nil)
+	resp, _, err := client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+	if gotHost != "app.internal" {
+		t.Errorf("expected Host header app.internal, got %q", gotHost)
+	}
+}
+
+func TestClient_WithUnixSocket(t *testing.T) {
+	sockPath := filepath.Join(t.TempDir(), "app.sock")
+	listener, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("via socket " + r.Host))
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	client := NewClient(10, 0, 0, 10, WithUnixSocket(sockPath))
+
+	req, _ := http.NewRequest("GET", "http://socket.local/", nil)
+	_, body, err := client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != "via socket socket.local" {
+		t.Errorf("unexpected body: %s", body)
+	}
+}
+
+func TestClient_BodyTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+		w.Write([]byte("start"))
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(10, 0, 2, 10, WithBodyTimeout(100*time.Millisecond))
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	start := time.Now()
+	_, _, err := client.Do(req, 0)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrBodyTimeout) {
+		t.Fatalf("expected ErrBodyTimeout, got %v", err)
+	}
+	// Body timeouts are not retried.
+	if elapsed > 2*time.Second {
+		t.Errorf("expected body timeout to abort quickly, took %s", elapsed)
+	}
+}
+
+func TestClient_BodyTimeout_FastBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("complete"))
+	}))
+	defer server.Close()
+
+	client := NewClient(10, 0, 0, 10, WithBodyTimeout(time.Second))
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	_, body, err := client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(body) != "complete" {
+		t.Errorf("unexpected body: %s", body)
+	}
+}
+
+// DNS answers served by fakeResolver.
+const (
+	dnsAnswer    = iota // A record for 127.0.0.1
+	dnsServFail         // temporary failure
+	dnsNXDomain         // host does not exist
+	dnsAnswerDoc        // A record for 192.0.2.10 and PTR svc.test for any address
+)
+
+// fakeResolver starts a UDP DNS server on loopback that replies to every
+// query with *mode, and returns a resolver that sends all lookups to it.
+func fakeResolver(t *testing.T, mode *atomic.Int32) *net.Resolver {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n < 12 {
+				continue
+			}
+			// Header + question only; drop the EDNS additional record.
+			end := 12
+			for end < n && buf[end] != 0 {
+				end += int(buf[end]) + 1
+			}
+			end += 5
+			if end > n {
+				continue
+			}
+			qtype := int(buf[end-4])<<8 | int(buf[end-3])
+
+			resp := append([]byte(nil), buf[:end]...)
+			resp[2] = 0x80 | buf[2]&0x01 // QR, copy RD
+			resp[3] = 0x80               // RA, NOERROR
+			resp[6], resp[7] = 0, 0
+			resp[8], resp[9], resp[10], resp[11] = 0, 0, 0, 0
+			switch mode.Load() {
+			case dnsServFail:
+				resp[3] |= 2
+			case dnsNXDomain:
+				resp[3] |= 3
+			case dnsAnswerDoc:
+				switch qtype {
+				case 1:
+					resp[7] = 1
+					resp = append(resp, 0xc0, 0x0c, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 192, 0, 2, 10)
+				case 12:
+					resp[7] = 1
+					resp = append(resp, 0xc0, 0x0c, 0, 12, 0, 1, 0, 0, 0, 60, 0, 10, 3, 's', 'v', 'c', 4, 't', 'e', 's', 't', 0)
+				}
+			default:
+				if qtype == 1 {
+					resp[7] = 1
+					resp = append(resp, 0xc0, 0x0c, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 127, 0, 0, 1)
+				}
+			}
+			conn.WriteTo(resp, addr)
+		}
+	}()
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "udp", conn.LocalAddr().String())
+		},
+	}
+}
+
+func TestClient_DNSFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	// A trailing dot skips resolv.conf search domains.
+	host := "svc.test.:" + port
+
+	tests := []struct {
+		name         string
+		answers      []int32 // DNS answer per HTTP attempt; the last one repeats
+		wantErr      bool
+		wantNotFound bool
+		wantAttempts int
+		wantFailures int
+	}{
+		{"temporary then success", []int32{dnsServFail, dnsAnswer}, false, false, 2, 0},
+		{"temporary exhausts retries", []int32{dnsServFail}, true, false, 3, 0},
+		{"temporary then permanent", []int32{dnsServFail, dnsNXDomain}, true, true, 2, 1},
+		{"permanent fails fast", []int32{dnsNXDomain}, true, true, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mode atomic.Int32
+			resolver := fakeResolver(t, &mode)
+
+			client := NewClient(10, 0, 2, 10)
+			dialer := &net.Dialer{Timeout: 5 * time.Second, Resolver: resolver}
+			var attempts int
+			client.transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+				answer := tt.answers[len(tt.answers)-1]
+				if attempts < len(tt.answers) {
+					answer = tt.answers[attempts]
+				}
+				attempts++
+				mode.Store(answer)
+				return dialer.DialContext(ctx, network, addr)
+			}
+
+			req, _ := http.NewRequest("GET", "http://"+host+"/", nil)
+			_, body, err := client.Do(req, 0)
+
+			if tt.wantErr && err == nil {
+				t.Fatal("expected an error")
+			}
+			if !tt.wantErr && (err != nil || string(body) != "ok") {
+				t.Fatalf("expected success, got body %q, err %v", body, err)
+			}
+			if tt.wantErr {
+				var dnsErr *net.DNSError
+				if !errors.As(err, &dnsErr) {
+					t.Fatalf("expected a DNS error, got %v", err)
+				}
+				if dnsErr.IsNotFound != tt.wantNotFound {
+					t.Errorf("expected IsNotFound=%v, got %v", tt.wantNotFound, dnsErr.IsNotFound)
+				}
+			}
+			if attempts != tt.wantAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.wantAttempts, attempts)
+			}
+			if got := client.circuitBreaker.failureCounts[host]; got != tt.wantFailures {
+				t.Errorf("expected %d circuit breaker failures, got %d", tt.wantFailures, got)
+			}
+		})
+	}
+}
+
+func TestClient_WithSeed(t *testing.T) {
+	a := NewClient(10, 0, 0, 10, WithSeed(42))
+	b := NewClient(10, 0, 0, 10, WithSeed(42))
+	for attempt := 0; attempt < 5; attempt++ {
+		if ja, jb := a.jitter(attempt), b.jitter(attempt); ja != jb {
+			t.Fatalf("attempt %d: expected equal jitter for the same seed, got %v and %v", attempt, ja, jb)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package transport
+
+import (
+	"context"
+	"sync"
+)
+
+// WithHostConcurrency caps the requests in flight to any single host at n,
+// however many workers the caller runs. A request holds its host's slot
+// from after the rate limiter until its body has been read, retries
+// included.
+func WithHostConcurrency(n int) Option {
+	return func(c *Client) {
+		c.hostSlots = &hostSlots{size: n, slots: make(map[string]chan struct{})}
+	}
+}
+
+// hostSlots is a counting semaphore per host.
+type hostSlots struct {
+	mu    sync.Mutex
+	size  int
+	slots map[string]chan struct{}
+}
+
+// acquire blocks until host has a free slot or ctx is done. The returned
+// func releases the slot.
+func (h *hostSlots) acquire(ctx context.Context, host string) (func(), error) {
+	h.mu.Lock()
+	slots, ok := h.slots[host]
+	if !ok {
+		slots = make(chan struct{}, h.size)
+		h.slots[host] = slots
+	}
+	h.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
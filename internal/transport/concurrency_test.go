@@ -0,0 +1,88 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// concurrencyCounter records the most requests a handler served at once.
+type concurrencyCounter struct {
+	current, max int32
+}
+
+func (c *concurrencyCounter) handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&c.current, 1)
+		for {
+			max := atomic.LoadInt32(&c.max)
+			if n <= max || atomic.CompareAndSwapInt32(&c.max, max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&c.current, -1)
+		w.WriteHeader(200)
+	})
+}
+
+func TestClient_WithHostConcurrency(t *testing.T) {
+	var counters [2]concurrencyCounter
+	server1 := httptest.NewServer(counters[0].handler())
+	defer server1.Close()
+	server2 := httptest.NewServer(counters[1].handler())
+	defer server2.Close()
+
+	client := NewClient(10, 0, 0, 10, WithHostConcurrency(3))
+
+	var wg sync.WaitGroup
+	for _, target := range []string{server1.URL, server2.URL} {
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(target string) {
+				defer wg.Done()
+				req, _ := http.NewRequest("GET", target, nil)
+				if _, _, err := client.Do(req, 0); err != nil {
+					t.Errorf("request failed: %v", err)
+				}
+			}(target)
+		}
+	}
+	wg.Wait()
+
+	for i := range counters {
+		if max := atomic.LoadInt32(&counters[i].max); max > 3 {
+			t.Errorf("host %d saw %d concurrent requests, want at most 3", i+1, max)
+		} else if max < 2 {
+			t.Errorf("host %d saw at most %d concurrent requests; the cap should still allow 3", i+1, max)
+		}
+	}
+}
+
+func TestClient_WithHostConcurrency_Cancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(10, 0, 0, 10, WithHostConcurrency(1))
+	go func() {
+		req, _ := http.NewRequest("GET", server.URL, nil)
+		client.Do(req, 0)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, _, err := client.DoContext(ctx, req, 0); err == nil {
+		t.Error("expected the wait for a busy host's slot to end with the context")
+	}
+}
@@ -0,0 +1,15 @@
+package transport
+
+import "net/http/cookiejar"
+
+// WithCookieJar gives the client a cookie jar, so cookies a host sets are
+// sent back on later requests to it, calibration probes included. The jar
+// is safe for concurrent use and shared by every worker. Cookies set on
+// the request itself (-H Cookie, --cookies) are sent alongside.
+func WithCookieJar() Option {
+	return func(c *Client) {
+		// cookiejar.New never fails without options.
+		jar, _ := cookiejar.New(nil)
+		c.httpClient.Jar = jar
+	}
+}
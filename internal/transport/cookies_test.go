@@ -0,0 +1,68 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestClient_WithCookieJar(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", Path: "/"})
+			return
+		}
+		mu.Lock()
+		seen = append(seen, r.Header.Get("Cookie"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	get := func(client *Client, path, cookie string) {
+		req, _ := http.NewRequest("GET", server.URL+path, nil)
+		if cookie != "" {
+			req.Header.Set("Cookie", cookie)
+		}
+		// Errorf, not Fatalf: get also runs on other goroutines.
+		if _, _, err := client.Do(req, 0); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	plain := NewClient(10, 0, 0, 10)
+	get(plain, "/login", "")
+	get(plain, "/admin", "")
+	if seen[0] != "" {
+		t.Errorf("expected no cookie without a jar, got %q", seen[0])
+	}
+
+	seen = nil
+	jarred := NewClient(10, 0, 0, 10, WithCookieJar())
+	get(jarred, "/login", "")
+	get(jarred, "/admin", "theme=dark")
+	if seen[0] != "theme=dark; session=abc123" {
+		t.Errorf("expected the static cookie and the session cookie, got %q", seen[0])
+	}
+
+	// Workers share the jar.
+	seen = nil
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			get(jarred, "/login", "")
+			get(jarred, "/admin", "")
+		}()
+	}
+	wg.Wait()
+	for _, cookie := range seen {
+		if cookie != "session=abc123" {
+			t.Errorf("expected the session cookie on every concurrent request, got %q", cookie)
+			break
+		}
+	}
+}
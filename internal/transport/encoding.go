@@ -0,0 +1,69 @@
+package transport
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// AcceptEncoding is sent with scan requests. net/http only decodes gzip,
+// and only when it added Accept-Encoding itself, so the client decodes
+// every encoding listed here.
+const AcceptEncoding = "gzip, deflate, br"
+
+// decodeBody returns body decoded according to resp's Content-Encoding,
+// reading at most limit decoded bytes. On success Content-Encoding and
+// Content-Length are removed and resp.Uncompressed is set, as net/http
+// does for gzip. A body in an unknown encoding, or one that does not
+// decode at all, is returned as is; a truncated stream yields what was
+// decoded before the cut.
+func decodeBody(resp *http.Response, body []byte, limit int64) []byte {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" || len(body) == 0 {
+		return body
+	}
+
+	var r io.Reader
+	var err error
+	switch encoding {
+	case "gzip", "x-gzip":
+		r, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		// RFC 9110 deflate is zlib-wrapped, but some servers send a raw
+		// deflate stream.
+		if zlibHeader(body) {
+			r, err = zlib.NewReader(bytes.NewReader(body))
+		} else {
+			r = flate.NewReader(bytes.NewReader(body))
+		}
+	case "br":
+		r = brotli.NewReader(bytes.NewReader(body))
+	default:
+		return body
+	}
+	if err != nil {
+		return body
+	}
+
+	decoded, err := io.ReadAll(io.LimitReader(r, limit))
+	if err != nil && len(decoded) == 0 {
+		return body
+	}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return decoded
+}
+
+// zlibHeader reports whether b starts with a zlib header (RFC 1950):
+// compression method 8 and a check value divisible by 31.
+func zlibHeader(b []byte) bool {
+	return len(b) >= 2 && b[0]&0x0f == 8 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
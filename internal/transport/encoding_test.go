@@ -0,0 +1,96 @@
+package transport
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func compress(t *testing.T, encoding string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	case "br":
+		w = brotli.NewWriter(&buf)
+	}
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestClient_DecodesCompressedBodies(t *testing.T) {
+	page := []byte(strings.Repeat("<p>compressed page</p>", 50))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding := strings.TrimPrefix(r.URL.Path, "/")
+		body := page
+		switch encoding {
+		case "identity":
+		case "corrupt":
+			encoding = "gzip"
+			body = []byte("not gzip at all")
+		case "zstd":
+			body = []byte("opaque")
+		default:
+			body = compress(t, encoding, page)
+		}
+		if encoding == "raw-deflate" {
+			encoding = "deflate"
+		}
+		w.Header().Set("Content-Encoding", encoding)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := NewClient(10, 0, 0, 10)
+	for _, encoding := range []string{"gzip", "deflate", "raw-deflate", "br", "identity"} {
+		t.Run(encoding, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", server.URL+"/"+encoding, nil)
+			req.Header.Set("Accept-Encoding", AcceptEncoding)
+			resp, body, err := client.Do(req, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(body, page) {
+				t.Errorf("expected the decoded page (%d bytes), got %d bytes", len(page), len(body))
+			}
+			if encoding != "identity" && (resp.Header.Get("Content-Encoding") != "" || !resp.Uncompressed) {
+				t.Errorf("expected Content-Encoding removed and Uncompressed set, got %q %v", resp.Header.Get("Content-Encoding"), resp.Uncompressed)
+			}
+		})
+	}
+
+	for path, want := range map[string]string{"corrupt": "not gzip at all", "zstd": "opaque"} {
+		req, _ := http.NewRequest("GET", server.URL+"/"+path, nil)
+		req.Header.Set("Accept-Encoding", AcceptEncoding)
+		resp, body, err := client.Do(req, 0)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", path, err)
+		}
+		if string(body) != want || resp.Header.Get("Content-Encoding") == "" {
+			t.Errorf("%s: expected the raw body with its Content-Encoding kept, got %q", path, body)
+		}
+	}
+}
+
+func TestDecodeBody_Limit(t *testing.T) {
+	bomb := compress(t, "gzip", make([]byte, 1<<20))
+	resp := &http.Response{Header: http.Header{"Content-Encoding": {"gzip"}}}
+	if got := decodeBody(resp, bomb, 1024); len(got) != 1024 {
+		t.Errorf("expected decoding capped at 1024 bytes, got %d", len(got))
+	}
+}
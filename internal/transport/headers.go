@@ -0,0 +1,63 @@
+package transport
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Response header limits applied unless overridden with WithHeaderLimits.
+// A hostile target can otherwise send header blocks far larger than the
+// capped body; net/http's own default allows 1 MiB.
+const (
+	DefaultMaxHeaderBytes = 256 << 10
+	DefaultMaxHeaders     = 200
+)
+
+// WithHeaderLimits caps the total size of a response header block (the
+// connection fails with an error when exceeded) and the number of header
+// field lines kept on the returned response. Zero leaves a limit at its
+// default.
+func WithHeaderLimits(maxBytes int64, maxCount int) Option {
+	return func(c *Client) {
+		if maxBytes > 0 {
+			c.transport.MaxResponseHeaderBytes = maxBytes
+		}
+		if maxCount > 0 {
+			c.maxHeaders = maxCount
+		}
+	}
+}
+
+// limitHeaders returns h trimmed to at most max field lines. Keys are kept
+// in sorted order so truncation is deterministic; a repeated header (e.g.
+// Set-Cookie) counts once per value.
+func limitHeaders(h http.Header, max int) http.Header {
+	total := 0
+	for _, values := range h {
+		total += len(values)
+	}
+	if total <= max {
+		return h
+	}
+
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	limited := make(http.Header, max)
+	remaining := max
+	for _, k := range keys {
+		if remaining == 0 {
+			break
+		}
+		values := h[k]
+		if len(values) > remaining {
+			values = values[:remaining]
+		}
+		limited[k] = values
+		remaining -= len(values)
+	}
+	return limited
+}
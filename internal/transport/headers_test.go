@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHeaderCountLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 5000; i++ {
+			w.Header().Add(fmt.Sprintf("X-Flood-%d", i), "x")
+			w.Header().Add("Set-Cookie", fmt.Sprintf("c%d=v", i))
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client := NewClient(10, 0, 0, 10, WithHeaderLimits(1<<20, 100))
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, _, err := client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := 0
+	for _, values := range resp.Header {
+		lines += len(values)
+	}
+	if lines != 100 {
+		t.Errorf("expected 100 header lines kept, got %d", lines)
+	}
+}
+
+func TestHeaderSizeLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 5000; i++ {
+			w.Header().Add(fmt.Sprintf("X-Flood-%d", i), strings.Repeat("x", 100))
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client := NewClient(10, 0, 0, 10)
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, _, err := client.Do(req, 0); err == nil {
+		t.Errorf("expected an error for a header block over %d bytes", DefaultMaxHeaderBytes)
+	}
+}
+
+func TestLimitHeaders(t *testing.T) {
+	h := http.Header{
+		"A": {"1", "2", "3"},
+		"B": {"1"},
+		"C": {"1", "2"},
+	}
+
+	tests := []struct {
+		max  int
+		want http.Header
+	}{
+		{10, h},
+		{4, http.Header{"A": {"1", "2", "3"}, "B": {"1"}}},
+		{2, http.Header{"A": {"1", "2"}}},
+	}
+
+	for _, tt := range tests {
+		got := limitHeaders(h, tt.max)
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("max %d: expected %v, got %v", tt.max, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package transport
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// latencyWindowSize is how many recent samples are kept per host.
+	latencyWindowSize = 100
+	// latencyMinSamples is how many samples a host needs before its
+	// adaptive timeout replaces the fixed one.
+	latencyMinSamples = 10
+)
+
+// latencyTracker keeps a rolling window of response latencies per host,
+// plus the timeout seeded for hosts whose window is still filling.
+type latencyTracker struct {
+	mu      sync.Mutex
+	windows map[string]*latencyWindow
+	seeded  map[string]time.Duration
+}
+
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+func newLatencyTracker() *latencyTracker {
+	return &latencyTracker{windows: make(map[string]*latencyWindow), seeded: make(map[string]time.Duration)}
+}
+
+// seed records an initial timeout for host, keeping the larger one when
+// several targets share the host.
+func (t *latencyTracker) seed(host string, timeout time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if timeout > t.seeded[host] {
+		t.seeded[host] = timeout
+	}
+}
+
+// seededTimeout returns the timeout seeded for host, if any.
+func (t *latencyTracker) seededTimeout(host string) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timeout, ok := t.seeded[host]
+	return timeout, ok
+}
+
+// record adds a latency sample for host, evicting the oldest once the
+// window is full.
+func (t *latencyTracker) record(host string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	w, ok := t.windows[host]
+	if !ok {
+		w = &latencyWindow{samples: make([]time.Duration, 0, latencyWindowSize)}
+		t.windows[host] = w
+	}
+	if len(w.samples) < latencyWindowSize {
+		w.samples = append(w.samples, d)
+		return
+	}
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % latencyWindowSize
+}
+
+// percentile returns the p-th percentile (0-100) of host's recent
+// latencies, or false if fewer than latencyMinSamples were recorded.
+func (t *latencyTracker) percentile(host string, p float64) (time.Duration, bool) {
+	t.mu.Lock()
+	w, ok := t.windows[host]
+	if !ok || len(w.samples) < latencyMinSamples {
+		t.mu.Unlock()
+		return 0, false
+	}
+	sorted := append([]time.Duration(nil), w.samples...)
+	t.mu.Unlock()
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := int(float64(len(sorted)-1) * p / 100)
+	return sorted[idx], true
+}
+
+// adaptiveTimeout derives a per-host request deadline from observed latency.
+type adaptiveTimeout struct {
+	multiplier float64
+	floor      time.Duration
+	ceiling    time.Duration
+}
+
+// WithAdaptiveTimeout bounds each request by the host's p95 latency times
+// multiplier, clamped to [floor, ceiling]. Until a host has enough samples
+// the timeout given to SeedAdaptiveTimeout applies, or else only the
+// client's fixed timeout.
+func WithAdaptiveTimeout(multiplier float64, floor, ceiling time.Duration) Option {
+	return func(c *Client) {
+		c.latency = newLatencyTracker()
+		c.adaptive = &adaptiveTimeout{multiplier: multiplier, floor: floor, ceiling: ceiling}
+	}
+}
+
+// SeedAdaptiveTimeout sets the timeout for host until enough latency
+// samples have been recorded, e.g. from a calibration baseline. It is
+// clamped like the p95-derived timeout and ignored without
+// WithAdaptiveTimeout.
+func (c *Client) SeedAdaptiveTimeout(host string, timeout time.Duration) {
+	if c.adaptive == nil {
+		return
+	}
+	c.latency.seed(host, timeout)
+}
+
+// requestTimeout returns the adaptive deadline for host, or 0 when the fixed
+// timeout should be used.
+func (c *Client) requestTimeout(host string) time.Duration {
+	if c.adaptive == nil {
+		return 0
+	}
+	var timeout time.Duration
+	if p95, ok := c.latency.percentile(host, 95); ok {
+		timeout = time.Duration(float64(p95) * c.adaptive.multiplier)
+	} else if seeded, ok := c.latency.seededTimeout(host); ok {
+		timeout = seeded
+	} else {
+		return 0
+	}
+
+	if timeout < c.adaptive.floor {
+		timeout = c.adaptive.floor
+	}
+	if c.adaptive.ceiling > 0 && timeout > c.adaptive.ceiling {
+		timeout = c.adaptive.ceiling
+	}
+	return timeout
+}
@@ -0,0 +1,127 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLatencyTrackerPercentile(t *testing.T) {
+	tracker := newLatencyTracker()
+
+	for i := 0; i < latencyMinSamples-1; i++ {
+		tracker.record("a", time.Millisecond)
+	}
+	if _, ok := tracker.percentile("a", 95); ok {
+		t.Error("expected no percentile before the minimum sample count")
+	}
+
+	for i := 1; i <= 100; i++ {
+		tracker.record("b", time.Duration(i)*time.Millisecond)
+	}
+	p95, ok := tracker.percentile("b", 95)
+	if !ok {
+		t.Fatal("expected a percentile")
+	}
+	if p95 < 94*time.Millisecond || p95 > 96*time.Millisecond {
+		t.Errorf("expected p95 around 95ms, got %s", p95)
+	}
+
+	// The window rolls: fresh fast samples push out the slow ones.
+	for i := 0; i < latencyWindowSize; i++ {
+		tracker.record("b", time.Millisecond)
+	}
+	if p95, _ := tracker.percentile("b", 95); p95 != time.Millisecond {
+		t.Errorf("expected p95 of 1ms after the window rolled, got %s", p95)
+	}
+}
+
+func TestRequestTimeoutClamp(t *testing.T) {
+	c := NewClient(10, 0, 0, 10, WithAdaptiveTimeout(3, time.Second, 5*time.Second))
+
+	if got := c.requestTimeout("new-host"); got != 0 {
+		t.Errorf("expected fixed timeout for a host without samples, got %s", got)
+	}
+
+	for i := 0; i < latencyMinSamples; i++ {
+		c.latency.record("fast", 10*time.Millisecond)
+		c.latency.record("medium", time.Second)
+		c.latency.record("slow", 4*time.Second)
+	}
+	if got := c.requestTimeout("fast"); got != time.Second {
+		t.Errorf("expected floor of 1s, got %s", got)
+	}
+	if got := c.requestTimeout("medium"); got != 3*time.Second {
+		t.Errorf("expected 3s, got %s", got)
+	}
+	if got := c.requestTimeout("slow"); got != 5*time.Second {
+		t.Errorf("expected ceiling of 5s, got %s", got)
+	}
+
+	if got := NewClient(10, 0, 0, 10).requestTimeout("fast"); got != 0 {
+		t.Errorf("expected no adaptive timeout when disabled, got %s", got)
+	}
+}
+
+func TestAdaptiveTimeoutFailsFastOnHang(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) > latencyMinSamples {
+			time.Sleep(2 * time.Second)
+		}
+		w.WriteHeader(200)
+	}))
+	defer server.Close()
+
+	client := NewClient(10, 0, 0, 10, WithAdaptiveTimeout(3, 200*time.Millisecond, 10*time.Second))
+
+	for i := 0; i < latencyMinSamples; i++ {
+		req, _ := http.NewRequest("GET", server.URL, nil)
+		if _, _, err := client.Do(req, 0); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	start := time.Now()
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, _, err := client.Do(req, 0); err == nil {
+		t.Fatal("expected the hanging request to time out")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected to fail near the 200ms floor, took %s", elapsed)
+	}
+}
+
+func TestSeedAdaptiveTimeout(t *testing.T) {
+	c := NewClient(10, 0, 0, 10, WithAdaptiveTimeout(3, time.Second, 5*time.Second))
+
+	c.SeedAdaptiveTimeout("a", 2*time.Second)
+	c.SeedAdaptiveTimeout("a", 1500*time.Millisecond) // a smaller seed for the same host is ignored
+	if got := c.requestTimeout("a"); got != 2*time.Second {
+		t.Errorf("expected the seeded 2s before any samples, got %s", got)
+	}
+	c.SeedAdaptiveTimeout("fast", 10*time.Millisecond)
+	c.SeedAdaptiveTimeout("slow", time.Minute)
+	if got := c.requestTimeout("fast"); got != time.Second {
+		t.Errorf("expected the seed to be raised to the 1s floor, got %s", got)
+	}
+	if got := c.requestTimeout("slow"); got != 5*time.Second {
+		t.Errorf("expected the seed to be capped at 5s, got %s", got)
+	}
+
+	// Once the window fills, the observed p95 replaces the seed.
+	for i := 0; i < latencyMinSamples; i++ {
+		c.latency.record("a", 1200*time.Millisecond)
+	}
+	if got := c.requestTimeout("a"); got != 3600*time.Millisecond {
+		t.Errorf("expected 3x p95 = 3.6s after enough samples, got %s", got)
+	}
+
+	plain := NewClient(10, 0, 0, 10)
+	plain.SeedAdaptiveTimeout("a", 2*time.Second)
+	if got := plain.requestTimeout("a"); got != 0 {
+		t.Errorf("expected seeding to be ignored without --adaptive-timeout, got %s", got)
+	}
+}
@@ -0,0 +1,41 @@
+package transport
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// WithProxy routes every connection through proxyURL, e.g. Burp at
+// http://127.0.0.1:8080 or a SOCKS pivot at socks5://127.0.0.1:9050.
+// http and https proxies are set as Transport.Proxy, so https targets are
+// tunnelled with CONNECT; socks5 and socks5h replace the dialer and let the
+// proxy resolve target names. Requests keep the target URL, so rate limits
+// and the circuit breaker still key on the target host, never the proxy.
+func WithProxy(proxyURL *url.URL) (Option, error) {
+	switch proxyURL.Scheme {
+	case "http", "https":
+		return func(c *Client) {
+			c.transport.Proxy = http.ProxyURL(proxyURL)
+		}, nil
+	case "socks5", "socks5h":
+		forward := &net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}
+		dialer, err := proxy.FromURL(proxyURL, forward)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --proxy %q: %w", proxyURL.Redacted(), err)
+		}
+		contextDialer, ok := dialer.(proxy.ContextDialer)
+		if !ok {
+			return nil, fmt.Errorf("invalid --proxy %q: SOCKS dialer does not support contexts", proxyURL.Redacted())
+		}
+		return func(c *Client) {
+			c.transport.DialContext = contextDialer.DialContext
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported --proxy scheme %q. Valid values: http, https, socks5, socks5h", proxyURL.Scheme)
+	}
+}
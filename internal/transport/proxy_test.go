@@ -0,0 +1,161 @@
+package transport
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestWithProxy_HTTP(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.URL.String())
+		mu.Unlock()
+		if r.URL.Path == "/down" {
+			w.WriteHeader(502)
+			return
+		}
+		w.Write([]byte("via proxy"))
+	}))
+	defer proxyServer.Close()
+
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	opt, err := WithProxy(proxyURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(10, 0, 0, 10, opt)
+
+	req, _ := http.NewRequest("GET", "http://target.test/admin", nil)
+	resp, body, err := client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 || string(body) != "via proxy" {
+		t.Errorf("expected the proxy's response, got %d %q", resp.StatusCode, body)
+	}
+
+	// Failures behind the proxy count against the target, not the proxy.
+	for i := 0; i < client.circuitBreaker.threshold; i++ {
+		req, _ := http.NewRequest("GET", "http://target.test/down", nil)
+		client.Do(req, 0)
+	}
+	if !client.circuitBreaker.isOpen("target.test") {
+		t.Error("expected the circuit breaker to open for the target host")
+	}
+	if client.circuitBreaker.isOpen(proxyURL.Host) {
+		t.Error("expected the circuit breaker not to key on the proxy host")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seen) == 0 || seen[0] != "http://target.test/admin" {
+		t.Errorf("expected the proxy to receive the absolute target URL, got %v", seen)
+	}
+}
+
+func TestWithProxy_SOCKS5(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("host=" + r.Host))
+	}))
+	defer target.Close()
+
+	requested := make(chan string, 1)
+	socks := socks5Server(t, target.Listener.Addr().String(), requested)
+
+	opt, err := WithProxy(&url.URL{Scheme: "socks5", Host: socks})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(10, 0, 0, 10, opt)
+
+	req, _ := http.NewRequest("GET", "http://internal.test:8080/", nil)
+	resp, body, err := client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 || string(body) != "host=internal.test:8080" {
+		t.Errorf("expected the target's response, got %d %q", resp.StatusCode, body)
+	}
+	if got := <-requested; got != "internal.test:8080" {
+		t.Errorf("expected the proxy to be asked for internal.test:8080, got %q", got)
+	}
+}
+
+func TestWithProxy_UnsupportedScheme(t *testing.T) {
+	if _, err := WithProxy(&url.URL{Scheme: "ftp", Host: "127.0.0.1:21"}); err == nil {
+		t.Error("expected error for an ftp proxy")
+	}
+}
+
+// socks5Server accepts one unauthenticated SOCKS5 CONNECT, reports the
+// requested address on requested and connects it to upstream.
+func socks5Server(t *testing.T, upstream string, requested chan<- string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// Greeting: version, method count, methods. Reply "no auth".
+		greeting := make([]byte, 2)
+		if _, err := io.ReadFull(conn, greeting); err != nil {
+			return
+		}
+		io.CopyN(io.Discard, conn, int64(greeting[1]))
+		conn.Write([]byte{5, 0})
+
+		// Request: version, CONNECT, reserved, address type, address, port.
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+		var host string
+		switch header[3] {
+		case 1:
+			ip := make([]byte, 4)
+			io.ReadFull(conn, ip)
+			host = net.IP(ip).String()
+		case 3:
+			n := make([]byte, 1)
+			io.ReadFull(conn, n)
+			name := make([]byte, n[0])
+			io.ReadFull(conn, name)
+			host = string(name)
+		default:
+			return
+		}
+		port := make([]byte, 2)
+		io.ReadFull(conn, port)
+		requested <- net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+
+		backend, err := net.Dial("tcp", upstream)
+		if err != nil {
+			conn.Write([]byte{5, 5, 0, 1, 0, 0, 0, 0, 0, 0})
+			return
+		}
+		defer backend.Close()
+		conn.Write([]byte{5, 0, 0, 1, 127, 0, 0, 1, 0, 0})
+
+		go io.Copy(backend, conn)
+		io.Copy(conn, backend)
+	}()
+
+	return ln.Addr().String()
+}
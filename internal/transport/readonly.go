@@ -0,0 +1,35 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrReadOnly is returned for any request other than a GET on a client built
+// with WithReadOnly.
+var ErrReadOnly = errors.New("read-only mode allows GET requests only")
+
+// WithReadOnly refuses every request whose method is not GET, including
+// calibration probes made through HTTPClient. Callers should not build such
+// requests under --read-only in the first place; this is the backstop that
+// keeps a missed code path from reaching the target.
+func WithReadOnly() Option {
+	return func(c *Client) {
+		c.httpClient.Transport = &readOnlyTransport{next: c.httpClient.Transport}
+	}
+}
+
+type readOnlyTransport struct {
+	next http.RoundTripper
+}
+
+func (t *readOnlyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("%w: refused %s %s", ErrReadOnly, req.Method, req.URL)
+	}
+	return t.next.RoundTrip(req)
+}
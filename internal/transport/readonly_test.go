@@ -0,0 +1,46 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestClient_WithReadOnly(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(r.Method))
+	}))
+	defer server.Close()
+
+	client := NewClient(10, 0, 2, 10, WithReadOnly())
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, body, err := client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 || string(body) != "GET" {
+		t.Errorf("expected GET to pass through, got %d %q", resp.StatusCode, body)
+	}
+
+	for _, method := range []string{"POST", "PUT", "DELETE", "PATCH", "OPTIONS", "HEAD"} {
+		req, _ := http.NewRequest(method, server.URL, strings.NewReader("x"))
+		if _, _, err := client.Do(req, 0); !errors.Is(err, ErrReadOnly) {
+			t.Errorf("%s: expected ErrReadOnly, got %v", method, err)
+		}
+	}
+
+	// Calibration goes through HTTPClient and must be guarded too.
+	if _, err := client.HTTPClient().Post(server.URL, "text/plain", strings.NewReader("x")); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("expected HTTPClient POST to be refused, got %v", err)
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("expected only the GET to reach the server, got %d requests", got)
+	}
+}
@@ -0,0 +1,37 @@
+package transport
+
+import "net/http"
+
+// WithFollowRedirects lets the client follow up to maxHops redirects. The
+// response to the last hop is returned as is, so a chain longer than
+// maxHops ends on a 3xx rather than an error, as does a redirect to a host
+// outside the client's Scope. Without this option redirects are never
+// followed.
+func WithFollowRedirects(maxHops int) Option {
+	return func(c *Client) {
+		c.httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxHops || !c.scope.Allows(req.URL.Host) {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		}
+	}
+}
+
+// RedirectChain returns every URL requested to produce resp, starting with
+// the original request and ending with the URL that answered. It has a
+// single entry when no redirect was followed.
+func RedirectChain(resp *http.Response) []string {
+	var chain []string
+	for req := resp.Request; req != nil; {
+		chain = append(chain, req.URL.String())
+		if req.Response == nil {
+			break
+		}
+		req = req.Response.Request
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// hopServer redirects /hop/N to /hop/N-1 and answers /hop/0 with 200.
+func hopServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hop/"))
+		if n > 0 {
+			http.Redirect(w, r, "/hop/"+strconv.Itoa(n-1), http.StatusMovedPermanently)
+			return
+		}
+		w.Write([]byte("landed"))
+	}))
+}
+
+func TestClient_NoFollowByDefault(t *testing.T) {
+	server := hopServer()
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/hop/2", nil)
+	resp, _, err := NewClient(10, 0, 0, 10).Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusMovedPermanently {
+		t.Errorf("expected the 301 itself, got %d", resp.StatusCode)
+	}
+	if chain := RedirectChain(resp); len(chain) != 1 || chain[0] != server.URL+"/hop/2" {
+		t.Errorf("expected a single-entry chain, got %q", chain)
+	}
+}
+
+func TestClient_WithFollowRedirects(t *testing.T) {
+	server := hopServer()
+	defer server.Close()
+	client := NewClient(10, 0, 0, 10, WithFollowRedirects(3))
+
+	req, _ := http.NewRequest("GET", server.URL+"/hop/3", nil)
+	resp, body, err := client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 || string(body) != "landed" {
+		t.Errorf("expected to land on 200, got %d %q", resp.StatusCode, body)
+	}
+	want := []string{server.URL + "/hop/3", server.URL + "/hop/2", server.URL + "/hop/1", server.URL + "/hop/0"}
+	if chain := RedirectChain(resp); strings.Join(chain, " ") != strings.Join(want, " ") {
+		t.Errorf("chain = %q, want %q", chain, want)
+	}
+
+	// One hop too many stops on the last redirect instead of failing.
+	req, _ = http.NewRequest("GET", server.URL+"/hop/4", nil)
+	resp, _, err = client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusMovedPermanently || resp.Header.Get("Location") != "/hop/0" {
+		t.Errorf("expected the fourth 301 after 3 hops, got %d to %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	if chain := RedirectChain(resp); len(chain) != 4 {
+		t.Errorf("expected 4 URLs after 3 hops, got %q", chain)
+	}
+}
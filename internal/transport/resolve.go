@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResolveTimeout bounds one host's A/AAAA and PTR lookups together.
+const ResolveTimeout = 5 * time.Second
+
+// HostInfo is what DNS says about a target host: its addresses and the
+// reverse (PTR) names of those addresses.
+type HostInfo struct {
+	IPs   []string `json:"ips,omitempty"`
+	PTR   []string `json:"ptr,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// HostResolver resolves hosts once and caches the answer, so enrichment
+// costs one lookup per host however many requests go to it.
+type HostResolver struct {
+	resolver *net.Resolver
+
+	mu    sync.Mutex
+	cache map[string]HostInfo
+}
+
+// NewHostResolver returns a caching resolver. A nil resolver uses
+// net.DefaultResolver.
+func NewHostResolver(resolver *net.Resolver) *HostResolver {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &HostResolver{resolver: resolver, cache: make(map[string]HostInfo)}
+}
+
+// Lookup resolves host (a port is ignored) to its sorted IPs and their PTR
+// names. IP literals skip the forward lookup. A failed forward lookup is
+// recorded in Error; missing PTR records are not an error.
+func (r *HostResolver) Lookup(ctx context.Context, host string) HostInfo {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+
+	r.mu.Lock()
+	info, ok := r.cache[host]
+	r.mu.Unlock()
+	if ok {
+		return info
+	}
+
+	info = r.lookup(ctx, host)
+
+	r.mu.Lock()
+	r.cache[host] = info
+	r.mu.Unlock()
+	return info
+}
+
+// Cached returns the answer for host if Lookup already resolved it.
+func (r *HostResolver) Cached(host string) (HostInfo, bool) {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info, ok := r.cache[host]
+	return info, ok
+}
+
+func (r *HostResolver) lookup(ctx context.Context, host string) HostInfo {
+	ctx, cancel := context.WithTimeout(ctx, ResolveTimeout)
+	defer cancel()
+
+	var info HostInfo
+	if ip := net.ParseIP(host); ip != nil {
+		info.IPs = []string{ip.String()}
+	} else {
+		addrs, err := r.resolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			info.Error = err.Error()
+			return info
+		}
+		for _, addr := range addrs {
+			info.IPs = append(info.IPs, addr.IP.String())
+		}
+		sort.Strings(info.IPs)
+	}
+
+	seen := make(map[string]bool)
+	for _, ip := range info.IPs {
+		names, _ := r.resolver.LookupAddr(ctx, ip)
+		for _, name := range names {
+			name = strings.TrimSuffix(name, ".")
+			if name != "" && !seen[name] {
+				seen[name] = true
+				info.PTR = append(info.PTR, name)
+			}
+		}
+	}
+	sort.Strings(info.PTR)
+	return info
+}
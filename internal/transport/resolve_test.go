@@ -0,0 +1,50 @@
+package transport
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHostResolver_Lookup(t *testing.T) {
+	var mode atomic.Int32
+	mode.Store(dnsAnswerDoc)
+	r := NewHostResolver(fakeResolver(t, &mode))
+
+	info := r.Lookup(context.Background(), "svc.test.:8443")
+	want := HostInfo{IPs: []string{"192.0.2.10"}, PTR: []string{"svc.test"}}
+	if !reflect.DeepEqual(info, want) {
+		t.Fatalf("expected %+v, got %+v", want, info)
+	}
+
+	// A second lookup is served from the cache, even once DNS fails.
+	mode.Store(dnsNXDomain)
+	if again := r.Lookup(context.Background(), "svc.test."); !reflect.DeepEqual(again, want) {
+		t.Errorf("expected cached %+v, got %+v", want, again)
+	}
+	if cached, ok := r.Cached("svc.test.:443"); !ok || !reflect.DeepEqual(cached, want) {
+		t.Errorf("expected Cached to return %+v, got %+v (ok=%v)", want, cached, ok)
+	}
+}
+
+func TestHostResolver_Errors(t *testing.T) {
+	var mode atomic.Int32
+	mode.Store(dnsNXDomain)
+	r := NewHostResolver(fakeResolver(t, &mode))
+
+	info := r.Lookup(context.Background(), "missing.test.")
+	if info.Error == "" || len(info.IPs) != 0 {
+		t.Errorf("expected a lookup error and no IPs, got %+v", info)
+	}
+
+	// IP literals skip the forward lookup; a missing PTR is not an error.
+	info = r.Lookup(context.Background(), "[2001:db8::1]:80")
+	if info.Error != "" || !reflect.DeepEqual(info.IPs, []string{"2001:db8::1"}) || len(info.PTR) != 0 {
+		t.Errorf("expected the literal address without PTR names, got %+v", info)
+	}
+
+	if _, ok := r.Cached("never-looked-up.test"); ok {
+		t.Error("expected no cache entry for an unresolved host")
+	}
+}
@@ -0,0 +1,75 @@
+package transport
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"strings"
+)
+
+// Scope decides which hosts the client may contact (--allow, --deny).
+// Patterns are globs matched against the host name without its port,
+// ignoring case: "*.example.com" matches every subdomain of example.com
+// but not example.com itself. A nil Scope allows every host.
+type Scope struct {
+	allow []string
+	deny  []string
+}
+
+// NewScope builds a Scope from allow and deny patterns. It returns nil
+// when both are empty, and an error for a malformed pattern.
+func NewScope(allow, deny []string) (*Scope, error) {
+	if len(allow) == 0 && len(deny) == 0 {
+		return nil, nil
+	}
+	s := &Scope{}
+	for _, list := range []struct {
+		flag     string
+		patterns []string
+		dst      *[]string
+	}{{"--allow", allow, &s.allow}, {"--deny", deny, &s.deny}} {
+		for _, pattern := range list.patterns {
+			pattern = strings.ToLower(strings.TrimSpace(pattern))
+			if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+				return nil, fmt.Errorf("invalid %s pattern %q. Use a host name or a glob such as *.example.com", list.flag, pattern)
+			}
+			*list.dst = append(*list.dst, pattern)
+		}
+	}
+	return s, nil
+}
+
+// Allows reports whether host (optionally with a port) may be contacted:
+// it must match no deny pattern and, when allow patterns are set, at
+// least one of them.
+func (s *Scope) Allows(host string) bool {
+	if s == nil {
+		return true
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	for _, pattern := range s.deny {
+		if ok, _ := path.Match(pattern, host); ok {
+			return false
+		}
+	}
+	if len(s.allow) == 0 {
+		return true
+	}
+	for _, pattern := range s.allow {
+		if ok, _ := path.Match(pattern, host); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// WithScope refuses requests to hosts s does not allow, and stops
+// following a redirect (--follow-redirects) that leaves the scope.
+func WithScope(s *Scope) Option {
+	return func(c *Client) {
+		c.scope = s
+	}
+}
@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestScope_Allows(t *testing.T) {
+	scope, err := NewScope([]string{"*.example.com", "Example.org"}, []string{"admin.example.com", "*.internal.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"www.example.com", true},
+		{"a.b.example.com", true},
+		{"WWW.EXAMPLE.COM:8443", true},
+		{"example.com", false}, // *.example.com matches subdomains only
+		{"example.org", true},
+		{"www.example.org", false},
+		{"admin.example.com", false},
+		{"db.internal.example.com", false},
+		{"example.com.evil.net", false},
+		{"evil.net", false},
+	}
+	for _, tt := range tests {
+		if got := scope.Allows(tt.host); got != tt.want {
+			t.Errorf("Allows(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+
+	denyOnly, _ := NewScope(nil, []string{"*.gov"})
+	if !denyOnly.Allows("example.com") || denyOnly.Allows("www.agency.gov") {
+		t.Error("expected deny patterns alone to exclude only matching hosts")
+	}
+
+	var none *Scope
+	if !none.Allows("anything.test") {
+		t.Error("expected a nil scope to allow every host")
+	}
+	if s, err := NewScope(nil, nil); s != nil || err != nil {
+		t.Errorf("expected no scope without patterns, got %v, %v", s, err)
+	}
+	for _, bad := range []string{"[a-", "  "} {
+		if _, err := NewScope([]string{bad}, nil); err == nil {
+			t.Errorf("expected an error for pattern %q", bad)
+		}
+	}
+}
+
+func TestClient_WithScope(t *testing.T) {
+	offScope := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the out-of-scope host must not be contacted")
+	}))
+	defer offScope.Close()
+	inScope := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, offScope.URL+"/landing", http.StatusFound)
+	}))
+	defer inScope.Close()
+
+	// Both servers listen on 127.0.0.1; reaching one as "localhost" puts
+	// it out of scope.
+	scope, _ := NewScope(nil, []string{"localhost"})
+	client := NewClient(10, 0, 0, 10, WithScope(scope), WithFollowRedirects(5))
+
+	req, _ := http.NewRequest("GET", "http://localhost:"+portOf(offScope.URL), nil)
+	if _, _, err := client.Do(req, 0); !errors.Is(err, ErrOutOfScope) {
+		t.Errorf("expected ErrOutOfScope, got %v", err)
+	}
+
+	// A redirect that leaves the scope ends the chain at the 3xx.
+	offScopeByName := "http://localhost:" + portOf(offScope.URL) + "/landing"
+	inScope.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, offScopeByName, http.StatusFound)
+	})
+	req, _ = http.NewRequest("GET", inScope.URL, nil)
+	resp, _, err := client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusFound {
+		t.Errorf("expected the off-scope redirect to be reported as a 302, got %d", resp.StatusCode)
+	}
+}
+
+func portOf(rawURL string) string {
+	return rawURL[len("http://127.0.0.1:"):]
+}
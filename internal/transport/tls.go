@@ -0,0 +1,191 @@
+package transport
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// tlsProfile is a set of TLS 1.2 cipher suites and curves to offer.
+type tlsProfile struct {
+	cipherSuites []uint16
+	curves       []tls.CurveID
+}
+
+// tlsProfiles restrict the TLS 1.2 cipher suites and curves offered to
+// those current desktop browsers enable. They are allow-lists only:
+// crypto/tls sends suites and curves in its own order whatever order is
+// configured, always offers its TLS 1.3 suites, and fixes the extension
+// layout, so the ClientHello still looks like Go, not a browser.
+var tlsProfiles = map[string]tlsProfile{
+	"chrome": {
+		cipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+			tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+			tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+		},
+		curves: []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384},
+	},
+	"firefox": {
+		cipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+			tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+			tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+		},
+		curves: []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384, tls.CurveP521},
+	},
+}
+
+var curveNames = map[string]tls.CurveID{
+	"X25519": tls.X25519,
+	"P256":   tls.CurveP256,
+	"P384":   tls.CurveP384,
+	"P521":   tls.CurveP521,
+}
+
+// TLSAllowList resolves the -tls-profile, -tls-cipher-suites and
+// -tls-curves settings into a client Option that limits the TLS 1.2
+// cipher suites and curves offered. Explicit cipher suites or curves
+// replace the corresponding half of the profile; the order given is not
+// kept. Returns a nil Option when nothing is configured.
+func TLSAllowList(profile string, cipherSuites, curves []string) (Option, error) {
+	var p tlsProfile
+	if profile != "" {
+		var ok bool
+		if p, ok = tlsProfiles[strings.ToLower(profile)]; !ok {
+			return nil, fmt.Errorf("unknown TLS profile %q. Valid values: chrome, firefox", profile)
+		}
+	}
+
+	if len(cipherSuites) > 0 {
+		ids, err := parseCipherSuites(cipherSuites)
+		if err != nil {
+			return nil, err
+		}
+		p.cipherSuites = ids
+	}
+	if len(curves) > 0 {
+		ids, err := parseCurves(curves)
+		if err != nil {
+			return nil, err
+		}
+		p.curves = ids
+	}
+
+	if len(p.cipherSuites) == 0 && len(p.curves) == 0 {
+		return nil, nil
+	}
+	return func(c *Client) {
+		if c.transport.TLSClientConfig == nil {
+			c.transport.TLSClientConfig = &tls.Config{}
+		}
+		c.transport.TLSClientConfig.CipherSuites = p.cipherSuites
+		c.transport.TLSClientConfig.CurvePreferences = p.curves
+	}, nil
+}
+
+// WithSNI sends name as the TLS server name (SNI) and verifies the server
+// certificate against it, independently of the request URL host. The HTTP
+// Host header is unaffected.
+func WithSNI(name string) Option {
+	return func(c *Client) {
+		if c.transport.TLSClientConfig == nil {
+			c.transport.TLSClientConfig = &tls.Config{}
+		}
+		c.transport.TLSClientConfig.ServerName = name
+	}
+}
+
+// WithInsecureSkipVerify accepts any server certificate, for internal
+// targets with self-signed or private-CA certificates.
+func WithInsecureSkipVerify() Option {
+	return func(c *Client) {
+		if c.transport.TLSClientConfig == nil {
+			c.transport.TLSClientConfig = &tls.Config{}
+		}
+		c.transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+}
+
+// WithTLSMinVersion sets the lowest TLS version offered, e.g.
+// tls.VersionTLS10 to reach legacy servers Go refuses by default.
+func WithTLSMinVersion(version uint16) Option {
+	return func(c *Client) {
+		if c.transport.TLSClientConfig == nil {
+			c.transport.TLSClientConfig = &tls.Config{}
+		}
+		c.transport.TLSClientConfig.MinVersion = version
+	}
+}
+
+// WithHTTP2 offers HTTP/2 to HTTPS targets via ALPN. The client's custom
+// dialer otherwise leaves net/http on HTTP/1.1. Servers that do not
+// speak h2 are still reached over HTTP/1.1.
+func WithHTTP2() Option {
+	return func(c *Client) {
+		c.transport.ForceAttemptHTTP2 = true
+	}
+}
+
+// WithHTTP1 pins every connection to HTTP/1.1, even when an option or
+// the TLS configuration would otherwise allow h2.
+func WithHTTP1() Option {
+	return func(c *Client) {
+		c.transport.ForceAttemptHTTP2 = false
+		c.transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+}
+
+// parseCipherSuites maps IANA cipher suite names (e.g.
+// TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256) to their IDs, preserving order.
+func parseCipherSuites(names []string) ([]uint16, error) {
+	known := make(map[string]uint16)
+	for _, cs := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		known[cs.Name] = cs.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := known[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown TLS cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// parseCurves maps curve names (X25519, P256, P384, P521) to CurveIDs,
+// preserving order.
+func parseCurves(names []string) ([]tls.CurveID, error) {
+	ids := make([]tls.CurveID, 0, len(names))
+	for _, name := range names {
+		id, ok := curveNames[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown TLS curve %q. Valid values: X25519, P256, P384, P521", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,202 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTLSAllowList_None(t *testing.T) {
+	opt, err := TLSAllowList("", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opt != nil {
+		t.Error("expected nil option when nothing is configured")
+	}
+}
+
+func TestTLSAllowList_Invalid(t *testing.T) {
+	if _, err := TLSAllowList("safari", nil, nil); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+	if _, err := TLSAllowList("", []string{"TLS_NOT_A_SUITE"}, nil); err == nil {
+		t.Error("expected error for unknown cipher suite")
+	}
+	if _, err := TLSAllowList("", nil, []string{"P999"}); err == nil {
+		t.Error("expected error for unknown curve")
+	}
+}
+
+func TestTLSAllowList_Overrides(t *testing.T) {
+	opt, err := TLSAllowList("chrome", []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}, []string{"p384", "X25519"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	client := NewClient(10, 0, 0, 10, opt)
+	cfg := client.transport.TLSClientConfig
+	if len(cfg.CipherSuites) != 1 || cfg.CipherSuites[0] != tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384 {
+		t.Errorf("expected explicit cipher suite to override profile, got %v", cfg.CipherSuites)
+	}
+	if len(cfg.CurvePreferences) != 2 || cfg.CurvePreferences[0] != tls.CurveP384 {
+		t.Errorf("expected explicit curves, got %v", cfg.CurvePreferences)
+	}
+}
+
+func TestTLSAllowList_ClientHello(t *testing.T) {
+	var hello *tls.ClientHelloInfo
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	server.TLS = &tls.Config{
+		GetConfigForClient: func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+			hello = info
+			return nil, nil
+		},
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	opt, err := TLSAllowList("firefox", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	client := NewClient(10, 0, 0, 10, opt)
+	client.transport.TLSClientConfig.InsecureSkipVerify = true // test certificate
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, _, err := client.Do(req, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if hello == nil {
+		t.Fatal("expected to capture the ClientHello")
+	}
+	want := tlsProfiles["firefox"].curves
+	if len(hello.SupportedCurves) < len(want) {
+		t.Fatalf("expected at least %d curves, got %v", len(want), hello.SupportedCurves)
+	}
+	for i, c := range want {
+		if hello.SupportedCurves[i] != c {
+			t.Errorf("curve %d: expected %v, got %v", i, c, hello.SupportedCurves[i])
+		}
+	}
+}
+
+func TestWithSNI(t *testing.T) {
+	var serverName, host string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		w.WriteHeader(200)
+	}))
+	server.TLS = &tls.Config{
+		GetConfigForClient: func(info *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverName = info.ServerName
+			return nil, nil
+		},
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	// The httptest certificate is valid for example.com, so verification
+	// succeeds against the SNI name even though the URL host is an IP.
+	client := NewClient(10, 0, 0, 10, WithSNI("example.com"))
+	client.transport.TLSClientConfig.RootCAs = x509.NewCertPool()
+	client.transport.TLSClientConfig.RootCAs.AddCert(server.Certificate())
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, _, err := client.Do(req, 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if serverName != "example.com" {
+		t.Errorf("expected SNI example.com, got %q", serverName)
+	}
+	if host != req.URL.Host {
+		t.Errorf("expected Host to stay the URL host %q, got %q", req.URL.Host, host)
+	}
+}
+
+func TestWithInsecureSkipVerify(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // expected handshake failures
+	server.StartTLS()
+	defer server.Close()
+
+	// Verification stays on by default: the self-signed certificate fails.
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, _, err := NewClient(10, 0, 0, 10).Do(req, 0); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("expected a certificate error without -k, got %v", err)
+	}
+
+	req, _ = http.NewRequest("GET", server.URL, nil)
+	resp, body, err := NewClient(10, 0, 0, 10, WithInsecureSkipVerify()).Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error with -k: %v", err)
+	}
+	if resp.StatusCode != 200 || string(body) != "ok" {
+		t.Errorf("expected 200 ok, got %d %q", resp.StatusCode, body)
+	}
+}
+
+func TestWithTLSMinVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.TLS = &tls.Config{MinVersion: tls.VersionTLS10, MaxVersion: tls.VersionTLS11}
+	server.StartTLS()
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	if _, _, err := NewClient(10, 0, 0, 10, WithInsecureSkipVerify()).Do(req, 0); err == nil {
+		t.Fatal("expected the default client to refuse a TLS 1.1 server")
+	}
+
+	req, _ = http.NewRequest("GET", server.URL, nil)
+	resp, _, err := NewClient(10, 0, 0, 10, WithInsecureSkipVerify(), WithTLSMinVersion(tls.VersionTLS10)).Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error with a TLS 1.0 floor: %v", err)
+	}
+	if resp.TLS == nil || resp.TLS.Version != tls.VersionTLS11 {
+		t.Errorf("expected a TLS 1.1 connection, got %+v", resp.TLS)
+	}
+}
+
+func TestWithHTTP2(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default", nil, "HTTP/1.1"},
+		{"http2", []Option{WithHTTP2()}, "HTTP/2.0"},
+		{"http1", []Option{WithHTTP2(), WithHTTP1()}, "HTTP/1.1"},
+	}
+	for _, tt := range tests {
+		opts := append([]Option{WithInsecureSkipVerify()}, tt.opts...)
+		req, _ := http.NewRequest("GET", server.URL, nil)
+		resp, body, err := NewClient(10, 0, 0, 10, opts...).Do(req, 0)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if resp.Proto != tt.want || string(body) != tt.want {
+			t.Errorf("%s: expected %s, got %s (server saw %s)", tt.name, tt.want, resp.Proto, body)
+		}
+	}
+}
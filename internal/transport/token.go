@@ -0,0 +1,218 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenCommandTimeout bounds each run of a --token-cmd command.
+const TokenCommandTimeout = 30 * time.Second
+
+// TokenSource obtains a bearer token by running an external command and
+// caches it for a refresh interval. The command is executed directly, never
+// through a shell, so the command line cannot inject further commands.
+type TokenSource struct {
+	argv     []string
+	interval time.Duration
+
+	mu      sync.Mutex
+	token   string
+	fetched time.Time
+}
+
+// NewTokenSource parses command into arguments (single quotes, double
+// quotes and backslash escapes are honored; nothing else is interpreted).
+// A zero interval only refreshes the token after a 401.
+func NewTokenSource(command string, interval time.Duration) (*TokenSource, error) {
+	argv, err := splitCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(argv) == 0 {
+		return nil, errors.New("token command is empty")
+	}
+	return &TokenSource{argv: argv, interval: interval}, nil
+}
+
+// Token returns the cached token, running the command first when there is
+// none yet or the refresh interval has passed.
+func (s *TokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && (s.interval <= 0 || time.Since(s.fetched) < s.interval) {
+		return s.token, nil
+	}
+	return s.fetchLocked(ctx)
+}
+
+// Refresh replaces a token the server rejected. When several requests get a
+// 401 for the same token, only the first runs the command; the others get
+// its result.
+func (s *TokenSource) Refresh(ctx context.Context, rejected string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && s.token != rejected {
+		return s.token, nil
+	}
+	return s.fetchLocked(ctx)
+}
+
+func (s *TokenSource) fetchLocked(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, TokenCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.argv[0], s.argv[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("token command timed out after %s", TokenCommandTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("token command failed: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("token command failed: %w", err)
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", errors.New("token command printed no token")
+	}
+	if strings.ContainsAny(token, "\r\n") {
+		return "", errors.New("token command printed more than one line")
+	}
+	s.token = token
+	s.fetched = time.Now()
+	return token, nil
+}
+
+// WithTokenSource sends "Authorization: Bearer <token>" from src on every
+// request, including calibration probes made through HTTPClient. A 401
+// response triggers one token refresh and a single retry of the request.
+// Under --follow-redirects the token only goes to the host the chain
+// started on, never to a host a redirect points elsewhere.
+func WithTokenSource(src *TokenSource) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = &tokenTransport{next: c.httpClient.Transport, src: src}
+	}
+}
+
+type tokenTransport struct {
+	next http.RoundTripper
+	src  *TokenSource
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !sameHostAsChainStart(req) {
+		return t.next.RoundTrip(req)
+	}
+	token, err := t.src.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(withBearer(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// Only retry requests whose body can be sent again.
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+	fresh, err := t.src.Refresh(req.Context(), token)
+	if err != nil || fresh == token {
+		return resp, nil
+	}
+	retry := withBearer(req, fresh)
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return resp, nil
+		}
+	}
+	resp.Body.Close()
+	return t.next.RoundTrip(retry)
+}
+
+// sameHostAsChainStart reports whether req goes to the host of the first
+// request in its redirect chain. A request that is not a redirect hop
+// always does.
+func sameHostAsChainStart(req *http.Request) bool {
+	first := req
+	for first.Response != nil && first.Response.Request != nil {
+		first = first.Response.Request
+	}
+	return strings.EqualFold(first.URL.Host, req.URL.Host)
+}
+
+// withBearer returns a copy of req carrying the token; RoundTrippers must
+// not modify the caller's request.
+func withBearer(req *http.Request, token string) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Header.Set("Authorization", "Bearer "+token)
+	return clone
+}
+
+// splitCommand splits a command line into arguments like a POSIX shell
+// would for quoting purposes only: no variables, globs, pipes or command
+// substitution.
+func splitCommand(command string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inArg := false
+	var quote rune
+
+	for i := 0; i < len(command); i++ {
+		ch := rune(command[i])
+		switch {
+		case quote == '\'':
+			if ch == '\'' {
+				quote = 0
+			} else {
+				cur.WriteByte(command[i])
+			}
+		case quote == '"':
+			switch {
+			case ch == '"':
+				quote = 0
+			case ch == '\\' && i+1 < len(command) && strings.ContainsRune(`"\$`+"`", rune(command[i+1])):
+				i++
+				cur.WriteByte(command[i])
+			default:
+				cur.WriteByte(command[i])
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+			inArg = true
+		case ch == '\\':
+			if i+1 < len(command) {
+				i++
+				cur.WriteByte(command[i])
+				inArg = true
+			}
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteByte(command[i])
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in token command", quote)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,206 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"./get-token.sh", []string{"./get-token.sh"}},
+		{"  cmd   a  b ", []string{"cmd", "a", "b"}},
+		{`cmd 'a b' "c d"`, []string{"cmd", "a b", "c d"}},
+		{`cmd a\ b "x\"y" 'it''s'`, []string{"cmd", "a b", `x"y`, "its"}},
+		{`cmd '$HOME' "$(id)" ; rm`, []string{"cmd", "$HOME", "$(id)", ";", "rm"}},
+		{`cmd ""`, []string{"cmd", ""}},
+		{"cmd héllo", []string{"cmd", "héllo"}},
+	}
+
+	for _, tt := range tests {
+		got, err := splitCommand(tt.input)
+		if err != nil {
+			t.Errorf("splitCommand(%q): unexpected error %v", tt.input, err)
+			continue
+		}
+		if strings.Join(got, "|") != strings.Join(tt.expected, "|") || len(got) != len(tt.expected) {
+			t.Errorf("splitCommand(%q): expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+
+	if _, err := splitCommand(`cmd "open`); err == nil {
+		t.Error("expected error for unterminated quote")
+	}
+	if _, err := NewTokenSource("   ", time.Minute); err == nil {
+		t.Error("expected error for empty command")
+	}
+}
+
+// tokenScript writes a script that prints token-N, where N counts its runs.
+func tokenScript(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	script := filepath.Join(dir, "token.sh")
+	body := "#!/bin/sh\nn=$(cat " + dir + "/count 2>/dev/null || echo 0)\nn=$((n+1))\necho $n > " + dir + "/count\necho token-$n\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+func TestTokenSource_CachesAndRefreshes(t *testing.T) {
+	src, err := NewTokenSource(tokenScript(t), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if tok, err := src.Token(ctx); err != nil || tok != "token-1" {
+			t.Fatalf("expected cached token-1, got %q (%v)", tok, err)
+		}
+	}
+
+	if tok, _ := src.Refresh(ctx, "token-1"); tok != "token-2" {
+		t.Errorf("expected refresh to token-2, got %q", tok)
+	}
+	// A second 401 for the already-replaced token must not run the command again.
+	if tok, _ := src.Refresh(ctx, "token-1"); tok != "token-2" {
+		t.Errorf("expected token-2 reused, got %q", tok)
+	}
+}
+
+func TestTokenSource_NoShell(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "pwned")
+	src, err := NewTokenSource("echo ok; touch "+marker, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok, err := src.Token(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tok != "ok; touch "+marker {
+		t.Errorf("expected the arguments echoed literally, got %q", tok)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("command line was interpreted by a shell")
+	}
+}
+
+func TestTokenSource_Errors(t *testing.T) {
+	for _, cmd := range []string{"false", "true", "/nonexistent/token-cmd"} {
+		src, _ := NewTokenSource(cmd, 0)
+		if _, err := src.Token(context.Background()); err == nil {
+			t.Errorf("%s: expected an error", cmd)
+		}
+	}
+}
+
+func TestClient_WithTokenSource(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		// The server only accepts the second token the command hands out.
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(401)
+			return
+		}
+		w.Write([]byte("secret area"))
+	}))
+	defer server.Close()
+
+	src, err := NewTokenSource(tokenScript(t), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(10, 0, 0, 10, WithTokenSource(src))
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, body, err := client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 || string(body) != "secret area" {
+		t.Errorf("expected 200 after token refresh, got %d %q", resp.StatusCode, body)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("expected one retry after the 401, got %d requests", got)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("expected the caller's request to be left unmodified")
+	}
+
+	// Calibration goes through HTTPClient and must carry the token too.
+	resp2, err := client.HTTPClient().Get(server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp2.Body.Close()
+	if resp2.StatusCode != 200 {
+		t.Errorf("expected HTTPClient requests to be authenticated, got %d", resp2.StatusCode)
+	}
+}
+
+func TestClient_WithTokenSource_CrossHostRedirect(t *testing.T) {
+	var leaked atomic.Value
+	leaked.Store("")
+	attacker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked.Store(r.Header.Get("Authorization"))
+		w.Write([]byte("elsewhere"))
+	}))
+	defer attacker.Close()
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/away":
+			http.Redirect(w, r, attacker.URL+"/collect", http.StatusFound)
+		case "/here":
+			http.Redirect(w, r, "/landed", http.StatusFound)
+		case "/landed":
+			if r.Header.Get("Authorization") != "Bearer token-1" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte("landed"))
+		}
+	}))
+	defer target.Close()
+
+	src, err := NewTokenSource(tokenScript(t), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient(10, 0, 0, 10, WithTokenSource(src), WithFollowRedirects(5))
+
+	req, _ := http.NewRequest("GET", target.URL+"/away", nil)
+	resp, body, err := client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 || string(body) != "elsewhere" {
+		t.Fatalf("expected the redirect followed, got %d %q", resp.StatusCode, body)
+	}
+	if got := leaked.Load().(string); got != "" {
+		t.Errorf("expected no token sent to the other host, got %q", got)
+	}
+
+	// A redirect on the same host keeps the token.
+	req, _ = http.NewRequest("GET", target.URL+"/here", nil)
+	resp, body, err = client.Do(req, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != 200 || string(body) != "landed" {
+		t.Errorf("expected the same-host hop authenticated, got %d %q", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,64 @@
+package ui
+
+import "os"
+
+// Text styles and colors used throughout the UI. They hold ANSI escape
+// sequences while color is on and are empty otherwise; see SetColor.
+var (
+	reset, bold, dim, italic                            string
+	red, green, yellow, blue, magenta, cyan, white      string
+	bgRed, bgGreen, bgYellow, bgBlue, bgMagenta, bgCyan string
+)
+
+// ansiCodes maps each style variable to its escape sequence.
+var ansiCodes = map[*string]string{
+	&reset:  "\033[0m",
+	&bold:   "\033[1m",
+	&dim:    "\033[2m",
+	&italic: "\033[3m",
+
+	&red:     "\033[31m",
+	&green:   "\033[32m",
+	&yellow:  "\033[33m",
+	&blue:    "\033[34m",
+	&magenta: "\033[35m",
+	&cyan:    "\033[36m",
+	&white:   "\033[37m",
+
+	&bgRed:     "\033[41m",
+	&bgGreen:   "\033[42m",
+	&bgYellow:  "\033[43m",
+	&bgBlue:    "\033[44m",
+	&bgMagenta: "\033[45m",
+	&bgCyan:    "\033[46m",
+}
+
+// colorEnabled is whether styles are written; see SetColor.
+var colorEnabled = true
+
+func init() {
+	SetColor(true)
+}
+
+// color returns code while color is enabled and "" otherwise.
+func color(code string) string {
+	if !colorEnabled {
+		return ""
+	}
+	return code
+}
+
+// SetColor turns colors and text styles in all UI output on or off.
+func SetColor(enabled bool) {
+	colorEnabled = enabled
+	for style, code := range ansiCodes {
+		*style = color(code)
+	}
+}
+
+// IsTerminal reports whether f is a character device, i.e. output a
+// person is watching rather than a pipe or a file.
+func IsTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
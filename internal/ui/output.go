@@ -3,83 +3,161 @@ package ui
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/capsaicin/scanner/internal/config"
+	"github.com/capsaicin/scanner/internal/reporting"
 	"github.com/capsaicin/scanner/internal/scanner"
+	"github.com/capsaicin/scanner/internal/version"
 )
 
+// Cursor / line control. These stay on without color: the live display
+// needs them to redraw its progress line.
 const (
-	reset  = "\033[0m"
-	bold   = "\033[1m"
-	dim    = "\033[2m"
-	italic = "\033[3m"
-
-	red     = "\033[31m"
-	green   = "\033[32m"
-	yellow  = "\033[33m"
-	blue    = "\033[34m"
-	magenta = "\033[35m"
-	cyan    = "\033[36m"
-	white   = "\033[37m"
-
-	bgRed     = "\033[41m"
-	bgGreen   = "\033[42m"
-	bgYellow  = "\033[43m"
-	bgBlue    = "\033[44m"
-	bgMagenta = "\033[45m"
-	bgCyan    = "\033[46m"
-
-	// Cursor / line control
 	clearLine = "\033[2K"
 	moveUp    = "\033[1A"
 )
 
+// out receives all human-readable output. It is stdout unless a machine
+// sink claims stdout (-o -), in which case SetOutput moves the UI to stderr.
+var out io.Writer = os.Stdout
+
+// SetOutput redirects all UI output to w.
+func SetOutput(w io.Writer) {
+	out = w
+}
+
+// Output returns the writer UI output currently goes to.
+func Output() io.Writer {
+	return out
+}
+
 // PrintBanner displays a clean, professional banner.
 func PrintBanner() {
-	fmt.Println()
-	fmt.Printf("  %s%s┌──────────────────────────────────────────────────┐%s\n", bold, red, reset)
-	fmt.Printf("  %s%s│%s  🌶  %s%sCAPSAICIN%s  %sv3.1%s  %s%s─  Web Directory Scanner  %s%s│%s\n",
+	label := "v" + version.Version
+	// The box grows with the version label; the chili takes two columns.
+	width := utf8.RuneCountInString("  🌶  CAPSAICIN  "+label+"  ─  Web Directory Scanner  ") + 2
+	border := strings.Repeat("─", width)
+
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "  %s%s┌%s┐%s\n", bold, red, border, reset)
+	fmt.Fprintf(out, "  %s%s│%s  🌶  %s%sCAPSAICIN%s  %s%s%s  %s%s─  Web Directory Scanner  %s%s│%s\n",
 		bold, red, reset,
 		bold, white, reset,
-		dim, reset,
+		dim, label, reset,
 		dim, white, reset,
 		bold+red, reset)
-	fmt.Printf("  %s%s└──────────────────────────────────────────────────┘%s\n", bold, red, reset)
-	fmt.Println()
+	fmt.Fprintf(out, "  %s%s└%s┘%s\n", bold, red, border, reset)
+	fmt.Fprintln(out)
 }
 
 // PrintConfig displays scan configuration in a structured panel.
 func PrintConfig(cfg config.Config, targetCount int, wordCount int) {
-	fmt.Printf("  %s%s⚙  Scan Configuration%s\n", bold, cyan, reset)
-	fmt.Printf("  %s──────────────────────────────────────%s\n", dim, reset)
-	fmt.Printf("  %s%-14s%s %s%d%s\n", dim, "Targets", reset, white, targetCount, reset)
-	fmt.Printf("  %s%-14s%s %s%d%s\n", dim, "Threads", reset, white, cfg.Threads, reset)
-	fmt.Printf("  %s%-14s%s %s%ds%s\n", dim, "Timeout", reset, white, cfg.Timeout, reset)
-	fmt.Printf("  %s%-14s%s %s%s%s\n", dim, "Wordlist", reset, white, cfg.Wordlist, reset)
+	fmt.Fprintf(out, "  %s%s⚙  Scan Configuration%s\n", bold, cyan, reset)
+	fmt.Fprintf(out, "  %s──────────────────────────────────────%s\n", dim, reset)
+	fmt.Fprintf(out, "  %s%-14s%s %s%d%s\n", dim, "Targets", reset, white, targetCount, reset)
+	threads := strconv.Itoa(cfg.Threads)
+	if cfg.ThreadsAuto {
+		threads += " (auto)"
+	}
+	if cfg.ThreadsPerHost > 0 {
+		threads += fmt.Sprintf(", max %d per host", cfg.ThreadsPerHost)
+	}
+	fmt.Fprintf(out, "  %s%-14s%s %s%s%s\n", dim, "Threads", reset, white, threads, reset)
+	fmt.Fprintf(out, "  %s%-14s%s %s%ds%s\n", dim, "Timeout", reset, white, cfg.Timeout, reset)
+	fmt.Fprintf(out, "  %s%-14s%s %s%s%s\n", dim, "Wordlist", reset, white, strings.Join(cfg.WordlistPaths(), ", "), reset)
 	if wordCount > 0 {
-		fmt.Printf("  %s%-14s%s %s%d words%s\n", dim, "Words", reset, white, wordCount, reset)
+		fmt.Fprintf(out, "  %s%-14s%s %s%d words%s\n", dim, "Words", reset, white, wordCount, reset)
+	}
+	if methods := cfg.RequestMethods(); len(methods) > 1 || methods[0] != "GET" {
+		fmt.Fprintf(out, "  %s%-14s%s %s%s%s\n", dim, "Methods", reset, white, strings.Join(methods, ", "), reset)
+	}
+	if cfg.Data != "" {
+		fmt.Fprintf(out, "  %s%-14s%s %s%d bytes (%s)%s\n", dim, "Body", reset, white, len(cfg.Data), cfg.ContentType, reset)
+	}
+	if cfg.UserAgent != "" {
+		fmt.Fprintf(out, "  %s%-14s%s %s%s%s\n", dim, "User-Agent", reset, white, cfg.UserAgent, reset)
+	} else if len(cfg.UserAgents) > 0 {
+		fmt.Fprintf(out, "  %s%-14s%s %srotating %d from %s%s\n", dim, "User-Agent", reset, white, len(cfg.UserAgents), cfg.UserAgentsFile, reset)
 	}
 
 	if cfg.RateLimit > 0 {
-		fmt.Printf("  %s%-14s%s %s%d req/s%s\n", dim, "Rate Limit", reset, white, cfg.RateLimit, reset)
+		fmt.Fprintf(out, "  %s%-14s%s %s%d req/s%s\n", dim, "Rate Limit", reset, white, cfg.RateLimit, reset)
 	} else {
-		fmt.Printf("  %s%-14s%s %sunlimited%s\n", dim, "Rate Limit", reset, dim+white, reset)
+		fmt.Fprintf(out, "  %s%-14s%s %sunlimited%s\n", dim, "Rate Limit", reset, dim+white, reset)
+	}
+	if cfg.RateLimitGlobal > 0 {
+		fmt.Fprintf(out, "  %s%-14s%s %s%d req/s total%s\n", dim, "Global Limit", reset, white, cfg.RateLimitGlobal, reset)
+	}
+
+	if cfg.DelayMax > 0 {
+		delay := fmt.Sprintf("%d ms", cfg.DelayMin)
+		if cfg.DelayMax > cfg.DelayMin {
+			delay = fmt.Sprintf("%d-%d ms", cfg.DelayMin, cfg.DelayMax)
+		}
+		fmt.Fprintf(out, "  %s%-14s%s %s%s per request%s\n", dim, "Delay", reset, white, delay, reset)
 	}
 
 	if cfg.MaxDepth > 0 {
-		fmt.Printf("  %s%-14s%s %s%d%s\n", dim, "Max Depth", reset, white, cfg.MaxDepth, reset)
+		fmt.Fprintf(out, "  %s%-14s%s %s%d%s\n", dim, "Max Depth", reset, white, cfg.MaxDepth, reset)
 	}
 	if len(cfg.Extensions) > 0 {
-		fmt.Printf("  %s%-14s%s %s%s%s\n", dim, "Extensions", reset, white, strings.Join(cfg.Extensions, ", "), reset)
+		fmt.Fprintf(out, "  %s%-14s%s %s%s%s\n", dim, "Extensions", reset, white, strings.Join(cfg.Extensions, ", "), reset)
+	}
+	if cfg.ExtMode == "prefix" || cfg.ExtMode == "both" {
+		fmt.Fprintf(out, "  %s%-14s%s %s%s%s\n", dim, "Ext Mode", reset, white, cfg.ExtMode, reset)
 	}
-	if cfg.SafeMode {
-		fmt.Printf("  %s%-14s%s %s%s⚠ Safe Mode%s\n", dim, "Mode", reset, bold, yellow, reset)
+	if len(cfg.Mutations) > 0 {
+		fmt.Fprintf(out, "  %s%-14s%s %s%s%s\n", dim, "Mutations", reset, white, strings.Join(cfg.Mutations, ", "), reset)
 	}
-	fmt.Printf("  %s%-14s%s %s%s%s\n", dim, "Started", reset, white, time.Now().Format("15:04:05 — 2006-01-02"), reset)
-	fmt.Printf("  %s──────────────────────────────────────%s\n", dim, reset)
-	fmt.Println()
+	if cfg.ReadOnly {
+		fmt.Fprintf(out, "  %s%-14s%s %s%s🔒 Read-Only (GET only)%s\n", dim, "Mode", reset, bold, yellow, reset)
+	} else if cfg.SafeMode {
+		fmt.Fprintf(out, "  %s%-14s%s %s%s⚠ Safe Mode%s\n", dim, "Mode", reset, bold, yellow, reset)
+	}
+	if cfg.OnlySecrets {
+		fmt.Fprintf(out, "  %s%-14s%s %s%s🔑 Secrets Only%s\n", dim, "Filter", reset, bold, magenta, reset)
+	} else if cfg.ActionableOnly {
+		fmt.Fprintf(out, "  %s%-14s%s %s%s❗ Actionable Only%s\n", dim, "Filter", reset, bold, red, reset)
+	}
+	if cfg.Evasion != "" && cfg.Evasion != "none" {
+		fmt.Fprintf(out, "  %s%-14s%s %s%s%s\n", dim, "Evasion", reset, yellow, cfg.Evasion, reset)
+	}
+	fmt.Fprintf(out, "  %s%-14s%s %s%s%s\n", dim, "Started", reset, white, time.Now().Format("15:04:05 — 2006-01-02"), reset)
+	fmt.Fprintf(out, "  %s──────────────────────────────────────%s\n", dim, reset)
+	fmt.Fprintln(out)
+}
+
+// PrintInfo prints a dimmed status line, such as how many targets were
+// loaded.
+func PrintInfo(msg string) {
+	fmt.Fprintf(out, "  %s%s%s\n", dim, msg, reset)
+}
+
+// PrintWarning prints a highlighted warning line, e.g. for settings that
+// multiply request volume.
+func PrintWarning(msg string) {
+	fmt.Fprintf(out, "  %s%s⚠  %s%s\n\n", bold, yellow, msg, reset)
+}
+
+// PrintPlan lists every URL a --read-only scan will request before it
+// starts, so the scope can be checked against the rules of engagement.
+// maxDepth > 0 notes that recursion adds discovered directories.
+func PrintPlan(plan []string, maxDepth int) {
+	fmt.Fprintf(out, "  %s%sRequest plan%s %s%d GET requests plus calibration probes%s\n", bold, cyan, reset, dim, len(plan), reset)
+	for _, url := range plan {
+		fmt.Fprintf(out, "    %s\n", url)
+	}
+	if maxDepth > 0 {
+		fmt.Fprintf(out, "  %sDirectories found during the scan are also requested, down to depth %d%s\n", dim, maxDepth, reset)
+	}
+	fmt.Fprintln(out)
 }
 
 // PrintResult formats a single scan result with status badge and tags.
@@ -110,6 +188,15 @@ func PrintResult(result scanner.Result) {
 	if len(result.Technologies) > 0 {
 		tags = append(tags, fmt.Sprintf("%s%s[%s]%s", dim, blue, strings.Join(result.Technologies, ", "), reset))
 	}
+	if result.LoginPanel != "" {
+		tags = append(tags, fmt.Sprintf("%s%s🔐 %s%s", bold, yellow, result.LoginPanel, reset))
+	}
+	if result.CachePoisoningHint != "" {
+		tags = append(tags, fmt.Sprintf("%s%s⚠ %s%s", bold, yellow, result.CachePoisoningHint, reset))
+	}
+	if result.CalibrationDistance > 0 {
+		tags = append(tags, fmt.Sprintf("%scal %.2f%s", dim, result.CalibrationDistance, reset))
+	}
 
 	sizeStr := formatSize(result.Size)
 
@@ -118,7 +205,7 @@ func PrintResult(result scanner.Result) {
 		tagStr = "  " + strings.Join(tags, " ")
 	}
 
-	fmt.Printf("%s  %s%s%s  %s%s%s%s\n",
+	fmt.Fprintf(out, "%s  %s%s%s  %s%s%s%s\n",
 		badge,
 		dim, sizeStr, reset,
 		statusColor, result.URL, reset,
@@ -129,7 +216,7 @@ func PrintResult(result scanner.Result) {
 // It clears the progress line, prints the result, then the progress resumes on next tick.
 func printResultInline(result *scanner.Result) {
 	// Clear current progress line
-	fmt.Printf("\r%s", clearLine)
+	fmt.Fprintf(out, "\r%s", clearLine)
 
 	statusColor := statusToColor(result.StatusCode)
 	statusBg := statusToBg(result.StatusCode)
@@ -152,6 +239,15 @@ func printResultInline(result *scanner.Result) {
 	if len(result.Technologies) > 0 {
 		tags = append(tags, fmt.Sprintf("%s%s[%s]%s", dim, blue, strings.Join(result.Technologies, ", "), reset))
 	}
+	if result.LoginPanel != "" {
+		tags = append(tags, fmt.Sprintf("%s%s🔐 %s%s", bold, yellow, result.LoginPanel, reset))
+	}
+	if result.CachePoisoningHint != "" {
+		tags = append(tags, fmt.Sprintf("%s%s⚠ %s%s", bold, yellow, result.CachePoisoningHint, reset))
+	}
+	if result.CalibrationDistance > 0 {
+		tags = append(tags, fmt.Sprintf("%scal %.2f%s", dim, result.CalibrationDistance, reset))
+	}
 
 	sizeStr := formatSize(result.Size)
 
@@ -160,7 +256,7 @@ func printResultInline(result *scanner.Result) {
 		tagStr = "  " + strings.Join(tags, " ")
 	}
 
-	fmt.Printf("%s  %s%s%s  %s%s%s%s\n",
+	fmt.Fprintf(out, "%s  %s%s%s  %s%s%s%s\n",
 		badge,
 		dim, sizeStr, reset,
 		statusColor, result.URL, reset,
@@ -171,7 +267,11 @@ func printResultInline(result *scanner.Result) {
 // - Display live progress (spinner, progress bar, req/s, current URL)
 // - Print non-404 results inline as they are found
 // It replaces the old StartProgressReporter.
-func StartLiveUI(stats *scanner.Stats, eventCh <-chan scanner.ScanEvent, ctx context.Context) {
+//
+// recent is the number of recently tried URLs listed under the progress line
+// (0 shows only the current URL), and urlInterval is the minimum time between
+// URL updates, so fast scans do not flicker.
+func StartLiveUI(stats *scanner.Stats, eventCh <-chan scanner.ScanEvent, ctx context.Context, recent int, urlInterval time.Duration) {
 	ticker := time.NewTicker(150 * time.Millisecond)
 	defer ticker.Stop()
 
@@ -179,26 +279,50 @@ func StartLiveUI(stats *scanner.Stats, eventCh <-chan scanner.ScanEvent, ctx con
 	frame := 0
 	lastURL := ""
 
+	// The URL part of the display only changes every urlInterval.
+	var shownURL string
+	var shownRecent []string
+	var shownAt time.Time
+
+	// extraLines counts the recent-URL lines drawn below the progress line,
+	// which must be cleared before anything else is printed.
+	extraLines := 0
+	clearStatus := func() {
+		fmt.Fprintf(out, "\r%s", clearLine)
+		for ; extraLines > 0; extraLines-- {
+			fmt.Fprintf(out, "%s\r%s", moveUp, clearLine)
+		}
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
-			fmt.Printf("\r%s", clearLine)
+			clearStatus()
 			return
 
 		case event, ok := <-eventCh:
 			if !ok {
 				// Channel closed — scan complete.
-				fmt.Printf("\r%s", clearLine)
+				clearStatus()
 				return
 			}
 
 			switch event.Type {
 			case scanner.EventResultFound:
 				if event.Result != nil {
+					clearStatus()
 					printResultInline(event.Result)
 				}
 			case scanner.EventURLTrying:
 				lastURL = event.URL
+			case scanner.EventHostAbandoned:
+				clearStatus()
+				fmt.Fprintf(out, "  %s⚠  %s exceeded its error budget; skipping its remaining paths%s\n", yellow, event.URL, reset)
+			case scanner.EventWAFThrottled:
+				if event.Result != nil {
+					clearStatus()
+					fmt.Fprintf(out, "  %s🛡  %s detected on %s; throttling to --waf-rate and skipping bypass%s\n", yellow, event.Result.WAFDetected, event.URL, reset)
+				}
 			}
 
 		case <-ticker.C:
@@ -238,26 +362,51 @@ func StartLiveUI(stats *scanner.Stats, eventCh <-chan scanner.ScanEvent, ctx con
 				extraMetrics += fmt.Sprintf("  %s✗%d%s", red, errors, reset)
 			}
 
-			// Truncate URL for display
-			displayURL := lastURL
-			if displayURL == "" {
-				displayURL = stats.GetCurrentURL()
-			}
-			maxURLLen := 50
-			if len(displayURL) > maxURLLen {
-				displayURL = "…" + displayURL[len(displayURL)-maxURLLen+1:]
+			if time.Since(shownAt) >= urlInterval {
+				shownAt = time.Now()
+				shownURL = lastURL
+				if shownURL == "" {
+					shownURL = stats.GetCurrentURL()
+				}
+				if recent > 0 {
+					shownRecent = stats.RecentURLs(recent)
+				}
 			}
 
 			// Line 1: Progress bar + metrics
-			fmt.Printf("\r%s", clearLine)
-			fmt.Printf("  %s%s %s%s%s %s%.0f%%%s  %s%d%s req/s  Found: %s%s  %s%s%s",
+			clearStatus()
+			fmt.Fprintf(out, "  %s%s %s%s%s %s%.0f%%%s  %s%d%s req/s  Found: %s%s  %s%s%s",
 				cyan, s,
 				dim, bar, reset,
 				bold, progress, reset,
 				dim, int(reqPerSec), reset,
 				foundStr,
 				extraMetrics,
-				dim, displayURL, reset)
+				dim, truncateURL(shownURL, 50), reset)
+
+			// Optional recent-URL lines below it.
+			for _, u := range shownRecent {
+				fmt.Fprintf(out, "\n    %s%s%s", dim, truncateURL(u, 70), reset)
+				extraLines++
+			}
+		}
+	}
+}
+
+// truncateURL shortens u to at most max bytes, keeping the end.
+func truncateURL(u string, max int) string {
+	if len(u) > max {
+		return "…" + u[len(u)-max+1:]
+	}
+	return u
+}
+
+// PrintQuietResults writes the URL of each finding to w, one per line, as
+// the scan reports it (--quiet). It returns once the engine closes eventCh.
+func PrintQuietResults(w io.Writer, eventCh <-chan scanner.ScanEvent) {
+	for event := range eventCh {
+		if event.Type == scanner.EventResultFound && event.Result != nil {
+			fmt.Fprintln(w, event.Result.URL)
 		}
 	}
 }
@@ -274,7 +423,7 @@ func StartProgressReporter(stats *scanner.Stats, ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
-			fmt.Print("\r\033[K")
+			fmt.Fprint(out, "\r\033[K")
 			return
 		case <-ticker.C:
 			elapsed := time.Since(stats.StartTime).Seconds()
@@ -318,7 +467,7 @@ func StartProgressReporter(stats *scanner.Stats, ctx context.Context) {
 				errStr = fmt.Sprintf("  %s✗ %d%s", red, errors, reset)
 			}
 
-			fmt.Printf("\r  %s%s %s%s%s %s%.0f%%%s  %s%d%s req/s  Found: %s%s%s%s",
+			fmt.Fprintf(out, "\r  %s%s %s%s%s %s%.0f%%%s  %s%d%s req/s  Found: %s%s%s%s",
 				cyan, s,
 				dim, bar, reset,
 				bold, progress, reset,
@@ -330,7 +479,8 @@ func StartProgressReporter(stats *scanner.Stats, ctx context.Context) {
 }
 
 // PrintSummary displays the final scan summary with actionable metrics.
-func PrintSummary(stats *scanner.Stats) {
+// The latency line is computed from the reported results.
+func PrintSummary(stats *scanner.Stats, results []scanner.Result) {
 	elapsed := time.Since(stats.StartTime)
 	processed := stats.GetProcessed()
 	var reqPerSec float64
@@ -344,26 +494,213 @@ func PrintSummary(stats *scanner.Stats) {
 		errorRate = float64(errors) / float64(processed) * 100
 	}
 
-	fmt.Println()
-	fmt.Printf("  %s%s✔  Scan Complete%s\n", bold, green, reset)
-	fmt.Printf("  %s──────────────────────────────────────%s\n", dim, reset)
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "  %s%s✔  Scan Complete%s\n", bold, green, reset)
+	fmt.Fprintf(out, "  %s──────────────────────────────────────%s\n", dim, reset)
 
-	fmt.Printf("  %s%-14s%s %s%d%s\n", dim, "Requests", reset, white, processed, reset)
-	fmt.Printf("  %s%-14s%s %s%s%d%s\n", dim, "Findings", reset, bold, green, stats.GetFound(), reset)
+	fmt.Fprintf(out, "  %s%-14s%s %s%d%s\n", dim, "Requests", reset, white, processed, reset)
+	actionableColor := white
+	if stats.GetActionable() > 0 {
+		actionableColor = red
+	}
+	fmt.Fprintf(out, "  %s%-14s%s %s%s%d%s\n", dim, "Actionable", reset, bold, actionableColor, stats.GetActionable(), reset)
+	fmt.Fprintf(out, "  %s%-14s%s %s%s%d%s\n", dim, "Findings", reset, bold, green, stats.GetFound(), reset)
+	if suppressed := stats.GetSuppressed(); suppressed > 0 {
+		fmt.Fprintf(out, "  %s%-14s%s %s%d informational hidden (--actionable-only)%s\n", dim, "Suppressed", reset, dim, suppressed, reset)
+	}
 
 	if stats.GetSecrets() > 0 {
-		fmt.Printf("  %s%-14s%s %s%s%d%s\n", dim, "Secrets", reset, bold, magenta, stats.GetSecrets(), reset)
+		fmt.Fprintf(out, "  %s%-14s%s %s%s%d%s\n", dim, "Secrets", reset, bold, magenta, stats.GetSecrets(), reset)
 	}
 	if stats.GetWAFHits() > 0 {
-		fmt.Printf("  %s%-14s%s %s%s%d%s\n", dim, "WAF Hits", reset, bold, yellow, stats.GetWAFHits(), reset)
+		fmt.Fprintf(out, "  %s%-14s%s %s%s%d%s\n", dim, "WAF Hits", reset, bold, yellow, stats.GetWAFHits(), reset)
 	}
 	if errors > 0 {
-		fmt.Printf("  %s%-14s%s %s%s%d%s  %s(%.1f%%)%s\n", dim, "Errors", reset, bold, red, errors, reset, dim, errorRate, reset)
+		fmt.Fprintf(out, "  %s%-14s%s %s%s%d%s  %s(%.1f%%)%s\n", dim, "Errors", reset, bold, red, errors, reset, dim, errorRate, reset)
+	}
+	if flaky := stats.GetFlaky(); flaky > 0 {
+		fmt.Fprintf(out, "  %s%-14s%s %s%d%s\n", dim, "Flaky", reset, yellow, flaky, reset)
+	}
+	if bodyTimeouts := stats.GetBodyTimeouts(); bodyTimeouts > 0 {
+		fmt.Fprintf(out, "  %s%-14s%s %s%d%s\n", dim, "Body Timeouts", reset, red, bodyTimeouts, reset)
+	}
+
+	fmt.Fprintf(out, "  %s%-14s%s %s%s%s\n", dim, "Duration", reset, white, elapsed.Round(time.Millisecond), reset)
+	fmt.Fprintf(out, "  %s%-14s%s %s%.0f req/s%s\n", dim, "Speed", reset, white, reqPerSec, reset)
+	if latency := reporting.SummarizeLatency(results); latency.Count > 0 {
+		fmt.Fprintf(out, "  %s%-14s%s %savg %dms · p50 %dms · p95 %dms · p99 %dms · max %dms%s\n",
+			dim, "Latency", reset, white, latency.Avg, latency.P50, latency.P95, latency.P99, latency.Max, reset)
+	}
+
+	if bypasses := stats.GetBypassCounts(); len(bypasses) > 0 {
+		names := make([]string, 0, len(bypasses))
+		for name := range bypasses {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if bypasses[names[i]] != bypasses[names[j]] {
+				return bypasses[names[i]] > bypasses[names[j]]
+			}
+			return names[i] < names[j]
+		})
+
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "  %s%sBypass successes by strategy%s\n", bold, cyan, reset)
+		for _, name := range names {
+			fmt.Fprintf(out, "  %s%-22s%s %s%d%s\n", dim, name, reset, white, bypasses[name], reset)
+		}
+	}
+
+	if secretTypes := stats.GetSecretTypeCounts(); len(secretTypes) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "  %s%sSecrets by type%s\n", bold, magenta, reset)
+		for _, st := range secretTypes {
+			fmt.Fprintf(out, "  %s%-22s%s %s×%d%s  %s%s%s\n", dim, st.Type, reset, white, st.Count, reset, severityColor(st.Severity), st.Severity, reset)
+		}
+	}
+
+	if abandoned := stats.GetAbandonedHosts(); len(abandoned) > 0 {
+		hosts := make([]string, 0, len(abandoned))
+		for host := range abandoned {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "  %s%sAbandoned hosts%s\n", bold, red, reset)
+		for _, host := range hosts {
+			fmt.Fprintf(out, "  %s%s%s  abandoned after %d errors\n", white, host, reset, abandoned[host])
+		}
+	}
+
+	if autoSizes := stats.GetAutoFilteredSizes(); len(autoSizes) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "  %s%sAuto-filtered sizes (--exclude-length-auto)%s\n", bold, yellow, reset)
+		for _, size := range autoSizes {
+			fmt.Fprintf(out, "  %s%d, %d bytes%s  %d responses dropped\n", white, size.StatusCode, size.Size, reset, size.Dropped)
+		}
+	}
+
+	if wafHosts := stats.GetWAFHosts(); len(wafHosts) > 0 {
+		hosts := make([]string, 0, len(wafHosts))
+		for host := range wafHosts {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "  %s%sWAF-throttled hosts%s\n", bold, yellow, reset)
+		for _, host := range hosts {
+			fmt.Fprintf(out, "  %s%s%s  %s; bypass skipped\n", white, host, reset, wafHosts[host])
+		}
+	}
+
+	if targetHosts := stats.GetTargetHosts(); len(targetHosts) > 0 {
+		targets := make([]string, 0, len(targetHosts))
+		sharers := make(map[string]int)
+		for target, info := range targetHosts {
+			targets = append(targets, target)
+			for _, ip := range info.IPs {
+				sharers[ip]++
+			}
+		}
+		sort.Strings(targets)
+
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "  %s%sTarget hosts%s\n", bold, cyan, reset)
+		for _, target := range targets {
+			info := targetHosts[target]
+			if info.Error != "" {
+				fmt.Fprintf(out, "  %s%s%s  %s%s%s\n", white, target, reset, red, info.Error, reset)
+				continue
+			}
+			ips := make([]string, 0, len(info.IPs))
+			for _, ip := range info.IPs {
+				if n := sharers[ip]; n > 1 {
+					ip += fmt.Sprintf(" %s(shared by %d targets)%s", yellow, n, reset)
+				}
+				ips = append(ips, ip)
+			}
+			line := strings.Join(ips, ", ")
+			if len(info.PTR) > 0 {
+				line += fmt.Sprintf("  %s%s%s", dim, strings.Join(info.PTR, ", "), reset)
+			}
+			fmt.Fprintf(out, "  %s%s%s  %s\n", white, target, reset, line)
+		}
+	}
+
+	if targetErrors := stats.GetTargetErrors(); len(targetErrors) > 0 {
+		targets := make([]string, 0, len(targetErrors))
+		for target := range targetErrors {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "  %s%sSkipped targets%s\n", bold, red, reset)
+		for _, target := range targets {
+			fmt.Fprintf(out, "  %s%s%s  %s\n", white, target, reset, targetErrors[target])
+		}
+	}
+	fmt.Fprintln(out)
+}
+
+// PrintBodyClusters lists the largest groups of findings that returned an
+// identical body — usually one boilerplate page served under many paths.
+func PrintBodyClusters(clusters []reporting.BodyCluster, limit int) {
+	if len(clusters) == 0 {
+		return
 	}
 
-	fmt.Printf("  %s%-14s%s %s%s%s\n", dim, "Duration", reset, white, elapsed.Round(time.Millisecond), reset)
-	fmt.Printf("  %s%-14s%s %s%.0f req/s%s\n", dim, "Speed", reset, white, reqPerSec, reset)
-	fmt.Println()
+	fmt.Fprintf(out, "  %s%sDuplicate bodies%s\n", bold, cyan, reset)
+	for i, c := range clusters {
+		if i == limit {
+			fmt.Fprintf(out, "  %s… %d more clusters%s\n", dim, len(clusters)-limit, reset)
+			break
+		}
+		sample := c.URLs[0]
+		fmt.Fprintf(out, "  %s%-18s%s %s%d URLs%s  %se.g. %s%s\n", dim, c.Hash, reset, yellow, c.Count, reset, dim, sample, reset)
+	}
+	fmt.Fprintln(out)
+}
+
+// PrintTree prints the discovered directory tree, one block per target.
+func PrintTree(root *reporting.TreeNode) {
+	if len(root.Children) == 0 {
+		return
+	}
+
+	fmt.Fprintf(out, "  %s%sDirectory tree%s\n", bold, cyan, reset)
+	for _, line := range strings.Split(strings.TrimRight(reporting.RenderTree(root), "\n"), "\n") {
+		fmt.Fprintf(out, "  %s\n", line)
+	}
+	fmt.Fprintln(out)
+}
+
+// PrintMonitorDiff lists the findings a -monitor run found added (+),
+// removed (-) or changed (~) since the previous run.
+func PrintMonitorDiff(m reporting.MonitorResult) {
+	for _, target := range m.Baselined {
+		fmt.Fprintf(out, "  %sMonitor baseline recorded for %s%s\n", dim, target, reset)
+	}
+
+	d := m.Diff
+	if d.Empty() {
+		fmt.Fprintf(out, "  %s%sNo changes since last run%s\n\n", bold, green, reset)
+		return
+	}
+
+	fmt.Fprintf(out, "  %s%sChanges since last run%s  %s+%d -%d ~%d%s\n", bold, cyan, reset, dim, len(d.Added), len(d.Removed), len(d.Changed), reset)
+	for _, r := range d.Added {
+		fmt.Fprintf(out, "  %s+ %d%s %s %s\n", green, r.StatusCode, reset, r.Method, r.URL)
+	}
+	for _, r := range d.Removed {
+		fmt.Fprintf(out, "  %s- %d%s %s %s\n", red, r.StatusCode, reset, r.Method, r.URL)
+	}
+	for _, c := range d.Changed {
+		fmt.Fprintf(out, "  %s~ %d→%d%s %s %s %s(%s→%s)%s\n", yellow, c.Before.StatusCode, c.After.StatusCode, reset, c.After.Method, c.After.URL, dim, c.Before.Severity, c.After.Severity, reset)
+	}
+	fmt.Fprintln(out)
 }
 
 func statusToColor(code int) string {
@@ -381,6 +718,17 @@ func statusToColor(code int) string {
 	}
 }
 
+func severityColor(severity string) string {
+	switch severity {
+	case scanner.SeverityCritical, scanner.SeverityHigh:
+		return red
+	case scanner.SeverityMedium:
+		return yellow
+	default:
+		return dim
+	}
+}
+
 func statusToBg(code int) string {
 	switch {
 	case code >= 200 && code < 300:
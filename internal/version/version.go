@@ -0,0 +1,83 @@
+// Package version holds the build metadata shown by --version, the banner
+// and report metadata. Release builds set it at link time:
+//
+//	go build -ldflags "\
+//	  -X github.com/capsaicin/scanner/internal/version.Version=3.1.0 \
+//	  -X github.com/capsaicin/scanner/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/capsaicin/scanner/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)" \
+//	  ./cmd/capsaicin
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+var (
+	// Version is the release version, without a leading "v".
+	Version = "3.1.0"
+	// Commit is the source revision. When not set by -ldflags, the VCS
+	// revision embedded by the Go toolchain is used if available.
+	Commit = ""
+	// BuildDate is the build time in RFC 3339 form.
+	BuildDate = ""
+)
+
+// String returns the --version output: version, commit, build date, Go
+// version and platform, one per line.
+func String() string {
+	commit, date := Commit, BuildDate
+	if commit == "" || date == "" {
+		vcsCommit, vcsDate := vcsInfo()
+		if commit == "" {
+			commit = vcsCommit
+		}
+		if date == "" {
+			date = vcsDate
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "capsaicin %s\n", Version)
+	fmt.Fprintf(&b, "  commit:   %s\n", orUnknown(commit))
+	fmt.Fprintf(&b, "  built:    %s\n", orUnknown(date))
+	fmt.Fprintf(&b, "  go:       %s\n", runtime.Version())
+	fmt.Fprintf(&b, "  platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	return b.String()
+}
+
+// vcsInfo reads the revision and commit time the Go toolchain embeds when
+// building inside a VCS checkout.
+func vcsInfo() (revision, date string) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "", ""
+	}
+	modified := false
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.time":
+			date = s.Value
+		case "vcs.modified":
+			modified = s.Value == "true"
+		}
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if revision != "" && modified {
+		revision += "-dirty"
+	}
+	return revision, date
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
@@ -0,0 +1,25 @@
+package version
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	defer func(v, c, d string) { Version, Commit, BuildDate = v, c, d }(Version, Commit, BuildDate)
+	Version, Commit, BuildDate = "9.9.9", "abc1234", "2024-01-02T03:04:05Z"
+
+	out := String()
+	for _, want := range []string{
+		"capsaicin 9.9.9",
+		"commit:   abc1234",
+		"built:    2024-01-02T03:04:05Z",
+		runtime.Version(),
+		runtime.GOOS + "/" + runtime.GOARCH,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in version output, got:\n%s", want, out)
+		}
+	}
+}